@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/malagant/fluxcli/pkg/audit"
+	"github.com/malagant/fluxcli/pkg/k8s"
+)
+
+var (
+	auditCluster string
+	auditKind    string
+	auditName    string
+	auditAction  string
+	auditUser    string
+	auditResult  string
+	auditSince   time.Duration
+	auditTail    int
+	auditOutput  string
+)
+
+// auditActions are the operations recorded in the audit log
+var auditActions = []string{"suspend", "resume", "reconcile"}
+
+// auditCmd queries the audit log
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Show who suspended, resumed or reconciled resources",
+	Long: `Show the audit log of the suspend, resume and reconcile operations run from
+this machine, oldest first. Every entry records the time, the operating system
+user, the Kubernetes user the cluster authenticated, the cluster, kind,
+namespace and name of the object, the action and whether it succeeded, failed
+or was blocked, e.g. on a read-only cluster.
+
+The log is written to audit.path, ~/.fluxcli/audit.log by default, while
+audit.enabled is set. With audit.annotate, suspending an object also records
+who suspended it and when in its fluxcli.io/suspended-by and
+fluxcli.io/suspended-at annotations.`,
+	Example: `  fluxcli audit --since 24h
+  fluxcli audit --cluster production --action suspend
+  fluxcli audit --kind ks --name apps -n flux-system -o json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		filter := audit.Filter{
+			Cluster:   auditCluster,
+			Namespace: namespace,
+			Name:      auditName,
+			User:      auditUser,
+		}
+		if auditKind != "" {
+			resourceType, err := k8s.ParseResourceType(auditKind)
+			if err != nil {
+				return err
+			}
+			filter.Kind = string(resourceType)
+		}
+		if auditAction != "" {
+			if !slices.Contains(auditActions, auditAction) {
+				return fmt.Errorf("unknown action %q, expected %s", auditAction, strings.Join(auditActions, ", "))
+			}
+			filter.Action = auditAction
+		}
+		if auditResult != "" {
+			result, err := audit.ParseResult(auditResult)
+			if err != nil {
+				return err
+			}
+			filter.Result = result
+		}
+		if auditSince > 0 {
+			filter.Since = time.Now().Add(-auditSince)
+		}
+		if auditOutput != "text" && auditOutput != "json" {
+			return fmt.Errorf("unknown output format %q, expected text or json", auditOutput)
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+		path, err := cfg.AuditPath()
+		if err != nil {
+			return fmt.Errorf("failed to locate the audit log: %w", err)
+		}
+
+		entries, malformed, err := audit.Read(path, filter)
+		if err != nil {
+			return err
+		}
+		if malformed > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: skipped %d malformed line(s) of %s\n", malformed, path)
+		}
+		if auditTail > 0 && len(entries) > auditTail {
+			entries = entries[len(entries)-auditTail:]
+		}
+
+		if auditOutput == "json" {
+			return printAuditJSON(os.Stdout, entries)
+		}
+		if len(entries) == 0 {
+			fmt.Println("No audit entries found")
+			return nil
+		}
+		printAudit(os.Stdout, entries)
+		return nil
+	},
+}
+
+// printAudit prints audit entries as a table
+func printAudit(out io.Writer, entries []audit.Entry) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tUSER\tIDENTITY\tCLUSTER\tACTION\tOBJECT\tRESULT\tDETAILS")
+	for _, entry := range entries {
+		details := entry.Reason
+		if entry.Error != "" {
+			details = entry.Error
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s/%s/%s\t%s\t%s\n",
+			entry.Time.Local().Format(time.DateTime), entry.User, valueOr(entry.Identity, "-"), entry.Cluster,
+			entry.Action, entry.Kind, entry.Namespace, entry.Name, entry.Result,
+			strings.ReplaceAll(details, "\n", " "))
+	}
+	w.Flush()
+}
+
+// printAuditJSON prints audit entries as JSON Lines, as they are logged
+func printAuditJSON(out io.Writer, entries []audit.Entry) error {
+	encoder := json.NewEncoder(out)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func init() {
+	auditCmd.Flags().StringVar(&auditCluster, "cluster", "", "only show changes of this cluster")
+	auditCmd.Flags().StringVar(&auditKind, "kind", "", "only show changes of this kind, such as kustomization or hr")
+	auditCmd.Flags().StringVar(&auditName, "name", "", "only show changes of objects with this name")
+	auditCmd.Flags().StringVar(&auditAction, "action", "", "only show this action: suspend, resume or reconcile")
+	auditCmd.Flags().StringVar(&auditUser, "user", "", "only show changes by this operating system or Kubernetes user")
+	auditCmd.Flags().StringVar(&auditResult, "result", "", "only show changes with this result: succeeded, failed or blocked")
+	auditCmd.Flags().DurationVar(&auditSince, "since", 0, "only show changes made within this duration, such as 24h")
+	auditCmd.Flags().IntVar(&auditTail, "tail", 0, "only show the last entries, all when zero")
+	auditCmd.Flags().StringVarP(&auditOutput, "output", "o", "text", "output format: text or json")
+
+	rootCmd.AddCommand(auditCmd)
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/malagant/fluxcli/internal/config"
+	"github.com/malagant/fluxcli/pkg/k8s"
+)
+
+var (
+	checkCluster       string
+	checkAllClusters   bool
+	checkFluxNamespace string
+	checkTimeout       time.Duration
+)
+
+// checkCmd reports the Flux installation of clusters
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check the Flux installation of clusters",
+	Long: fmt.Sprintf(`Check the Flux installation of the current cluster, like flux check: the
+Kubernetes and Flux versions, the controller deployments in flux-system with
+their images, and the served Flux API versions, including those FluxCLI reads.
+
+Optional controllers, such as the image automation controllers, are reported
+but not required. The command exits with %d when a cluster has problems.`, k8s.ExitUnhealthy),
+	Example: `  fluxcli check
+  fluxcli check --cluster production
+  fluxcli check --all-clusters`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		if checkAllClusters && checkCluster != "" {
+			return fmt.Errorf("--cluster and --all-clusters cannot be combined")
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+		clusters := []string{checkCluster}
+		if checkAllClusters {
+			clusters = configuredClusterNames(cfg)
+		}
+
+		var failed []string
+		for i, cluster := range clusters {
+			name := cluster
+			if name == "" {
+				name = cfg.CurrentCluster
+			}
+			if i > 0 {
+				fmt.Println()
+			}
+			if len(clusters) > 1 {
+				fmt.Printf("Cluster %s\n", name)
+			}
+
+			install, err := discoverFlux(cmd.Context(), cfg, cluster)
+			if err != nil {
+				fmt.Printf("✗ %v\n", err)
+				failed = append(failed, name)
+				continue
+			}
+			printCheck(os.Stdout, install)
+			if !install.Healthy() {
+				failed = append(failed, name)
+			}
+		}
+
+		if len(failed) > 0 {
+			return &ExitError{
+				Code: k8s.ExitUnhealthy,
+				Err:  fmt.Errorf("check failed for %s", strings.Join(failed, ", ")),
+			}
+		}
+		return nil
+	},
+}
+
+// discoverFlux inspects the Flux installation of a configured cluster, the
+// current one when cluster is empty
+func discoverFlux(ctx context.Context, cfg *config.Config, cluster string) (*k8s.FluxInstall, error) {
+	kubeconfigPath, kubeContext, ns, auth, err := clusterTarget(cfg, cluster)
+	if err != nil {
+		return nil, err
+	}
+	client, err := k8s.NewClient(kubeconfigPath, kubeContext, ns, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+	return client.DiscoverFlux(ctx, checkFluxNamespace)
+}
+
+// printCheck prints an installation in the style of flux check
+func printCheck(w io.Writer, install *k8s.FluxInstall) {
+	fmt.Fprintln(w, "► checking versions")
+	fmt.Fprintf(w, "✔ Kubernetes %s\n", install.KubernetesVersion)
+	switch {
+	case !install.Installed():
+		fmt.Fprintf(w, "✗ Flux is not installed in namespace %s\n", install.Namespace)
+		return
+	case install.Version != "":
+		fmt.Fprintf(w, "✔ Flux %s\n", install.Version)
+	default:
+		fmt.Fprintln(w, "✔ Flux (version unknown)")
+	}
+
+	fmt.Fprintln(w, "► checking controllers")
+	for _, controller := range install.Controllers {
+		switch {
+		case !controller.Installed && controller.Optional:
+			fmt.Fprintf(w, "- %s: not installed (optional)\n", controller.Name)
+		case !controller.Installed:
+			fmt.Fprintf(w, "✗ %s: not installed\n", controller.Name)
+		case !controller.Ready:
+			fmt.Fprintf(w, "✗ %s: %s\n", controller.Name, controller.Message)
+			fmt.Fprintf(w, "► %s\n", controller.Image)
+		default:
+			fmt.Fprintf(w, "✔ %s: deployment ready\n", controller.Name)
+			fmt.Fprintf(w, "► %s\n", controller.Image)
+		}
+	}
+
+	fmt.Fprintln(w, "► checking APIs")
+	for _, api := range install.APIs {
+		fmt.Fprintf(w, "✔ %s/%s\n", api.Group, strings.Join(api.Versions, ", "))
+	}
+
+	for _, problem := range install.APIProblems() {
+		fmt.Fprintf(w, "✗ %s\n", problem)
+	}
+
+	if install.Healthy() {
+		fmt.Fprintln(w, "✔ all checks passed")
+	} else {
+		fmt.Fprintf(w, "✗ %d problem(s) found\n", len(install.Problems()))
+	}
+}
+
+func init() {
+	checkCmd.Flags().StringVar(&checkCluster, "cluster", "", "configured cluster to check (defaults to the current cluster)")
+	checkCmd.Flags().BoolVar(&checkAllClusters, "all-clusters", false, "check every configured cluster")
+	checkCmd.Flags().StringVar(&checkFluxNamespace, "flux-namespace", k8s.FluxNamespace, "namespace Flux is installed in")
+	checkCmd.Flags().DurationVar(&checkTimeout, "timeout", 30*time.Second, "timeout for inspecting a cluster")
+	rootCmd.AddCommand(checkCmd)
+}
@@ -0,0 +1,571 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/malagant/fluxcli/internal/config"
+	"github.com/malagant/fluxcli/pkg/core"
+	"github.com/malagant/fluxcli/pkg/k8s"
+	"github.com/spf13/cobra"
+)
+
+var (
+	addCluster          config.ClusterConfig
+	addSkipTest         bool
+	addSetDefault       bool
+	importInclude       []string
+	importExclude       []string
+	importWithoutFlux   bool
+	importPrune         bool
+	importDryRun        bool
+	clusterProbeTimeout time.Duration
+)
+
+// clusterCmd groups the cluster management commands
+var clusterCmd = &cobra.Command{
+	Use:   "cluster",
+	Short: "Manage the clusters configured in FluxCLI",
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		// Failures are reported per cluster, usage would only add noise
+		cmd.SilenceUsage = true
+	},
+}
+
+// clusterAddCmd adds or updates a cluster
+var clusterAddCmd = &cobra.Command{
+	Use:   "add [name]",
+	Short: "Add a cluster to the configuration",
+	Long: `Add a cluster to the configuration, or update it if a cluster with the same
+name already exists. The kubeconfig and context are validated and the cluster
+is tested before it is saved unless --skip-test is given.
+
+Without a name and flags, the cluster details are prompted for interactively.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+
+		cluster := addCluster
+		if len(args) > 0 {
+			cluster.Name = args[0]
+		}
+		if cluster.Name == "" {
+			if !isTerminal(os.Stdin) {
+				return fmt.Errorf("cluster name is required")
+			}
+			if cluster, err = promptCluster(cluster); err != nil {
+				return err
+			}
+		}
+		if cluster.Context == "" {
+			cluster.Context = cluster.Name
+		}
+
+		if err := validateClusterConfig(cluster); err != nil {
+			return err
+		}
+
+		if !addSkipTest {
+			result := testCluster(cmd.Context(), cluster)
+			printTestResults([]clusterTestResult{result})
+			if result.err != nil {
+				return fmt.Errorf("cluster %s failed the connection test (use --skip-test to add it anyway)", cluster.Name)
+			}
+		}
+
+		cfg.AddCluster(cluster)
+		if addSetDefault {
+			cfg.Defaults.Cluster = cluster.Name
+		}
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save configuration: %w", err)
+		}
+
+		fmt.Printf("Cluster %s saved\n", cluster.Name)
+		return nil
+	},
+}
+
+// clusterRemoveCmd removes a cluster
+var clusterRemoveCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a cluster from the configuration",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+
+		if !cfg.RemoveCluster(args[0]) {
+			return fmt.Errorf("cluster %s not found", args[0])
+		}
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save configuration: %w", err)
+		}
+
+		fmt.Printf("Cluster %s removed\n", args[0])
+		return nil
+	},
+}
+
+// clusterListCmd lists the configured clusters
+var clusterListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List the configured clusters",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+
+		if len(cfg.Clusters) == 0 {
+			fmt.Println("No clusters configured, use 'fluxcli cluster add' or 'fluxcli cluster import'")
+			return nil
+		}
+
+		missing := make(map[string]bool)
+		if missingClusters, err := cfg.MissingContexts(); err == nil {
+			for _, cluster := range missingClusters {
+				missing[cluster.Name] = true
+			}
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "DEFAULT\tNAME\tCONTEXT\tKUBECONFIG\tNAMESPACE\tDESCRIPTION")
+		for _, cluster := range cfg.Clusters {
+			marker := ""
+			if cluster.Name == cfg.Defaults.Cluster {
+				marker = "*"
+			}
+			contextName := cluster.Context
+			if missing[cluster.Name] {
+				contextName += " (missing)"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", marker, cluster.Name, contextName,
+				valueOr(cluster.Kubeconfig, "<default>"), valueOr(cluster.Namespace, cfg.Defaults.Namespace), cluster.Description)
+		}
+		return w.Flush()
+	},
+}
+
+// clusterTestCmd tests the connection to clusters
+var clusterTestCmd = &cobra.Command{
+	Use:   "test [name...]",
+	Short: "Test the connection to configured clusters",
+	Long:  "Test the connection to the given clusters, or to every configured cluster when no name is given.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+
+		clusters := cfg.Clusters
+		if len(args) > 0 {
+			clusters = nil
+			for _, name := range args {
+				cluster, ok := cfg.GetCluster(name)
+				if !ok {
+					return fmt.Errorf("cluster %s not found", name)
+				}
+				clusters = append(clusters, *cluster)
+			}
+		}
+		if len(clusters) == 0 {
+			return fmt.Errorf("no clusters configured")
+		}
+
+		results := make([]clusterTestResult, len(clusters))
+		var wg sync.WaitGroup
+		for i, cluster := range clusters {
+			wg.Add(1)
+			go func(i int, cluster config.ClusterConfig) {
+				defer wg.Done()
+				results[i] = testCluster(cmd.Context(), cluster)
+			}(i, cluster)
+		}
+		wg.Wait()
+
+		printTestResults(results)
+
+		failed := 0
+		for _, result := range results {
+			if result.err != nil {
+				failed++
+			}
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d cluster(s) failed the connection test", failed, len(results))
+		}
+		return nil
+	},
+}
+
+// clusterSetDefaultCmd sets the cluster FluxCLI starts on
+var clusterSetDefaultCmd = &cobra.Command{
+	Use:   "set-default <name>",
+	Short: "Set the cluster FluxCLI starts on",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+
+		if _, ok := cfg.GetCluster(args[0]); !ok {
+			return fmt.Errorf("cluster %s not found", args[0])
+		}
+		cfg.Defaults.Cluster = args[0]
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save configuration: %w", err)
+		}
+
+		fmt.Printf("Default cluster set to %s\n", args[0])
+		return nil
+	},
+}
+
+// clusterImportCmd imports kubeconfig contexts as clusters
+var clusterImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import kubeconfig contexts as clusters",
+	Long: `Import contexts from the merged kubeconfig as FluxCLI clusters.
+
+Every selected context is probed for FluxCD CRDs and only contexts running Flux
+are imported unless --without-flux is given. Include and exclude patterns are
+globs, or regular expressions when wrapped in slashes (e.g. '/^prod-/').
+When no patterns are given the discovery settings from the config file apply.
+
+Configured clusters whose context no longer exists in kubeconfig are flagged
+and removed with --prune.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+
+		contexts, err := config.ListContexts(kubeconfig)
+		if err != nil {
+			return err
+		}
+
+		include, exclude := importInclude, importExclude
+		if len(include) == 0 && len(exclude) == 0 {
+			include, exclude = cfg.Discovery.Include, cfg.Discovery.Exclude
+		}
+
+		var selected []string
+		for _, name := range contexts {
+			matched, err := config.MatchContext(name, include, exclude)
+			if err != nil {
+				return err
+			}
+			if matched {
+				selected = append(selected, name)
+			}
+		}
+
+		results := probeContexts(cmd.Context(), kubeconfig, selected)
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "CONTEXT\tFLUX\tACTION\tMESSAGE")
+		imported, skipped, removed := 0, 0, 0
+		for _, result := range results {
+			action, message := "skipped", ""
+			switch {
+			case result.err != nil:
+				message = result.err.Error()
+				if importWithoutFlux {
+					action = "imported"
+				}
+			case !result.flux && !importWithoutFlux:
+				message = "no FluxCD CRDs found"
+			default:
+				action = "imported"
+			}
+
+			if existing := clusterForContext(cfg, kubeconfig, result.context); existing != "" {
+				action, message = "exists", fmt.Sprintf("configured as %s", existing)
+			} else if action == "imported" {
+				cfg.AddCluster(config.ClusterConfig{
+					Name:       result.context,
+					Context:    result.context,
+					Kubeconfig: kubeconfig,
+				})
+				imported++
+			} else {
+				skipped++
+			}
+
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", result.context, yesNo(result.flux, result.err), action, message)
+		}
+		w.Flush()
+
+		missing, err := cfg.MissingContexts()
+		if err != nil {
+			return err
+		}
+		for _, cluster := range missing {
+			if importPrune {
+				cfg.RemoveCluster(cluster.Name)
+				removed++
+				fmt.Printf("Removed cluster %s: context %s no longer exists in kubeconfig\n", cluster.Name, cluster.Context)
+			} else {
+				fmt.Printf("Warning: cluster %s refers to context %s which no longer exists in kubeconfig (use --prune to remove)\n", cluster.Name, cluster.Context)
+			}
+		}
+
+		summary := fmt.Sprintf("Imported %d, skipped %d, removed %d cluster(s)", imported, skipped, removed)
+		if importDryRun {
+			fmt.Printf("%s (dry run, configuration not saved)\n", summary)
+			return nil
+		}
+		if imported > 0 || removed > 0 {
+			if err := cfg.Save(); err != nil {
+				return fmt.Errorf("failed to save configuration: %w", err)
+			}
+		}
+		fmt.Println(summary)
+
+		return nil
+	},
+}
+
+func init() {
+	clusterAddCmd.Flags().StringVar(&addCluster.Name, "name", "", "cluster name")
+	clusterAddCmd.Flags().StringVar(&addCluster.Context, "context", "", "kubeconfig context (defaults to the cluster name)")
+	clusterAddCmd.Flags().StringVar(&addCluster.Kubeconfig, "kubeconfig", "", "kubeconfig file (defaults to $KUBECONFIG, then ~/.kube/config)")
+	clusterAddCmd.Flags().StringVar(&addCluster.Namespace, "namespace", "", "default namespace for the cluster")
+	clusterAddCmd.Flags().StringVar(&addCluster.Color, "color", "", "color used to highlight the cluster")
+	clusterAddCmd.Flags().StringVar(&addCluster.Description, "description", "", "cluster description")
+	clusterAddCmd.Flags().BoolVar(&addCluster.ReadOnly, "read-only", false, "block suspend, resume and reconcile on the cluster")
+	clusterAddCmd.Flags().BoolVar(&addCluster.Protected, "protected", false, "ask to type the cluster name before suspend, resume and reconcile")
+	clusterAddCmd.Flags().StringVar(&addCluster.As, "as", "", "user or service account to impersonate")
+	clusterAddCmd.Flags().StringSliceVar(&addCluster.AsGroups, "as-group", nil, "group to impersonate, can be repeated")
+	clusterAddCmd.Flags().StringVar(&addCluster.TokenFile, "token-file", "", "bearer token file used instead of the kubeconfig credentials")
+	clusterAddCmd.Flags().BoolVar(&addSkipTest, "skip-test", false, "save the cluster without testing the connection")
+	clusterAddCmd.Flags().BoolVar(&addSetDefault, "default", false, "make this the default cluster")
+
+	clusterImportCmd.Flags().StringSliceVar(&importInclude, "include", nil, "only import contexts matching these patterns")
+	clusterImportCmd.Flags().StringSliceVar(&importExclude, "exclude", nil, "skip contexts matching these patterns")
+	clusterImportCmd.Flags().BoolVar(&importWithoutFlux, "without-flux", false, "also import contexts without FluxCD CRDs or that cannot be probed")
+	clusterImportCmd.Flags().BoolVar(&importPrune, "prune", false, "remove clusters whose context no longer exists in kubeconfig")
+	clusterImportCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "show what would be imported without saving")
+	clusterCmd.PersistentFlags().DurationVar(&clusterProbeTimeout, "timeout", 10*time.Second, "timeout for testing each cluster")
+
+	clusterCmd.AddCommand(clusterAddCmd)
+	clusterCmd.AddCommand(clusterRemoveCmd)
+	clusterCmd.AddCommand(clusterListCmd)
+	clusterCmd.AddCommand(clusterTestCmd)
+	clusterCmd.AddCommand(clusterSetDefaultCmd)
+	clusterCmd.AddCommand(clusterImportCmd)
+	rootCmd.AddCommand(clusterCmd)
+}
+
+// probeResult is the outcome of probing a context for FluxCD
+type probeResult struct {
+	context string
+	flux    bool
+	err     error
+}
+
+// probeContexts probes the given contexts for FluxCD CRDs concurrently
+func probeContexts(ctx context.Context, kubeconfigPath string, contexts []string) []probeResult {
+	results := make([]probeResult, len(contexts))
+
+	var wg sync.WaitGroup
+	for i, name := range contexts {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+
+			probeCtx, cancel := context.WithTimeout(ctx, clusterProbeTimeout)
+			defer cancel()
+
+			flux, err := probeFlux(probeCtx, kubeconfigPath, name)
+			results[i] = probeResult{context: name, flux: flux, err: err}
+		}(i, name)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// probeFlux connects to a context and checks whether FluxCD is installed
+func probeFlux(ctx context.Context, kubeconfigPath, contextName string) (bool, error) {
+	client, err := k8s.NewClient(config.ExpandPath(kubeconfigPath), contextName, "", k8s.AuthOptions{})
+	if err != nil {
+		return false, err
+	}
+	if err := client.TestConnection(ctx); err != nil {
+		return false, fmt.Errorf("connection test failed: %w", err)
+	}
+	return client.HasFluxCRDs(ctx)
+}
+
+// clusterForContext returns the name of the configured cluster using the
+// given context of the same kubeconfig, if any
+func clusterForContext(cfg *config.Config, kubeconfigPath, contextName string) string {
+	for _, cluster := range cfg.Clusters {
+		if cluster.Context == contextName && config.SameKubeconfig(cluster.Kubeconfig, kubeconfigPath) {
+			return cluster.Name
+		}
+	}
+	return ""
+}
+
+// yesNo formats a probe outcome
+func yesNo(value bool, err error) string {
+	if err != nil {
+		return "?"
+	}
+	if value {
+		return "yes"
+	}
+	return "no"
+}
+
+// clusterTestResult is the outcome of testing a cluster connection
+type clusterTestResult struct {
+	cluster config.ClusterConfig
+	info    *k8s.ClusterInfo
+	err     error
+}
+
+// testCluster runs TestConnection and GetClusterInfo against a cluster
+func testCluster(ctx context.Context, cluster config.ClusterConfig) clusterTestResult {
+	result := clusterTestResult{cluster: cluster}
+
+	ctx, cancel := context.WithTimeout(ctx, clusterProbeTimeout)
+	defer cancel()
+
+	client, err := k8s.NewClient(config.ExpandPath(cluster.Kubeconfig), cluster.Context, cluster.Namespace, core.AuthOptions(cluster))
+	if err != nil {
+		result.err = err
+		return result
+	}
+	if err := client.TestConnection(ctx); err != nil {
+		result.err = fmt.Errorf("connection test failed: %w", err)
+		return result
+	}
+	result.info, result.err = client.GetClusterInfo(ctx)
+
+	return result
+}
+
+// printTestResults prints connection test results as a table
+func printTestResults(results []clusterTestResult) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tCONTEXT\tSTATUS\tVERSION\tNODES\tMESSAGE")
+	for _, result := range results {
+		status, version, nodes, message := "OK", "", "", ""
+		if result.info != nil {
+			version = result.info.Version
+			nodes = fmt.Sprintf("%d", result.info.NodeCount)
+		}
+		if result.err != nil {
+			status, message = "FAILED", result.err.Error()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", result.cluster.Name, result.cluster.Context, status, version, nodes, message)
+	}
+	w.Flush()
+}
+
+// validateClusterConfig checks that the kubeconfig exists and contains the
+// context, and that the credential overrides can be used
+func validateClusterConfig(cluster config.ClusterConfig) error {
+	if cluster.Kubeconfig != "" {
+		if _, err := os.Stat(config.ExpandPath(cluster.Kubeconfig)); err != nil {
+			return fmt.Errorf("kubeconfig %s: %w", cluster.Kubeconfig, err)
+		}
+	}
+	if cluster.TokenFile != "" {
+		if _, err := os.Stat(config.ExpandPath(cluster.TokenFile)); err != nil {
+			return fmt.Errorf("token file %s: %w", cluster.TokenFile, err)
+		}
+	}
+	if len(cluster.AsGroups) > 0 && cluster.As == "" {
+		return fmt.Errorf("--as-group needs --as, groups can only be impersonated along with a user")
+	}
+
+	contexts, err := config.ListContexts(cluster.Kubeconfig)
+	if err != nil {
+		return err
+	}
+	for _, name := range contexts {
+		if name == cluster.Context {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("context %s not found in kubeconfig (available: %s)", cluster.Context, strings.Join(contexts, ", "))
+}
+
+// promptCluster asks for the cluster details on the terminal
+func promptCluster(cluster config.ClusterConfig) (config.ClusterConfig, error) {
+	reader := bufio.NewReader(os.Stdin)
+
+	if contexts, err := config.ListContexts(cluster.Kubeconfig); err == nil && len(contexts) > 0 {
+		fmt.Printf("Available contexts: %s\n", strings.Join(contexts, ", "))
+	}
+
+	prompts := []struct {
+		label string
+		value *string
+	}{
+		{"Name", &cluster.Name},
+		{"Context", &cluster.Context},
+		{"Kubeconfig", &cluster.Kubeconfig},
+		{"Namespace", &cluster.Namespace},
+		{"Description", &cluster.Description},
+	}
+	for _, prompt := range prompts {
+		defaultValue := *prompt.value
+		if prompt.label == "Context" && defaultValue == "" {
+			defaultValue = cluster.Name
+		}
+
+		if defaultValue != "" {
+			fmt.Printf("%s [%s]: ", prompt.label, defaultValue)
+		} else {
+			fmt.Printf("%s: ", prompt.label)
+		}
+
+		line, err := reader.ReadString('\n')
+		if err != nil && line == "" {
+			return cluster, fmt.Errorf("failed to read %s: %w", strings.ToLower(prompt.label), err)
+		}
+		*prompt.value = valueOr(strings.TrimSpace(line), defaultValue)
+	}
+
+	if cluster.Name == "" {
+		return cluster, fmt.Errorf("cluster name is required")
+	}
+	return cluster, nil
+}
+
+// isTerminal reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// valueOr returns value, or fallback if value is empty
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/malagant/fluxcli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	validateStrict bool
+	schemaOutput   string
+	viewEffective  bool
+)
+
+// configCmd groups the configuration file commands
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect and validate the FluxCLI configuration",
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		cmd.SilenceUsage = true
+	},
+}
+
+// configValidateCmd checks a configuration file
+var configValidateCmd = &cobra.Command{
+	Use:   "validate [file]",
+	Short: "Validate the configuration file",
+	Long: `Validate the configuration file and report every problem with its line and
+column. Errors, such as a refresh interval of zero, prevent FluxCLI from
+starting; warnings, such as unknown keys, are ignored at runtime.
+
+The file defaults to --config or $HOME/.fluxcli/config.yaml. The command exits
+with a non-zero status when errors are found, or warnings with --strict.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := configFilePath(args)
+		if err != nil {
+			return err
+		}
+
+		issues, err := config.ValidateFile(path)
+		if err != nil {
+			return err
+		}
+
+		errorCount, warnings := 0, 0
+		for _, issue := range issues {
+			if issue.Severity == config.SeverityError {
+				errorCount++
+			} else {
+				warnings++
+			}
+			fmt.Println(formatIssue(path, issue))
+		}
+
+		switch {
+		case errorCount > 0:
+			return fmt.Errorf("%s has %d error(s) and %d warning(s)", path, errorCount, warnings)
+		case warnings > 0 && validateStrict:
+			return fmt.Errorf("%s has %d warning(s)", path, warnings)
+		case warnings > 0:
+			fmt.Printf("%s is valid with %d warning(s)\n", path, warnings)
+		default:
+			fmt.Printf("%s is valid\n", path)
+		}
+		return nil
+	},
+}
+
+// configViewCmd prints the configuration
+var configViewCmd = &cobra.Command{
+	Use:   "view",
+	Short: "Print the configuration file",
+	Long: `Print the configuration file. With --effective, print the configuration
+FluxCLI actually uses: the file merged with the selected profile (--profile or
+$` + config.ProfileEnv + `) and the ` + config.EnvPrefix + `* environment variables, with a comment
+naming the source of every value, followed by the cluster, context and
+namespace of the session.
+
+Every key can be overridden by an environment variable named after its path,
+for example ` + config.EnvPrefix + `DEFAULTS_REFRESH_INTERVAL=30s or ` + config.EnvPrefix + `UI_THEME=dark.
+Lists of strings are given comma separated.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !viewEffective {
+			path, err := configFilePath(nil)
+			if err != nil {
+				return err
+			}
+			data, err := os.ReadFile(config.ExpandPath(path))
+			if err != nil {
+				return fmt.Errorf("failed to read config file: %w", err)
+			}
+			_, err = os.Stdout.Write(data)
+			return err
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+
+		data, err := cfg.EffectiveYAML()
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stdout.Write(data); err != nil {
+			return err
+		}
+
+		fmt.Println()
+		fmt.Println("# Session")
+		session := []struct {
+			key   string
+			value string
+		}{
+			{"profile", cfg.Profile},
+			{"cluster", cfg.CurrentCluster},
+			{"context", cfg.CurrentContext},
+			{"kubeconfig", cfg.CurrentKubeConfig},
+			{"namespace", cfg.CurrentNamespace},
+		}
+		for _, entry := range session {
+			if entry.value == "" {
+				continue
+			}
+			fmt.Printf("#   %-11s %s (%s)\n", entry.key+":", entry.value, cfg.Source("current."+entry.key))
+		}
+		return nil
+	},
+}
+
+// configSchemaCmd prints the JSON Schema of the configuration file
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema of the configuration file",
+	Long: `Print the JSON Schema of the configuration file. Editors that use the YAML
+language server offer completion and validation when the config file starts with:
+
+  # yaml-language-server: $schema=/path/to/config.schema.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		schema, err := config.JSONSchema()
+		if err != nil {
+			return fmt.Errorf("failed to generate schema: %w", err)
+		}
+
+		if schemaOutput == "" {
+			_, err = os.Stdout.Write(schema)
+			return err
+		}
+		return os.WriteFile(schemaOutput, schema, 0644)
+	},
+}
+
+func init() {
+	configValidateCmd.Flags().BoolVar(&validateStrict, "strict", false, "treat warnings as errors")
+	configSchemaCmd.Flags().StringVarP(&schemaOutput, "output", "o", "", "write the schema to a file instead of stdout")
+
+	configViewCmd.Flags().BoolVar(&viewEffective, "effective", false, "print the merged configuration and the source of each value")
+
+	configCmd.AddCommand(configValidateCmd)
+	configCmd.AddCommand(configViewCmd)
+	configCmd.AddCommand(configSchemaCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+// configFilePath returns the config file named by the arguments, --config or
+// the default location
+func configFilePath(args []string) (string, error) {
+	if len(args) > 0 {
+		return args[0], nil
+	}
+	if cfgFile != "" {
+		return cfgFile, nil
+	}
+	return config.DefaultConfigPath()
+}
+
+// formatIssue renders an issue as "file:line:column: severity: path: message"
+func formatIssue(file string, issue config.ValidationIssue) string {
+	location := file
+	if issue.Line > 0 {
+		location = fmt.Sprintf("%s:%d", file, issue.Line)
+	}
+	if issue.Column > 0 {
+		location = fmt.Sprintf("%s:%d", location, issue.Column)
+	}
+	if issue.Path == "" {
+		return fmt.Sprintf("%s: %s: %s", location, issue.Severity, issue.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s: %s", location, issue.Severity, issue.Path, issue.Message)
+}
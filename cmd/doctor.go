@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/malagant/fluxcli/internal/config"
+	"github.com/malagant/fluxcli/pkg/k8s"
+)
+
+var (
+	doctorCluster       string
+	doctorAllClusters   bool
+	doctorAllNamespaces bool
+	doctorTimeout       time.Duration
+)
+
+// doctorCmd diagnoses why FluxCLI cannot show or change objects
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose connectivity, Flux APIs and RBAC of clusters",
+	Long: fmt.Sprintf(`Diagnose why FluxCLI shows empty tables or fails to change objects.
+
+For each cluster the doctor tests that the API server answers, that the Flux
+API versions FluxCLI reads are served, that the namespace exists, and reviews
+whether the user may list, watch and patch every Flux kind in the namespace.
+Failed checks come with a hint on how to fix them.
+
+The command exits with %d when a check failed.`, k8s.ExitUnhealthy),
+	Example: `  fluxcli doctor
+  fluxcli doctor -n apps
+  fluxcli doctor --all-clusters -A`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		if doctorAllClusters && doctorCluster != "" {
+			return fmt.Errorf("--cluster and --all-clusters cannot be combined")
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+		clusters := []string{doctorCluster}
+		if doctorAllClusters {
+			clusters = configuredClusterNames(cfg)
+		}
+
+		var failed []string
+		for i, cluster := range clusters {
+			name := cluster
+			if name == "" {
+				name = cfg.CurrentCluster
+			}
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("► cluster %s\n", name)
+
+			diagnosis := diagnose(cmd.Context(), cfg, cluster)
+			printDiagnosis(os.Stdout, diagnosis)
+			if !diagnosis.Healthy() {
+				failed = append(failed, name)
+			}
+		}
+
+		if len(failed) > 0 {
+			return &ExitError{
+				Code: k8s.ExitUnhealthy,
+				Err:  fmt.Errorf("diagnosis failed for %s", strings.Join(failed, ", ")),
+			}
+		}
+		return nil
+	},
+}
+
+// diagnose diagnoses a configured cluster, the current one when cluster is
+// empty. Errors building the client are reported as a failed connectivity
+// check.
+func diagnose(ctx context.Context, cfg *config.Config, cluster string) *k8s.Diagnosis {
+	kubeconfigPath, kubeContext, ns, auth, err := clusterTarget(cfg, cluster)
+	if doctorAllNamespaces {
+		ns = ""
+	}
+	if err != nil {
+		return &k8s.Diagnosis{Namespace: ns, Checks: []k8s.Check{k8s.ConnectionCheck(err)}}
+	}
+	client, err := k8s.NewClient(kubeconfigPath, kubeContext, ns, auth)
+	if err != nil {
+		return &k8s.Diagnosis{Namespace: ns, Checks: []k8s.Check{k8s.ConnectionCheck(err)}}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, doctorTimeout)
+	defer cancel()
+	return client.Diagnose(ctx, ns)
+}
+
+// printDiagnosis prints the checks of a diagnosis with their hints
+func printDiagnosis(w io.Writer, diagnosis *k8s.Diagnosis) {
+	for _, check := range diagnosis.Checks {
+		fmt.Fprintf(w, "%s %s: %s\n", check.Status.Icon(), check.Name, check.Message)
+		if check.Hint != "" && check.Status != k8s.CheckPassed {
+			fmt.Fprintf(w, "  → %s\n", check.Hint)
+		}
+	}
+	fmt.Fprintf(w, "%s %s\n", diagnosis.Status().Icon(), diagnosis.Summary())
+}
+
+func init() {
+	doctorCmd.Flags().StringVar(&doctorCluster, "cluster", "", "configured cluster to diagnose (defaults to the current cluster)")
+	doctorCmd.Flags().BoolVar(&doctorAllClusters, "all-clusters", false, "diagnose every configured cluster")
+	doctorCmd.Flags().BoolVarP(&doctorAllNamespaces, "all-namespaces", "A", false, "review access in all namespaces")
+	doctorCmd.Flags().DurationVar(&doctorTimeout, "timeout", 30*time.Second, "timeout for diagnosing a cluster")
+	rootCmd.AddCommand(doctorCmd)
+}
@@ -0,0 +1,325 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/malagant/fluxcli/internal/config"
+	"github.com/malagant/fluxcli/pkg/k8s"
+)
+
+var (
+	logsCluster     string
+	logsAllClusters bool
+	logsKind        string
+	logsName        string
+	logsLevel       string
+	logsSince       time.Duration
+	logsTail        int64
+	logsFollow      bool
+	logsOutput      string
+)
+
+// logsCmd prints the logs of the Flux controllers
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Show the logs of the Flux controllers",
+	Long: `Show the structured logs of the Flux controllers in flux-system, prefixed with
+the cluster name. With --kind only the controller reconciling that kind is
+read, and --name and the global --namespace flag select the lines about an
+object; otherwise the logs of every installed controller are shown.
+
+Lines are printed oldest first. With --follow new lines are printed as they
+are logged until interrupted. --output json prints each line as logged with
+the cluster and controller pod added, one JSON object per line.`,
+	Example: `  fluxcli logs --kind kustomization --name apps -n flux-system
+  fluxcli logs --level error --since 1h --all-clusters
+  fluxcli logs --kind hr --follow -o json | jq .error`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		filter, err := newLogFilter(logsKind, logsName, namespace, logsLevel)
+		if err != nil {
+			return err
+		}
+		if logsOutput != "text" && logsOutput != "json" {
+			return fmt.Errorf("unknown output format %q, expected text or json", logsOutput)
+		}
+		if logsAllClusters && logsCluster != "" {
+			return fmt.Errorf("--cluster and --all-clusters cannot be combined")
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+		clusters := []string{logsCluster}
+		if logsAllClusters {
+			clusters = configuredClusterNames(cfg)
+		}
+
+		controllers := k8s.FluxControllers
+		if filter.kind != "" {
+			controller, err := k8s.ControllerForKind(filter.kind)
+			if err != nil {
+				return err
+			}
+			controllers = []string{controller}
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+
+		opts := k8s.LogOptions{Since: logsSince, TailLines: logsTail, Follow: logsFollow}
+		lines, errs := streamClusterLogs(ctx, cfg, clusters, controllers, opts)
+
+		printLine := printLogLine
+		if logsOutput == "json" {
+			printLine = printLogJSON
+		}
+		if logsFollow {
+			for line := range lines {
+				if filter.matches(line.LogEntry) {
+					printLine(os.Stdout, line)
+				}
+			}
+		} else {
+			// All lines were read, print them in the order they were logged
+			var collected []clusterLogLine
+			for line := range lines {
+				if filter.matches(line.LogEntry) {
+					collected = append(collected, line)
+				}
+			}
+			slices.SortStableFunc(collected, func(a, b clusterLogLine) int {
+				return a.Time.Compare(b.Time)
+			})
+			for _, line := range collected {
+				printLine(os.Stdout, line)
+			}
+		}
+
+		failed := 0
+		for err := range errs {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			failed++
+		}
+		if failed > 0 && failed == len(clusters) {
+			return fmt.Errorf("no controller logs could be read")
+		}
+		return nil
+	},
+}
+
+// logFilter selects the log lines printed by the logs command. Empty fields
+// match every line.
+type logFilter struct {
+	kind      string
+	name      string
+	namespace string
+	level     string // Lowest level printed
+}
+
+// newLogFilter validates the filter flags. Kinds are given like the types
+// of the status command, or as any Flux kind.
+func newLogFilter(kind, name, namespace, level string) (logFilter, error) {
+	filter := logFilter{name: name, namespace: namespace}
+
+	if kind != "" {
+		if resourceType, err := k8s.ParseResourceType(kind); err == nil {
+			filter.kind = string(resourceType)
+		} else if i := slices.IndexFunc(k8s.FluxKinds, func(k string) bool { return strings.EqualFold(k, kind) }); i >= 0 {
+			filter.kind = k8s.FluxKinds[i]
+		} else {
+			return logFilter{}, err
+		}
+	}
+
+	switch strings.ToLower(level) {
+	case "", "all":
+	case k8s.LogLevelDebug, k8s.LogLevelInfo, k8s.LogLevelError:
+		filter.level = strings.ToLower(level)
+	default:
+		return logFilter{}, fmt.Errorf("unknown log level %q, expected debug, info or error", level)
+	}
+	return filter, nil
+}
+
+// matches reports whether a log line passes the filter
+func (f logFilter) matches(entry k8s.LogEntry) bool {
+	if f.level != "" && k8s.LogLevelSeverity(entry.Level) < k8s.LogLevelSeverity(f.level) {
+		return false
+	}
+	if f.name != "" && entry.Name != f.name {
+		return false
+	}
+	if f.namespace != "" && entry.Namespace != f.namespace {
+		return false
+	}
+	// Lines about an object of another kind are logged by the same
+	// controller, such as HelmCharts next to GitRepositories
+	return f.kind == "" || entry.Kind == "" || strings.EqualFold(entry.Kind, f.kind)
+}
+
+// clusterLogLine is a controller log line of a cluster
+type clusterLogLine struct {
+	Cluster string
+	k8s.LogEntry
+}
+
+// configuredClusterNames returns the configured clusters, or the current
+// cluster when none are configured
+func configuredClusterNames(cfg *config.Config) []string {
+	if len(cfg.Clusters) == 0 {
+		return []string{""}
+	}
+	names := make([]string, len(cfg.Clusters))
+	for i, cluster := range cfg.Clusters {
+		names[i] = cluster.Name
+	}
+	return names
+}
+
+// streamClusterLogs streams the logs of the controllers of every cluster
+// into one channel. Clusters that cannot be read are reported on the error
+// channel, which is closed after the lines. Controllers without running
+// pods are skipped, as the image controllers are optional.
+func streamClusterLogs(ctx context.Context, cfg *config.Config, clusters, controllers []string, opts k8s.LogOptions) (<-chan clusterLogLine, <-chan error) {
+	lines := make(chan clusterLogLine, 100)
+	errs := make(chan error, len(clusters))
+
+	var wg sync.WaitGroup
+	for _, cluster := range clusters {
+		wg.Add(1)
+		go func(cluster string) {
+			defer wg.Done()
+			name := cluster
+			if name == "" {
+				name = cfg.CurrentCluster
+			}
+			if err := streamLogs(ctx, cfg, cluster, name, controllers, opts, lines); err != nil {
+				errs <- fmt.Errorf("[%s] %w", name, err)
+			}
+		}(cluster)
+	}
+	go func() {
+		wg.Wait()
+		close(lines)
+		close(errs)
+	}()
+
+	return lines, errs
+}
+
+// streamLogs streams the logs of the controllers of one cluster
+func streamLogs(ctx context.Context, cfg *config.Config, cluster, name string, controllers []string, opts k8s.LogOptions, lines chan<- clusterLogLine) error {
+	kubeconfigPath, kubeContext, ns, auth, err := clusterTarget(cfg, cluster)
+	if err != nil {
+		return err
+	}
+	client, err := k8s.NewClient(kubeconfigPath, kubeContext, ns, auth)
+	if err != nil {
+		return err
+	}
+
+	// Streams already started end when a later controller fails
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var streams []<-chan k8s.LogEntry
+	for _, controller := range controllers {
+		controllerOpts := opts
+		controllerOpts.Controller = controller
+		entries, err := client.StreamControllerLogs(ctx, controllerOpts)
+		if errors.Is(err, k8s.ErrNoControllerPods) && len(controllers) > 1 {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		streams = append(streams, entries)
+	}
+
+	var wg sync.WaitGroup
+	for _, entries := range streams {
+		wg.Add(1)
+		go func(entries <-chan k8s.LogEntry) {
+			defer wg.Done()
+			for entry := range entries {
+				lines <- clusterLogLine{Cluster: name, LogEntry: entry}
+			}
+		}(entries)
+	}
+	wg.Wait()
+	return nil
+}
+
+// lineBreaks joins the lines of multi-line messages, such as stack traces,
+// so that every log entry stays on one line
+var lineBreaks = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ")
+
+// printLogLine prints a log line as text: the cluster, time, level,
+// controller, object and message with its error
+func printLogLine(w io.Writer, line clusterLogLine) {
+	timestamp := "-"
+	if !line.Time.IsZero() {
+		timestamp = line.Time.Local().Format(time.RFC3339)
+	}
+	level := line.Level
+	if level == "" {
+		level = "-"
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "[%s] %s %-5s %s", line.Cluster, timestamp, level, line.Controller)
+	if line.Name != "" {
+		fmt.Fprintf(&text, " %s %s/%s", line.Kind, line.Namespace, line.Name)
+	}
+	fmt.Fprintf(&text, " %s", lineBreaks.Replace(line.Message))
+	if line.Error != "" {
+		fmt.Fprintf(&text, ": %s", lineBreaks.Replace(line.Error))
+	}
+	fmt.Fprintln(w, text.String())
+}
+
+// printLogJSON prints a log line as logged with the cluster and pod added. Lines that are not JSON are printed with their text as msg.
+func printLogJSON(w io.Writer, line clusterLogLine) {
+	var raw map[string]any
+	if err := json.Unmarshal([]byte(line.Raw), &raw); err != nil {
+		raw = map[string]any{"msg": line.Raw}
+	}
+	// Flux logs the reconciler as controller, the pod names the controller
+	raw["cluster"] = line.Cluster
+	raw["pod"] = line.Pod
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(w, string(data))
+}
+
+func init() {
+	logsCmd.Flags().StringVar(&logsCluster, "cluster", "", "configured cluster to read (defaults to the current cluster)")
+	logsCmd.Flags().BoolVar(&logsAllClusters, "all-clusters", false, "read the logs of every configured cluster")
+	logsCmd.Flags().StringVar(&logsKind, "kind", "", "only read the controller of this kind, such as kustomization or hr")
+	logsCmd.Flags().StringVar(&logsName, "name", "", "only show the lines about objects with this name")
+	logsCmd.Flags().StringVar(&logsLevel, "level", "", "only show lines of this level or above: debug, info or error")
+	logsCmd.Flags().DurationVar(&logsSince, "since", 0, "only show lines logged within this duration, such as 1h")
+	logsCmd.Flags().Int64Var(&logsTail, "tail", 0, "lines to read from the end of each pod log, all when zero")
+	logsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "keep printing new lines until interrupted")
+	logsCmd.Flags().StringVarP(&logsOutput, "output", "o", "text", "output format: text or json")
+	rootCmd.AddCommand(logsCmd)
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -12,11 +13,13 @@ import (
 
 var (
 	cfgFile     string
+	profile     string
 	kubeconfig  string
-	context     string
+	kubeContext string
 	namespace   string
 	debug       bool
 	logLevel    string
+	readOnly    bool
 	
 	// Version information set by build
 	version   = "dev"
@@ -49,7 +52,7 @@ Features:
 - Event Streaming - Monitor FluxCD events and reconciliation status in real-time`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Load configuration
-		cfg, err := config.Load(cfgFile, kubeconfig, context, namespace)
+		cfg, err := loadConfig()
 		if err != nil {
 			return fmt.Errorf("failed to load configuration: %w", err)
 		}
@@ -64,6 +67,30 @@ Features:
 	},
 }
 
+// ExitError makes the process exit with Code instead of the generic failure
+// status
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the process exit code for an error returned by Execute
+func ExitCode(err error) int {
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return 1
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() error {
 	return rootCmd.Execute()
@@ -74,11 +101,13 @@ func init() {
 
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.fluxcli/config.yaml)")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "config profile to use (default is $"+config.ProfileEnv+")")
 	rootCmd.PersistentFlags().StringVar(&kubeconfig, "kubeconfig", "", "path to kubeconfig file (default is $KUBECONFIG env var, then $HOME/.kube/config)")
-	rootCmd.PersistentFlags().StringVar(&context, "context", "", "kubernetes context to use")
+	rootCmd.PersistentFlags().StringVar(&kubeContext, "context", "", "kubernetes context to use")
 	rootCmd.PersistentFlags().StringVarP(&namespace, "namespace", "n", "", "kubernetes namespace to use")
 	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "enable debug mode")
 	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "info", "log level (trace, debug, info, warn, error)")
+	rootCmd.PersistentFlags().BoolVar(&readOnly, "read-only", false, "block suspend, resume and reconcile on every cluster")
 
 	// Bind flags to viper
 	viper.BindPFlag("kubeconfig", rootCmd.PersistentFlags().Lookup("kubeconfig"))
@@ -100,6 +129,18 @@ func init() {
 	rootCmd.AddCommand(versionCmd)
 }
 
+// loadConfig loads the configuration with the global flags applied
+func loadConfig() (*config.Config, error) {
+	cfg, err := config.LoadProfile(cfgFile, profile, kubeconfig, kubeContext, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if readOnly {
+		cfg.SetReadOnly()
+	}
+	return cfg, nil
+}
+
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
 	if cfgFile != "" {
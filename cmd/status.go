@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/malagant/fluxcli/internal/config"
+	"github.com/malagant/fluxcli/pkg/core"
+	"github.com/malagant/fluxcli/pkg/k8s"
+)
+
+var (
+	statusCluster       string
+	statusAllNamespaces bool
+	statusHealth        []string
+	statusTimeout       time.Duration
+)
+
+// statusCmd reports the health of FluxCD resources
+var statusCmd = &cobra.Command{
+	Use:   "status [type...]",
+	Short: "Show the health of FluxCD resources",
+	Long: fmt.Sprintf(`Show the health of the FluxCD resources in a cluster, most severe first.
+Types are gitrepositories, helmrepositories, kustomizations and helmreleases or
+their short names (gitrepo, helmrepo, ks, hr); all types are shown by default.
+
+The health is computed from the Ready, Reconciling and Stalled conditions, the
+observed generation and suspension. The command exits with %d when every
+resource shown is ready or suspended, %d when one failed or is stalled and %d
+when one is still progressing or reports no status.`, k8s.ExitHealthy, k8s.ExitUnhealthy, k8s.ExitProgressing),
+	Example: `  fluxcli status
+  fluxcli status ks hr --cluster production -A
+  fluxcli status --health failed,stalled`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		resourceTypes := k8s.ResourceTypes
+		if len(args) > 0 {
+			resourceTypes = nil
+			for _, arg := range args {
+				resourceType, err := k8s.ParseResourceType(arg)
+				if err != nil {
+					return err
+				}
+				resourceTypes = append(resourceTypes, resourceType)
+			}
+		}
+
+		states := make([]k8s.HealthState, 0, len(statusHealth))
+		for _, name := range statusHealth {
+			state, err := k8s.ParseHealthState(name)
+			if err != nil {
+				return err
+			}
+			states = append(states, state)
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+		kubeconfigPath, kubeContext, ns, auth, err := clusterTarget(cfg, statusCluster)
+		if err != nil {
+			return err
+		}
+		if statusAllNamespaces {
+			ns = ""
+		}
+
+		client, err := k8s.NewClient(kubeconfigPath, kubeContext, ns, auth)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(cmd.Context(), statusTimeout)
+		defer cancel()
+
+		var resources []k8s.Resource
+		for _, resourceType := range resourceTypes {
+			list, err := client.ListResources(ctx, resourceType, ns)
+			if err != nil {
+				return err
+			}
+			resources = append(resources, list...)
+		}
+
+		resources = k8s.FilterByHealth(resources, states...)
+		k8s.SortByHealth(resources)
+		printStatus(resources)
+
+		return healthResult(resources)
+	},
+}
+
+// clusterTarget returns the kubeconfig, context and namespace to query: the
+// configured cluster with the given name, or the current cluster of the
+// configuration when name is empty, along with its credential overrides.
+// Like the current cluster, a cluster without a namespace is scoped to
+// defaults.namespace.
+func clusterTarget(cfg *config.Config, name string) (kubeconfigPath, kubeContext, ns string, auth k8s.AuthOptions, err error) {
+	if name == "" {
+		return config.ExpandPath(cfg.CurrentKubeConfig), cfg.CurrentContext, cfg.CurrentNamespace, core.ClusterAuth(cfg, cfg.CurrentCluster), nil
+	}
+
+	cluster, ok := cfg.GetCluster(name)
+	if !ok {
+		return "", "", "", auth, fmt.Errorf("cluster %s not found", name)
+	}
+	ns = valueOr(cluster.Namespace, cfg.Defaults.Namespace)
+	if namespace != "" {
+		ns = namespace
+	}
+	return config.ExpandPath(cluster.Kubeconfig), cluster.Context, ns, core.AuthOptions(*cluster), nil
+}
+
+// printStatus prints resources and their health as a table
+func printStatus(resources []k8s.Resource) {
+	if len(resources) == 0 {
+		fmt.Println("No resources found")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TYPE\tNAMESPACE\tNAME\tHEALTH\tREASON\tMESSAGE")
+	for _, resource := range resources {
+		message := strings.ReplaceAll(resource.Message, "\n", " ")
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", resource.Type, resource.Namespace, resource.Name, resource.Health, resource.Status, message)
+	}
+	w.Flush()
+}
+
+// healthResult returns an ExitError for the most severe health state among
+// resources, or nil when all of them are healthy
+func healthResult(resources []k8s.Resource) error {
+	counts := make(map[k8s.HealthState]int)
+	worst := k8s.HealthReady
+	for _, resource := range resources {
+		counts[resource.Health]++
+		if resource.Health.Severity() < worst.Severity() {
+			worst = resource.Health
+		}
+	}
+	if worst.Healthy() {
+		return nil
+	}
+
+	unhealthy := 0
+	var summary []string
+	for _, state := range k8s.HealthStates {
+		if counts[state] > 0 && !state.Healthy() {
+			unhealthy += counts[state]
+			summary = append(summary, fmt.Sprintf("%d %s", counts[state], strings.ToLower(string(state))))
+		}
+	}
+	return &ExitError{
+		Code: worst.ExitCode(),
+		Err:  fmt.Errorf("%d of %d resource(s) are not healthy: %s", unhealthy, len(resources), strings.Join(summary, ", ")),
+	}
+}
+
+func init() {
+	statusCmd.Flags().StringVar(&statusCluster, "cluster", "", "configured cluster to query (defaults to the current cluster)")
+	statusCmd.Flags().BoolVarP(&statusAllNamespaces, "all-namespaces", "A", false, "query all namespaces")
+	statusCmd.Flags().StringSliceVar(&statusHealth, "health", nil, "only show resources in these health states")
+	statusCmd.Flags().DurationVar(&statusTimeout, "timeout", 30*time.Second, "timeout for listing resources")
+	rootCmd.AddCommand(statusCmd)
+}
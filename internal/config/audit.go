@@ -0,0 +1,25 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// AuditConfig controls the log of suspend, resume and reconcile operations
+type AuditConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	Path     string `yaml:"path"`     // Defaults to ~/.fluxcli/audit.log
+	Annotate bool   `yaml:"annotate"` // Record who suspended an object on the object
+}
+
+// AuditPath returns the file of the audit log
+func (c *Config) AuditPath() (string, error) {
+	if c.Audit.Path != "" {
+		return ExpandPath(c.Audit.Path), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".fluxcli", "audit.log"), nil
+}
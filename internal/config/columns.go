@@ -0,0 +1,67 @@
+package config
+
+import (
+	"slices"
+	"strings"
+)
+
+// TableKinds are the resource kinds whose columns can be set in ui.columns
+var TableKinds = []string{"gitrepository", "helmrepository", "kustomization", "helmrelease"}
+
+// TableColumns are the columns of the resource tables. Columns that do not
+// apply to a kind, such as chart for a GitRepository, stay empty.
+var TableColumns = []string{
+	"name", "namespace", "cluster", "health", "status", "age", "transition", "message",
+	"url", "source", "chart", "revision", "interval", "last_reconcile", "suspend_reason",
+	"suspended_by",
+}
+
+// ResourceColumns returns the columns configured for a resource kind, or nil
+// when the kind uses the default columns
+func (c *Config) ResourceColumns(kind string) []string {
+	return c.UI.Columns[strings.ToLower(kind)]
+}
+
+// SetResourceColumns sets the columns of a resource kind; no columns restore
+// the defaults
+func (c *Config) SetResourceColumns(kind string, columns []string) {
+	kind = strings.ToLower(kind)
+	if len(columns) == 0 {
+		delete(c.UI.Columns, kind)
+		return
+	}
+	if c.UI.Columns == nil {
+		c.UI.Columns = make(map[string][]string)
+	}
+	c.UI.Columns[kind] = columns
+}
+
+// checkColumns validates the per-kind column lists
+func (v *validator) checkColumns(c *Config) {
+	kinds := make([]string, 0, len(c.UI.Columns))
+	for kind := range c.UI.Columns {
+		kinds = append(kinds, kind)
+	}
+	slices.Sort(kinds)
+
+	for _, kind := range kinds {
+		p := at("ui", "columns", kind)
+		if !slices.Contains(TableKinds, kind) {
+			v.warnf(p, "unknown resource kind %q, expected one of %s", kind, strings.Join(TableKinds, ", "))
+			continue
+		}
+
+		columns := c.UI.Columns[kind]
+		if len(columns) == 0 {
+			v.warnf(p, "no columns given, the default columns are shown")
+		}
+		for i, column := range columns {
+			switch {
+			case !slices.Contains(TableColumns, column):
+				v.errorf(at("ui", "columns", kind, i), "unknown column %q, expected one of %s", column, strings.Join(TableColumns, ", "))
+			case slices.Index(columns, column) < i:
+				v.warnf(at("ui", "columns", kind, i), "column %q is listed twice", column)
+			}
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateColumns(t *testing.T) {
+	path := writeTestConfig(t, `ui:
+  columns:
+    kustomization: [name, health, revison]
+    helmrelease: [name, name]
+    ocirepository: [name]
+`)
+
+	issues, err := ValidateFile(path)
+	require.NoError(t, err)
+
+	got := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		got = append(got, string(issue.Severity)+" "+issue.String())
+	}
+	assert.Equal(t, []string{
+		`error 3:35: ui.columns.kustomization[2]: unknown column "revison", expected one of name, namespace, cluster, health, status, age, transition, message, url, source, chart, revision, interval, last_reconcile, suspend_reason, suspended_by`,
+		`warning 4:25: ui.columns.helmrelease[1]: column "name" is listed twice`,
+		`warning 5:5: ui.columns.ocirepository: unknown resource kind "ocirepository", expected one of gitrepository, helmrepository, kustomization, helmrelease`,
+	}, got)
+}
+
+func TestSetResourceColumns(t *testing.T) {
+	cfg := newConfig()
+	cfg.SetResourceColumns("Kustomization", []string{"name", "revision"})
+	assert.Equal(t, []string{"name", "revision"}, cfg.ResourceColumns("kustomization"))
+	assert.Nil(t, cfg.ResourceColumns("HelmRelease"))
+
+	cfg.SetResourceColumns("kustomization", nil)
+	assert.Nil(t, cfg.ResourceColumns("Kustomization"))
+}
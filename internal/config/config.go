@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
 	"k8s.io/client-go/util/homedir"
 )
 
@@ -15,11 +17,30 @@ type Config struct {
 	Clusters         []ClusterConfig `yaml:"clusters"`
 	Defaults         DefaultConfig   `yaml:"defaults"`
 	UI               UIConfig        `yaml:"ui"`
+	Discovery        DiscoveryConfig `yaml:"discovery"`
+	Audit            AuditConfig     `yaml:"audit"`
+	KeyBindings      KeyBindingsConfig `yaml:"keybindings"`
 	Debug            bool            `yaml:"debug"`
 	LogLevel         string          `yaml:"log_level"`
 	CurrentKubeConfig string         `yaml:"-"` // Runtime only
 	CurrentContext   string          `yaml:"-"` // Runtime only
+	CurrentCluster   string          `yaml:"-"` // Runtime only
 	CurrentNamespace string          `yaml:"-"` // Runtime only
+	Profile          string          `yaml:"-"` // Runtime only, the active profile
+
+	path      string        // File the configuration was loaded from
+	document  *yaml.Node    // Parsed file, keeps comments and unknown keys
+	overrides loadOverrides // Command line arguments, reapplied on Reload
+	sources   map[string]Source // Origin of each value set by the file, a profile or the environment
+}
+
+// loadOverrides holds the command line arguments passed to Load
+type loadOverrides struct {
+	profile    string
+	kubeconfig string
+	context    string
+	namespace  string
+	readOnly   bool // --read-only, which a reload must not drop
 }
 
 // ClusterConfig represents a single cluster configuration
@@ -30,14 +51,31 @@ type ClusterConfig struct {
 	Namespace   string `yaml:"namespace"`
 	Color       string `yaml:"color"`
 	Description string `yaml:"description"`
+	ReadOnly    bool   `yaml:"read_only,omitempty"` // Block every change of the cluster
+	Protected   bool   `yaml:"protected,omitempty"` // Changes need the cluster name typed to confirm
+	As          string   `yaml:"as,omitempty"`         // User or service account to impersonate
+	AsGroups    []string `yaml:"as_groups,omitempty"`  // Groups to impersonate, only with as
+	TokenFile   string   `yaml:"token_file,omitempty"` // Bearer token file instead of the kubeconfig credentials
+	Exec        *ExecConfig `yaml:"exec,omitempty"`    // Credential plugin instead of the kubeconfig credentials
+}
+
+// ExecConfig configures a client-go exec credential plugin
+type ExecConfig struct {
+	Command    string            `yaml:"command"`
+	Args       []string          `yaml:"args,omitempty"`
+	Env        map[string]string `yaml:"env,omitempty"`
+	APIVersion string            `yaml:"api_version,omitempty"`
 }
 
 // DefaultConfig represents default settings
 type DefaultConfig struct {
+	Cluster              string        `yaml:"cluster"`
 	Namespace            string        `yaml:"namespace"`
 	RefreshInterval      time.Duration `yaml:"refresh_interval"`
 	MaxConcurrentClusters int          `yaml:"max_concurrent_clusters"`
 	EventsEnabled        bool          `yaml:"events_enabled"`
+	EventsLookback       time.Duration `yaml:"events_lookback"`
+	ReadOnly             bool          `yaml:"read_only"`
 }
 
 // UIConfig represents UI-specific settings
@@ -49,42 +87,56 @@ type UIConfig struct {
 	PaneEventsHeight int   `yaml:"pane_events_height"`
 	ColumnsName     int    `yaml:"columns_name"`
 	ColumnsStatus   int    `yaml:"columns_status"`
+	Themes          map[string]ThemeConfig `yaml:"themes"` // User themes by name
+	Columns         map[string][]string    `yaml:"columns"` // Resource table columns by kind
 }
 
-// Load loads configuration from file and command line arguments
+// Load loads configuration from file and command line arguments, using the
+// profile named by FLUXCLI_PROFILE if set
 func Load(configFile, kubeconfig, context, namespace string) (*Config, error) {
-	cfg := &Config{
-		Defaults: DefaultConfig{
-			Namespace:            "flux-system",
-			RefreshInterval:      5 * time.Second,
-			MaxConcurrentClusters: 10,
-			EventsEnabled:        true,
-		},
-		UI: UIConfig{
-			Theme:           "dark",
-			ShowAge:         true,
-			ShowMessage:     true,
-			ShowNamespace:   true,
-			PaneEventsHeight: 4,
-			ColumnsName:     30,
-			ColumnsStatus:   15,
-		},
-		Debug:    viper.GetBool("debug"),
-		LogLevel: viper.GetString("log-level"),
+	return LoadProfile(configFile, "", kubeconfig, context, namespace)
+}
+
+// LoadProfile loads configuration from file and command line arguments.
+// Values are resolved in increasing order of precedence: built-in defaults,
+// the config file, the named profile (FLUXCLI_PROFILE when empty), FLUXCLI_*
+// environment variables and finally the command line arguments.
+func LoadProfile(configFile, profile, kubeconfig, context, namespace string) (*Config, error) {
+	profileSource := Source{Kind: SourceFlag, Detail: "--profile"}
+	if profile == "" {
+		profile = os.Getenv(ProfileEnv)
+		profileSource = Source{Kind: SourceEnv, Detail: ProfileEnv}
 	}
 
+	cfg := newConfig()
+	cfg.overrides = loadOverrides{profile: profile, kubeconfig: kubeconfig, context: context, namespace: namespace}
+
 	// Load from config file if it exists
 	if err := loadConfigFile(cfg, configFile); err != nil {
 		return nil, err
 	}
+	if err := cfg.applyProfile(profile); err != nil {
+		return nil, err
+	}
+	if profile != "" {
+		cfg.setSource("current.profile", profileSource)
+	}
+	issues := cfg.applyEnv()
+	if issues = append(issues, cfg.Validate()...); HasErrors(issues) {
+		sortIssues(issues)
+		return nil, &ValidationError{File: cfg.path, Issues: issues}
+	}
 
-	// Override with command line arguments
+	// Override with command line arguments. The sources of the resulting
+	// session values are recorded under "current".
 	if kubeconfig != "" {
 		cfg.CurrentKubeConfig = kubeconfig
+		cfg.setSource("current.kubeconfig", Source{Kind: SourceFlag, Detail: "--kubeconfig"})
 	} else if cfg.CurrentKubeConfig == "" {
 		// Check KUBECONFIG environment variable first
 		if kubeconfigEnv := os.Getenv("KUBECONFIG"); kubeconfigEnv != "" {
 			cfg.CurrentKubeConfig = kubeconfigEnv
+			cfg.setSource("current.kubeconfig", Source{Kind: SourceEnv, Detail: "KUBECONFIG"})
 		} else {
 			// Use default kubeconfig location as final fallback
 			if home := homedir.HomeDir(); home != "" {
@@ -95,33 +147,121 @@ func Load(configFile, kubeconfig, context, namespace string) (*Config, error) {
 
 	if context != "" {
 		cfg.CurrentContext = context
+		cfg.setSource("current.context", Source{Kind: SourceFlag, Detail: "--context"})
+	} else if defaultCluster, ok := cfg.GetCluster(cfg.Defaults.Cluster); ok && cfg.Defaults.Cluster != "" {
+		// Start on the configured default cluster
+		fromCluster := Source{Kind: SourceCluster, Detail: defaultCluster.Name}
+		cfg.CurrentCluster = defaultCluster.Name
+		cfg.CurrentContext = defaultCluster.Context
+		cfg.setSource("current.cluster", cfg.Source("defaults.cluster"))
+		cfg.setSource("current.context", fromCluster)
+		if defaultCluster.Kubeconfig != "" && kubeconfig == "" {
+			cfg.CurrentKubeConfig = defaultCluster.Kubeconfig
+			cfg.setSource("current.kubeconfig", fromCluster)
+		}
+		if defaultCluster.Namespace != "" && namespace == "" {
+			cfg.CurrentNamespace = defaultCluster.Namespace
+			cfg.setSource("current.namespace", fromCluster)
+		}
+	} else if cfg.CurrentContext == "" {
+		// Name the default cluster after the kubeconfig's current context
+		cfg.CurrentContext = CurrentContextName(cfg.CurrentKubeConfig)
+		cfg.setSource("current.context", Source{Kind: SourceKubeconfig, Detail: "current-context"})
+	}
+
+	if cfg.CurrentCluster == "" {
+		cfg.CurrentCluster = cfg.CurrentContext
+		cfg.setSource("current.cluster", cfg.Source("current.context"))
 	}
 
 	if namespace != "" {
 		cfg.CurrentNamespace = namespace
+		cfg.setSource("current.namespace", Source{Kind: SourceFlag, Detail: "--namespace"})
 	} else if cfg.CurrentNamespace == "" {
 		cfg.CurrentNamespace = cfg.Defaults.Namespace
+		cfg.setSource("current.namespace", cfg.Source("defaults.namespace"))
 	}
 
 	return cfg, nil
 }
 
+// Reload loads the configuration file again, reapplying the command line
+// arguments of the original Load
+func (c *Config) Reload() (*Config, error) {
+	cfg, err := LoadProfile(c.path, c.overrides.profile, c.overrides.kubeconfig, c.overrides.context, c.overrides.namespace)
+	if err != nil {
+		return nil, err
+	}
+	if c.overrides.readOnly {
+		cfg.SetReadOnly()
+	}
+	return cfg, nil
+}
+
+// SetReadOnly blocks changes of every cluster, as the --read-only flag. It
+// is kept when the configuration is reloaded.
+func (c *Config) SetReadOnly() {
+	c.Defaults.ReadOnly = true
+	c.overrides.readOnly = true
+	c.setSource("defaults.read_only", Source{Kind: SourceFlag, Detail: "--read-only"})
+}
+
+// IsReadOnly reports whether changes of a cluster are blocked, by
+// --read-only, defaults.read_only or the read_only setting of the cluster
+func (c *Config) IsReadOnly(cluster string) bool {
+	if c.Defaults.ReadOnly {
+		return true
+	}
+	clusterCfg, ok := c.GetCluster(cluster)
+	return ok && clusterCfg.ReadOnly
+}
+
+// IsProtected reports whether changes of a cluster need its name typed to
+// confirm them
+func (c *Config) IsProtected(cluster string) bool {
+	clusterCfg, ok := c.GetCluster(cluster)
+	return ok && clusterCfg.Protected
+}
+
+// newConfig returns a configuration holding the built-in defaults
+func newConfig() *Config {
+	return &Config{
+		Defaults: DefaultConfig{
+			Namespace:            "flux-system",
+			RefreshInterval:      5 * time.Second,
+			MaxConcurrentClusters: 10,
+			EventsEnabled:        true,
+			EventsLookback:       time.Hour,
+		},
+		UI: UIConfig{
+			Theme:           "dark",
+			ShowAge:         true,
+			ShowMessage:     true,
+			ShowNamespace:   true,
+			PaneEventsHeight: 4,
+			ColumnsName:     30,
+			ColumnsStatus:   15,
+		},
+		Audit: AuditConfig{
+			Enabled: true,
+		},
+		Debug:    viper.GetBool("debug"),
+		LogLevel: viper.GetString("log-level"),
+	}
+}
+
 // loadConfigFile loads configuration from file
 func loadConfigFile(cfg *Config, configFile string) error {
-	if configFile != "" {
-		viper.SetConfigFile(configFile)
-	} else {
-		// Find home directory
-		home, err := os.UserHomeDir()
+	configPath := configFile
+	if configPath == "" {
+		var err error
+		configPath, err = DefaultConfigPath()
 		if err != nil {
 			return err
 		}
 
-		configDir := filepath.Join(home, ".fluxcli")
-		configPath := filepath.Join(configDir, "config.yaml")
-
 		// Create config directory if it doesn't exist
-		if err := os.MkdirAll(configDir, 0755); err != nil {
+		if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
 			return fmt.Errorf("failed to create config directory: %w", err)
 		}
 
@@ -131,17 +271,18 @@ func loadConfigFile(cfg *Config, configFile string) error {
 				return fmt.Errorf("failed to create default config: %w", err)
 			}
 		}
-
-		viper.SetConfigFile(configPath)
 	}
 
-	if err := viper.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-			return fmt.Errorf("failed to read config file: %w", err)
-		}
-	}
+	return readConfigFile(cfg, ExpandPath(configPath))
+}
 
-	return viper.Unmarshal(cfg)
+// DefaultConfigPath returns the default config file location
+func DefaultConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".fluxcli", "config.yaml"), nil
 }
 
 // createDefaultConfig creates a default configuration file
@@ -151,24 +292,51 @@ func createDefaultConfig(path string) error {
 clusters: []
 
 defaults:
+  cluster: ""
   namespace: flux-system
   refresh_interval: 5s
   max_concurrent_clusters: 10
   events_enabled: true
+  events_lookback: 1h
+  read_only: false # block suspend, resume and reconcile on every cluster
 
 ui:
-  theme: dark
+  theme: dark # dark, light, high-contrast, colorblind or a theme from ui.themes
   show_age: true
   show_message: true
   show_namespace: true
   pane_events_height: 4
   columns_name: 30
   columns_status: 15
+
+# Import kubeconfig contexts as clusters at startup. Include/exclude entries
+# are globs, or regular expressions when wrapped in slashes.
+discovery:
+  enabled: false
+  include: []
+  exclude: []
+
+# Log suspend, resume and reconcile to ~/.fluxcli/audit.log, see fluxcli audit
+audit:
+  enabled: true
+  annotate: false # also record who suspended an object on the object
 `
 
 	return os.WriteFile(path, []byte(defaultConfig), 0644)
 }
 
+// ExpandPath expands a leading "~" to the user's home directory
+func ExpandPath(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home := homedir.HomeDir()
+	if home == "" {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
+
 // GetCluster returns cluster configuration by name
 func (c *Config) GetCluster(name string) (*ClusterConfig, bool) {
 	for _, cluster := range c.Clusters {
@@ -197,18 +365,30 @@ func (c *Config) RemoveCluster(name string) bool {
 	for i, cluster := range c.Clusters {
 		if cluster.Name == name {
 			c.Clusters = append(c.Clusters[:i], c.Clusters[i+1:]...)
+			if c.Defaults.Cluster == name {
+				c.Defaults.Cluster = ""
+			}
 			return true
 		}
 	}
 	return false
 }
 
-// Save saves the configuration to file
+// Path returns the file the configuration was loaded from
+func (c *Config) Path() string {
+	return c.path
+}
+
+// Save saves the configuration to the file it was loaded from, keeping
+// comments and keys FluxCLI does not know about
 func (c *Config) Save() error {
-	return viper.WriteConfig()
+	if c.path == "" {
+		return fmt.Errorf("configuration was not loaded from a file")
+	}
+	return c.writeConfigFile(c.path)
 }
 
 // SaveTo saves the configuration to the specified file path
 func (c *Config) SaveTo(filepath string) error {
-	return viper.WriteConfigAs(filepath)
+	return c.writeConfigFile(ExpandPath(filepath))
 }
@@ -7,6 +7,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 func TestLoadDefaultConfig(t *testing.T) {
@@ -138,3 +139,40 @@ func TestRemoveCluster(t *testing.T) {
 	assert.False(t, removed)
 	assert.Len(t, config.Clusters, 1)
 }
+
+func TestReadOnlyAndProtected(t *testing.T) {
+	path := t.TempDir() + "/config.yaml"
+	require.NoError(t, os.WriteFile(path, []byte(`clusters:
+  - name: prod
+    context: prod
+    protected: true
+  - name: audit
+    context: audit
+    read_only: true
+  - name: dev
+    context: dev
+`), 0644))
+
+	config, err := Load(path, "", "", "")
+	require.NoError(t, err)
+
+	assert.True(t, config.IsReadOnly("audit"))
+	assert.False(t, config.IsReadOnly("prod"))
+	assert.True(t, config.IsProtected("prod"))
+	assert.False(t, config.IsProtected("dev"))
+
+	// --read-only covers every cluster and survives a reload
+	config.SetReadOnly()
+	reloaded, err := config.Reload()
+	require.NoError(t, err)
+	assert.True(t, reloaded.IsReadOnly("dev"))
+	assert.True(t, reloaded.IsReadOnly("discovered"))
+
+	// The flag is not saved
+	data, err := reloaded.marshal()
+	require.NoError(t, err)
+	var saved Config
+	require.NoError(t, yaml.Unmarshal(data, &saved))
+	assert.False(t, saved.Defaults.ReadOnly)
+	assert.True(t, saved.Clusters[1].ReadOnly)
+}
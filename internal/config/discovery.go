@@ -0,0 +1,227 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// DiscoveryConfig controls importing clusters from kubeconfig contexts
+type DiscoveryConfig struct {
+	Enabled    bool     `yaml:"enabled"`
+	Kubeconfig string   `yaml:"kubeconfig"`
+	Include    []string `yaml:"include"`
+	Exclude    []string `yaml:"exclude"`
+}
+
+// ListContexts returns the sorted context names of the merged kubeconfig.
+// An empty path uses the default loading rules ($KUBECONFIG, ~/.kube/config).
+func ListContexts(kubeconfig string) ([]string, error) {
+	rawConfig, err := loadKubeconfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	contexts := make([]string, 0, len(rawConfig.Contexts))
+	for name := range rawConfig.Contexts {
+		contexts = append(contexts, name)
+	}
+	sort.Strings(contexts)
+
+	return contexts, nil
+}
+
+// CurrentContextName returns the current-context of the merged kubeconfig,
+// or an empty string if it cannot be determined
+func CurrentContextName(kubeconfig string) string {
+	rawConfig, err := loadKubeconfig(kubeconfig)
+	if err != nil {
+		return ""
+	}
+	return rawConfig.CurrentContext
+}
+
+// MatchContext reports whether a context name is selected by the include and
+// exclude patterns. Patterns are shell globs, or regular expressions when
+// wrapped in slashes (e.g. "/^prod-.*$/"). No include patterns selects all.
+func MatchContext(name string, include, exclude []string) (bool, error) {
+	for _, pattern := range exclude {
+		matched, err := matchPattern(pattern, name)
+		if err != nil {
+			return false, err
+		}
+		if matched {
+			return false, nil
+		}
+	}
+
+	if len(include) == 0 {
+		return true, nil
+	}
+
+	for _, pattern := range include {
+		matched, err := matchPattern(pattern, name)
+		if err != nil {
+			return false, err
+		}
+		if matched {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// DiscoverClusters returns a cluster configuration for every kubeconfig
+// context selected by the discovery settings
+func DiscoverClusters(discovery DiscoveryConfig) ([]ClusterConfig, error) {
+	rawConfig, err := loadKubeconfig(discovery.Kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(rawConfig.Contexts))
+	for name := range rawConfig.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	clusters := make([]ClusterConfig, 0, len(names))
+	for _, name := range names {
+		matched, err := MatchContext(name, discovery.Include, discovery.Exclude)
+		if err != nil {
+			return nil, err
+		}
+		if !matched {
+			continue
+		}
+
+		clusters = append(clusters, ClusterConfig{
+			Name:       name,
+			Context:    name,
+			Kubeconfig: discovery.Kubeconfig,
+			Namespace:  rawConfig.Contexts[name].Namespace,
+		})
+	}
+
+	return clusters, nil
+}
+
+// MissingContexts returns the configured clusters whose context no longer
+// exists in their kubeconfig
+func (c *Config) MissingContexts() ([]ClusterConfig, error) {
+	contextsByPath := make(map[string]map[string]bool)
+
+	var missing []ClusterConfig
+	for _, cluster := range c.Clusters {
+		if cluster.Context == "" {
+			continue
+		}
+
+		contexts, ok := contextsByPath[cluster.Kubeconfig]
+		if !ok {
+			names, err := ListContexts(cluster.Kubeconfig)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read kubeconfig for cluster %s: %w", cluster.Name, err)
+			}
+			contexts = make(map[string]bool, len(names))
+			for _, name := range names {
+				contexts[name] = true
+			}
+			contextsByPath[cluster.Kubeconfig] = contexts
+		}
+
+		if !contexts[cluster.Context] {
+			missing = append(missing, cluster)
+		}
+	}
+
+	return missing, nil
+}
+
+// SameKubeconfig reports whether two kubeconfig settings load the same
+// files. Paths are compared with "~" expanded and made absolute; an empty
+// path stands for $KUBECONFIG, then ~/.kube/config.
+func SameKubeconfig(a, b string) bool {
+	return slices.Equal(kubeconfigFiles(a), kubeconfigFiles(b))
+}
+
+// kubeconfigFiles returns the normalized files a kubeconfig setting loads
+func kubeconfigFiles(kubeconfig string) []string {
+	paths := []string{kubeconfig}
+	if kubeconfig == "" {
+		paths = filepath.SplitList(os.Getenv(clientcmd.RecommendedConfigPathEnvVar))
+		if len(paths) == 0 {
+			paths = []string{clientcmd.RecommendedHomeFile}
+		}
+	}
+
+	var files []string
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		path = ExpandPath(path)
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		files = append(files, filepath.Clean(path))
+	}
+	return files
+}
+
+// loadKubeconfig loads the raw (merged) kubeconfig
+func loadKubeconfig(kubeconfig string) (*clientcmdapi.Config, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if kubeconfig != "" {
+		loadingRules.ExplicitPath = ExpandPath(kubeconfig)
+	}
+
+	rawConfig, err := loadingRules.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+
+	return rawConfig, nil
+}
+
+// matchPattern matches a name against a glob or a /regex/ pattern. Unlike
+// path.Match, "*" also matches "/" since context names such as EKS ARNs
+// commonly contain slashes.
+func matchPattern(pattern, name string) (bool, error) {
+	expr := globToRegexp(pattern)
+	if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		expr = pattern[1 : len(pattern)-1]
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return false, fmt.Errorf("invalid context pattern %q: %w", pattern, err)
+	}
+	return re.MatchString(name), nil
+}
+
+// globToRegexp converts a shell glob into an anchored regular expression
+func globToRegexp(glob string) string {
+	var expr strings.Builder
+	expr.WriteString("^")
+	for _, r := range glob {
+		switch r {
+		case '*':
+			expr.WriteString(".*")
+		case '?':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString("$")
+	return expr.String()
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+current-context: kind-dev
+clusters:
+- name: dev
+  cluster:
+    server: https://127.0.0.1:6443
+contexts:
+- name: kind-dev
+  context:
+    cluster: dev
+    user: dev
+    namespace: apps
+- name: prod-eu
+  context:
+    cluster: dev
+    user: dev
+- name: arn:aws:eks:eu-west-1:123456789012:cluster/prod-us
+  context:
+    cluster: dev
+    user: dev
+users:
+- name: dev
+  user:
+    token: secret
+`
+
+func writeTestKubeconfig(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	require.NoError(t, os.WriteFile(path, []byte(testKubeconfig), 0600))
+	return path
+}
+
+func TestMatchContext(t *testing.T) {
+	matched, err := MatchContext("prod-eu", nil, nil)
+	require.NoError(t, err)
+	assert.True(t, matched)
+
+	matched, err = MatchContext("prod-eu", []string{"prod-*"}, nil)
+	require.NoError(t, err)
+	assert.True(t, matched)
+
+	matched, err = MatchContext("prod-eu", []string{"prod-*"}, []string{"*-eu"})
+	require.NoError(t, err)
+	assert.False(t, matched)
+
+	matched, err = MatchContext("arn:aws:eks:eu-west-1:123456789012:cluster/prod-us", []string{"*/prod-*"}, nil)
+	require.NoError(t, err)
+	assert.True(t, matched)
+
+	matched, err = MatchContext("kind-dev", []string{"/^kind-/"}, nil)
+	require.NoError(t, err)
+	assert.True(t, matched)
+
+	_, err = MatchContext("kind-dev", []string{"/[/"}, nil)
+	assert.Error(t, err)
+}
+
+func TestDiscoverClusters(t *testing.T) {
+	path := writeTestKubeconfig(t)
+
+	clusters, err := DiscoverClusters(DiscoveryConfig{
+		Kubeconfig: path,
+		Exclude:    []string{"prod-*"},
+	})
+	require.NoError(t, err)
+	require.Len(t, clusters, 2)
+
+	assert.Equal(t, "arn:aws:eks:eu-west-1:123456789012:cluster/prod-us", clusters[0].Name)
+	assert.Equal(t, "kind-dev", clusters[1].Name)
+	assert.Equal(t, "kind-dev", clusters[1].Context)
+	assert.Equal(t, "apps", clusters[1].Namespace)
+	assert.Equal(t, path, clusters[1].Kubeconfig)
+
+	assert.Equal(t, "kind-dev", CurrentContextName(path))
+}
+
+func TestMissingContexts(t *testing.T) {
+	path := writeTestKubeconfig(t)
+
+	cfg := &Config{}
+	cfg.AddCluster(ClusterConfig{Name: "dev", Context: "kind-dev", Kubeconfig: path})
+	cfg.AddCluster(ClusterConfig{Name: "old", Context: "kind-old", Kubeconfig: path})
+
+	missing, err := cfg.MissingContexts()
+	require.NoError(t, err)
+	require.Len(t, missing, 1)
+	assert.Equal(t, "old", missing[0].Name)
+}
+
+func TestSameKubeconfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("KUBECONFIG", "")
+	t.Chdir(home)
+
+	defaultPath := filepath.Join(home, ".kube", "config")
+	assert.True(t, SameKubeconfig("~/.kube/config", defaultPath))
+	assert.True(t, SameKubeconfig(".kube/../.kube/config", "~/.kube/config"))
+	assert.False(t, SameKubeconfig("~/.kube/prod", defaultPath))
+
+	// Without a path, $KUBECONFIG and then ~/.kube/config are loaded
+	t.Setenv("KUBECONFIG", filepath.Join(home, "prod"))
+	assert.True(t, SameKubeconfig("", "~/prod"))
+	assert.False(t, SameKubeconfig("", defaultPath))
+}
@@ -0,0 +1,259 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// KeyBindingsConfig maps UI actions to keys. Actions that are not set keep
+// their default keys, see KeyActions.
+type KeyBindingsConfig struct {
+	Up               []string `yaml:"up"`
+	Down             []string `yaml:"down"`
+	PageUp           []string `yaml:"page_up"`
+	PageDown         []string `yaml:"page_down"`
+	Top              []string `yaml:"top"`
+	Bottom           []string `yaml:"bottom"`
+	ViewTop          []string `yaml:"view_top"`
+	ViewMiddle       []string `yaml:"view_middle"`
+	ViewBottom       []string `yaml:"view_bottom"`
+	Select           []string `yaml:"select"`
+	Back             []string `yaml:"back"`
+	SwitchView       []string `yaml:"switch_view"`
+	Zoom             []string `yaml:"zoom"`
+	GrowEvents       []string `yaml:"grow_events"`
+	ShrinkEvents     []string `yaml:"shrink_events"`
+	GitRepositories  []string `yaml:"git_repositories"`
+	HelmRepositories []string `yaml:"helm_repositories"`
+	Kustomizations   []string `yaml:"kustomizations"`
+	HelmReleases     []string `yaml:"helm_releases"`
+	Diagnostics      []string `yaml:"diagnostics"`
+	SortName         []string `yaml:"sort_name"`
+	SortStatus       []string `yaml:"sort_status"`
+	SortAge          []string `yaml:"sort_age"`
+	SortTransition   []string `yaml:"sort_transition"`
+	SortCluster      []string `yaml:"sort_cluster"`
+	Wide             []string `yaml:"wide"`
+	ObjectEvents     []string `yaml:"object_events"`
+	EventType        []string `yaml:"event_type"`
+	Follow           []string `yaml:"follow"`
+	Logs             []string `yaml:"logs"`
+	LogLevel         []string `yaml:"log_level"`
+	NextCluster      []string `yaml:"next_cluster"`
+	PreviousCluster  []string `yaml:"previous_cluster"`
+	Reconcile        []string `yaml:"reconcile"`
+	Suspend          []string `yaml:"suspend"`
+	Resume           []string `yaml:"resume"`
+	Refresh          []string `yaml:"refresh"`
+	Filter           []string `yaml:"filter"`
+	Command          []string `yaml:"command"`
+	Help             []string `yaml:"help"`
+	Quit             []string `yaml:"quit"`
+}
+
+// KeyAction describes an action that can be bound to keys
+type KeyAction struct {
+	Name        string // Key in the keybindings section
+	Group       string // Heading in the help screen
+	Description string
+	Keys        []string // Default keys
+}
+
+// KeyActions lists every bindable action in the order of the help screen.
+// All actions share one key space: a key may only trigger a single action.
+var KeyActions = []KeyAction{
+	{Name: "up", Group: "Navigation", Description: "Move up", Keys: []string{"k", "up"}},
+	{Name: "down", Group: "Navigation", Description: "Move down", Keys: []string{"j", "down"}},
+	{Name: "page_up", Group: "Navigation", Description: "Page up", Keys: []string{"ctrl+u", "pgup"}},
+	{Name: "page_down", Group: "Navigation", Description: "Page down", Keys: []string{"ctrl+d", "pgdown"}},
+	{Name: "top", Group: "Navigation", Description: "Go to the first item", Keys: []string{"g", "home"}},
+	{Name: "bottom", Group: "Navigation", Description: "Go to the last item", Keys: []string{"G", "end"}},
+	{Name: "view_top", Group: "Navigation", Description: "Top of the view", Keys: []string{"H"}},
+	{Name: "view_middle", Group: "Navigation", Description: "Middle of the view", Keys: []string{"M"}},
+	{Name: "view_bottom", Group: "Navigation", Description: "Bottom of the view", Keys: []string{"L"}},
+	{Name: "select", Group: "Navigation", Description: "View details, switch to the selected cluster", Keys: []string{"enter", "space"}},
+	{Name: "back", Group: "Navigation", Description: "Close the detail, log or diagnostics view", Keys: []string{"esc"}},
+	{Name: "switch_view", Group: "Views", Description: "Switch focus between the resource and event panes", Keys: []string{"tab", "shift+tab"}},
+	{Name: "zoom", Group: "Views", Description: "Maximize the focused pane or restore the split", Keys: []string{"z"}},
+	{Name: "grow_events", Group: "Views", Description: "Grow the events pane", Keys: []string{"+"}},
+	{Name: "shrink_events", Group: "Views", Description: "Shrink the events pane", Keys: []string{"-"}},
+	{Name: "git_repositories", Group: "Views", Description: "GitRepositories", Keys: []string{"1"}},
+	{Name: "helm_repositories", Group: "Views", Description: "HelmRepositories", Keys: []string{"2"}},
+	{Name: "kustomizations", Group: "Views", Description: "Kustomizations", Keys: []string{"3"}},
+	{Name: "helm_releases", Group: "Views", Description: "HelmReleases", Keys: []string{"4"}},
+	{Name: "diagnostics", Group: "Views", Description: "Diagnose connectivity, Flux APIs and RBAC of the current cluster", Keys: []string{"D"}},
+	{Name: "sort_name", Group: "Table", Description: "Sort by name, again to reverse", Keys: []string{"N"}},
+	{Name: "sort_status", Group: "Table", Description: "Sort by health and status, again to reverse", Keys: []string{"S"}},
+	{Name: "sort_age", Group: "Table", Description: "Sort by age, again to reverse", Keys: []string{"A"}},
+	{Name: "sort_transition", Group: "Table", Description: "Sort by last transition, again to reverse", Keys: []string{"T"}},
+	{Name: "sort_cluster", Group: "Table", Description: "Sort by cluster, again to reverse", Keys: []string{"C"}},
+	{Name: "wide", Group: "Table", Description: "Toggle wide mode with revision, interval and reconcile details", Keys: []string{"W"}},
+	{Name: "object_events", Group: "Events", Description: "Show the events of the selected resource or event object, or toggle the object filter of the log view", Keys: []string{"e"}},
+	{Name: "event_type", Group: "Events", Description: "Cycle the event type filter: Warning, Normal, all", Keys: []string{"w"}},
+	{Name: "follow", Group: "Events", Description: "Follow new events or log lines", Keys: []string{"F"}},
+	{Name: "logs", Group: "Logs", Description: "Show the controller log of the selected resource", Keys: []string{"l"}},
+	{Name: "log_level", Group: "Logs", Description: "Cycle the log level filter: info, error, all", Keys: []string{"V"}},
+	{Name: "next_cluster", Group: "Clusters", Description: "Next cluster", Keys: []string{"ctrl+j"}},
+	{Name: "previous_cluster", Group: "Clusters", Description: "Previous cluster", Keys: []string{"ctrl+k"}},
+	{Name: "reconcile", Group: "Operations", Description: "Reconcile the selected resource", Keys: []string{"f"}},
+	{Name: "suspend", Group: "Operations", Description: "Suspend the selected resource", Keys: []string{"P"}},
+	{Name: "resume", Group: "Operations", Description: "Resume the selected resource", Keys: []string{"U"}},
+	{Name: "refresh", Group: "Other", Description: "Refresh, or run the diagnostics again", Keys: []string{"r", "ctrl+r", "f5"}},
+	{Name: "filter", Group: "Other", Description: "Search the log view", Keys: []string{"/"}},
+	{Name: "command", Group: "Other", Description: "Command mode", Keys: []string{":"}},
+	{Name: "help", Group: "Other", Description: "Toggle help", Keys: []string{"?"}},
+	{Name: "quit", Group: "Other", Description: "Quit", Keys: []string{"q", "ctrl+c"}},
+}
+
+// keyNames are the named keys accepted in key bindings, besides single
+// characters. Modifiers are given as prefixes, e.g. "ctrl+k" or "alt+enter".
+var keyNames = []string{
+	"up", "down", "left", "right", "home", "end", "pgup", "pgdown",
+	"enter", "tab", "esc", "backspace", "delete", "insert", " ",
+	"f1", "f2", "f3", "f4", "f5", "f6", "f7", "f8", "f9", "f10",
+	"f11", "f12", "f13", "f14", "f15", "f16", "f17", "f18", "f19", "f20",
+}
+
+// keyAliases maps alternative spellings to the names bubbletea reports
+var keyAliases = map[string]string{
+	"space":    " ",
+	"pageup":   "pgup",
+	"pagedown": "pgdown",
+	"del":      "delete",
+	"escape":   "esc",
+	"return":   "enter",
+}
+
+// DefaultKeyBindings returns the default keys of every action
+func DefaultKeyBindings() KeyBindingsConfig {
+	return KeyBindingsConfig{}.WithDefaults()
+}
+
+// WithDefaults returns a copy with the default keys filled in for actions
+// that are not set
+func (k KeyBindingsConfig) WithDefaults() KeyBindingsConfig {
+	value := reflect.ValueOf(&k).Elem()
+	fields := yamlFields(value.Type())
+	for _, action := range KeyActions {
+		field := value.FieldByIndex(fields[action.Name].Index)
+		if field.Len() == 0 {
+			field.Set(reflect.ValueOf(slices.Clone(action.Keys)))
+		}
+	}
+	return k
+}
+
+// Keys returns the keys bound to an action, normalized to the names
+// bubbletea reports for key presses
+func (k KeyBindingsConfig) Keys(action string) []string {
+	keys := k.configured(action)
+	if len(keys) == 0 {
+		for _, a := range KeyActions {
+			if a.Name == action {
+				keys = a.Keys
+				break
+			}
+		}
+	}
+
+	normalized := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if name, err := NormalizeKey(key); err == nil {
+			normalized = append(normalized, name)
+		}
+	}
+	return normalized
+}
+
+// configured returns the keys set in the configuration for an action
+func (k KeyBindingsConfig) configured(action string) []string {
+	field, ok := yamlFields(reflect.TypeOf(k))[action]
+	if !ok {
+		return nil
+	}
+	return reflect.ValueOf(k).FieldByIndex(field.Index).Interface().([]string)
+}
+
+// NormalizeKey converts a key as written in the configuration, such as
+// "Ctrl+K", "PageDown" or "space", to the name bubbletea reports for it
+func NormalizeKey(key string) (string, error) {
+	if key == " " {
+		return key, nil
+	}
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return "", fmt.Errorf("empty key")
+	}
+	if len([]rune(key)) == 1 {
+		return key, nil
+	}
+
+	parts := strings.Split(key, "+")
+	if len(parts) > 1 && parts[len(parts)-1] == "" {
+		// "ctrl++" binds the plus key
+		parts = append(parts[:len(parts)-2], "+")
+	}
+	name := parts[len(parts)-1]
+	modifiers := parts[:len(parts)-1]
+
+	ctrl := false
+	for i, modifier := range modifiers {
+		modifier = strings.ToLower(modifier)
+		if modifier != "ctrl" && modifier != "alt" && modifier != "shift" {
+			return "", fmt.Errorf("unknown modifier %q in %q, expected ctrl, alt or shift", modifiers[i], key)
+		}
+		ctrl = ctrl || modifier == "ctrl"
+		modifiers[i] = modifier
+	}
+
+	if len([]rune(name)) > 1 || ctrl {
+		// Named keys and control characters are case insensitive
+		name = strings.ToLower(name)
+	}
+	if alias, ok := keyAliases[name]; ok {
+		name = alias
+	}
+	if len([]rune(name)) > 1 && !slices.Contains(keyNames, name) {
+		return "", fmt.Errorf("unknown key %q", key)
+	}
+
+	return strings.Join(append(modifiers, name), "+"), nil
+}
+
+// KeyLabel renders a normalized key for the footer and help screen
+func KeyLabel(key string) string {
+	if key == " " {
+		return "space"
+	}
+	return key
+}
+
+// checkKeyBindings reports invalid keys and keys bound to several actions
+func (v *validator) checkKeyBindings(c *Config) {
+	owners := make(map[string]string)
+	for _, action := range KeyActions {
+		configured := c.KeyBindings.configured(action.Name)
+		for i, key := range configured {
+			if _, err := NormalizeKey(key); err != nil {
+				v.errorf(at("keybindings", action.Name, i), "%v", err)
+			}
+		}
+
+		for _, key := range c.KeyBindings.Keys(action.Name) {
+			owner, taken := owners[key]
+			if !taken {
+				owners[key] = action.Name
+				continue
+			}
+
+			// Point at whichever of the two bindings the user wrote
+			p := at("keybindings", action.Name)
+			if len(configured) == 0 {
+				p = at("keybindings", owner)
+			}
+			v.errorf(p, "key %q is bound to both %s and %s", KeyLabel(key), owner, action.Name)
+		}
+	}
+}
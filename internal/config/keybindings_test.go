@@ -0,0 +1,78 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyActionsMatchConfig(t *testing.T) {
+	fields := yamlFields(reflect.TypeOf(KeyBindingsConfig{}))
+	require.Len(t, KeyActions, len(fields))
+	for _, action := range KeyActions {
+		assert.Contains(t, fields, action.Name)
+	}
+
+	// The defaults must not conflict with each other
+	issues := newConfig().Validate()
+	assert.Empty(t, issues)
+}
+
+func TestNormalizeKey(t *testing.T) {
+	for key, want := range map[string]string{
+		"q":        "q",
+		"G":        "G",
+		"Ctrl+K":   "ctrl+k",
+		"ctrl+R":   "ctrl+r",
+		"alt+G":    "alt+G",
+		"F5":       "f5",
+		"PageDown": "pgdown",
+		"space":    " ",
+		"Del":      "delete",
+		"ctrl++":   "ctrl++",
+	} {
+		got, err := NormalizeKey(key)
+		require.NoError(t, err, key)
+		assert.Equal(t, want, got, key)
+	}
+
+	for _, key := range []string{"", "hyper+k", "ctrl+foo", "F42"} {
+		_, err := NormalizeKey(key)
+		assert.Error(t, err, key)
+	}
+}
+
+func TestKeyBindingsDefaults(t *testing.T) {
+	bindings := KeyBindingsConfig{Refresh: []string{"R", "F5"}}
+
+	assert.Equal(t, []string{"R", "f5"}, bindings.Keys("refresh"))
+	assert.Equal(t, []string{"q", "ctrl+c"}, bindings.Keys("quit"))
+	assert.Equal(t, []string{"enter", " "}, bindings.Keys("select"))
+	assert.Equal(t, []string{"R", "F5"}, bindings.WithDefaults().Refresh)
+	assert.Equal(t, []string{"q", "ctrl+c"}, bindings.WithDefaults().Quit)
+}
+
+func TestValidateKeyBindings(t *testing.T) {
+	path := writeTestConfig(t, `keybindings:
+  refresh: ["f"]
+  up: ["P", "Up"]
+  quit: ["q", "hyper+q"]
+  resmue: ["u"]
+`)
+
+	issues, err := ValidateFile(path)
+	require.NoError(t, err)
+
+	got := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		got = append(got, string(issue.Severity)+" "+issue.String())
+	}
+	assert.Equal(t, []string{
+		`error 2:3: keybindings.refresh: key "f" is bound to both reconcile and refresh`,
+		`error 3:3: keybindings.up: key "P" is bound to both up and suspend`,
+		`error 4:15: keybindings.quit[1]: unknown modifier "hyper" in "hyper+q", expected ctrl, alt or shift`,
+		`warning 5:3: keybindings.resmue: unknown key, did you mean "resume"?`,
+	}, got)
+}
@@ -0,0 +1,354 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix prefixes the environment variables that override config keys,
+// e.g. FLUXCLI_DEFAULTS_REFRESH_INTERVAL for defaults.refresh_interval
+const EnvPrefix = "FLUXCLI_"
+
+// ProfileEnv selects a profile when --profile is not given
+const ProfileEnv = EnvPrefix + "PROFILE"
+
+// profilesKey is the top level key holding the named profiles. A profile
+// accepts the same keys as the top level and overrides them: mappings are
+// merged key by key, lists replace the top level list.
+const profilesKey = "profiles"
+
+// SourceKind identifies where a configuration value came from
+type SourceKind string
+
+const (
+	SourceDefault    SourceKind = "default"
+	SourceFile       SourceKind = "file"
+	SourceProfile    SourceKind = "profile"
+	SourceEnv        SourceKind = "env"
+	SourceFlag       SourceKind = "flag"
+	SourceKubeconfig SourceKind = "kubeconfig"
+	SourceCluster    SourceKind = "cluster"
+)
+
+// Source describes where a configuration value came from
+type Source struct {
+	Kind   SourceKind
+	Detail string // Profile name, environment variable, flag or cluster
+}
+
+// String renders the source as e.g. "profile prod" or "env FLUXCLI_UI_THEME"
+func (s Source) String() string {
+	if s.Detail == "" {
+		return string(s.Kind)
+	}
+	return fmt.Sprintf("%s %s", s.Kind, s.Detail)
+}
+
+// Source returns where the value at a dotted key path came from. Values
+// inside lists report the source of the list.
+func (c *Config) Source(path string) Source {
+	for {
+		if source, ok := c.sources[path]; ok {
+			return source
+		}
+		i := strings.LastIndexAny(path, ".[")
+		if i < 0 {
+			return Source{Kind: SourceDefault}
+		}
+		path = path[:i]
+	}
+}
+
+// setSource records the source of the value at a dotted key path
+func (c *Config) setSource(path string, source Source) {
+	if c.sources == nil {
+		c.sources = make(map[string]Source)
+	}
+	c.sources[path] = source
+}
+
+// ProfileNames returns the names of the profiles defined in the config file
+func (c *Config) ProfileNames() []string {
+	profiles := c.profilesNode()
+	if profiles == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(profiles.Content)/2)
+	for i := 0; i+1 < len(profiles.Content); i += 2 {
+		names = append(names, profiles.Content[i].Value)
+	}
+	return names
+}
+
+// profilesNode returns the mapping of profiles in the loaded document
+func (c *Config) profilesNode() *yaml.Node {
+	root := c.root()
+	if root == nil {
+		return nil
+	}
+	if profiles := mappingValue(root, profilesKey); profiles != nil && profiles.Kind == yaml.MappingNode {
+		return profiles
+	}
+	return nil
+}
+
+// profileNode returns the mapping of a single profile
+func (c *Config) profileNode(name string) *yaml.Node {
+	profiles := c.profilesNode()
+	if profiles == nil {
+		return nil
+	}
+	if profile := mappingValue(profiles, name); profile != nil && profile.Kind == yaml.MappingNode {
+		return profile
+	}
+	return nil
+}
+
+// applyProfile overlays a named profile on the values read from the file
+func (c *Config) applyProfile(name string) error {
+	if name == "" {
+		return nil
+	}
+
+	profile := c.profileNode(name)
+	if profile == nil {
+		available := c.ProfileNames()
+		if len(available) == 0 {
+			return fmt.Errorf("profile %q not found: %s defines no profiles", name, c.path)
+		}
+		return fmt.Errorf("profile %q not found in %s (available: %s)", name, c.path, strings.Join(available, ", "))
+	}
+
+	c.Profile = name
+	recordSources(c, profile, "", Source{Kind: SourceProfile, Detail: name})
+
+	if err := profile.Decode(c); err != nil {
+		typeErr, ok := err.(*yaml.TypeError)
+		if !ok {
+			return fmt.Errorf("failed to decode profile %s: %w", name, err)
+		}
+		issues := make([]ValidationIssue, 0, len(typeErr.Errors))
+		for _, message := range typeErr.Errors {
+			issues = append(issues, yamlIssue(message, c.document))
+		}
+		return &ValidationError{File: c.path, Issues: issues}
+	}
+
+	return nil
+}
+
+// recordSources marks every value set by a mapping node with source. Lists
+// are recorded as a whole since they replace rather than merge.
+func recordSources(c *Config, node *yaml.Node, prefix string, source Source) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, node.Content[i+1]
+		if prefix == "" && key == profilesKey {
+			continue
+		}
+		path := strings.TrimPrefix(prefix+"."+key, ".")
+		if value.Kind == yaml.MappingNode {
+			recordSources(c, value, path, source)
+			continue
+		}
+		c.setSource(path, source)
+	}
+}
+
+// envVar returns the environment variable that overrides a dotted key path
+func envVar(path string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(path, ".", "_"))
+}
+
+// applyEnv overrides values with FLUXCLI_* environment variables. Lists of
+// strings are given comma separated; lists of clusters cannot be overridden.
+func (c *Config) applyEnv() []ValidationIssue {
+	var issues []ValidationIssue
+
+	walkFields(reflect.ValueOf(c).Elem(), "", func(path string, field reflect.Value) {
+		name := envVar(path)
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return
+		}
+
+		node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+		switch {
+		case field.Kind() == reflect.Slice:
+			node = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+			for _, item := range strings.Split(value, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: item})
+				}
+			}
+		case field.Kind() != reflect.String:
+			// Let YAML resolve booleans and numbers
+			node.Tag = ""
+		}
+
+		target := reflect.New(field.Type())
+		if err := node.Decode(target.Interface()); err != nil {
+			message := err.Error()
+			if typeErr, ok := err.(*yaml.TypeError); ok && len(typeErr.Errors) > 0 {
+				message = typeErr.Errors[0]
+			}
+			issues = append(issues, ValidationIssue{
+				Severity: SeverityError,
+				Path:     path,
+				Message:  fmt.Sprintf("%s=%q: %s", name, value, strings.TrimPrefix(message, "line 0: ")),
+			})
+			return
+		}
+
+		field.Set(target.Elem())
+		c.setSource(path, Source{Kind: SourceEnv, Detail: name})
+	})
+
+	return issues
+}
+
+// walkFields calls fn for every value of v that can be set from a single
+// string: scalars, durations and lists of strings
+func walkFields(v reflect.Value, prefix string, fn func(path string, field reflect.Value)) {
+	t := v.Type()
+	for name, field := range yamlFields(t) {
+		path := strings.TrimPrefix(prefix+"."+name, ".")
+		value := v.FieldByIndex(field.Index)
+
+		switch {
+		case field.Type == reflect.TypeOf(time.Duration(0)):
+			fn(path, value)
+		case value.Kind() == reflect.Struct:
+			walkFields(value, path, fn)
+		case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.String:
+			fn(path, value)
+		case value.Kind() == reflect.String, value.Kind() == reflect.Bool, value.Kind() == reflect.Int:
+			fn(path, value)
+		}
+	}
+}
+
+// EnvVars returns the environment variables that override config keys,
+// keyed by their dotted key path
+func EnvVars() map[string]string {
+	vars := make(map[string]string)
+	walkFields(reflect.ValueOf(newConfig()).Elem(), "", func(path string, _ reflect.Value) {
+		vars[path] = envVar(path)
+	})
+	return vars
+}
+
+// EffectiveYAML renders the merged configuration with a comment on every
+// value naming its source
+func (c *Config) EffectiveYAML() ([]byte, error) {
+	effective := *c
+	effective.KeyBindings = c.KeyBindings.WithDefaults()
+
+	var node yaml.Node
+	if err := node.Encode(&effective); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	c.annotateSources(&node, "")
+
+	header := fmt.Sprintf("Effective configuration from %s", c.path)
+	if c.Profile != "" {
+		header += fmt.Sprintf(" with profile %s", c.Profile)
+	}
+	node.HeadComment = header
+
+	return marshalNode(&node)
+}
+
+// annotateSources sets the line comment of every value below node
+func (c *Config) annotateSources(node *yaml.Node, prefix string) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		path := strings.TrimPrefix(prefix+"."+key.Value, ".")
+		if value.Kind == yaml.MappingNode {
+			c.annotateSources(value, path)
+			continue
+		}
+		if value.Kind == yaml.SequenceNode && len(value.Content) > 0 && value.Content[0].Kind != yaml.ScalarNode {
+			// Leave out the unset fields of list items such as clusters
+			for _, item := range value.Content {
+				pruneNode(item)
+			}
+			key.LineComment = c.Source(path).String()
+			continue
+		}
+		if value.Kind == yaml.SequenceNode {
+			// A block sequence would move the comment to the next key
+			value.Style = yaml.FlowStyle
+		}
+		value.LineComment = c.Source(path).String()
+	}
+}
+
+// splitLayers divides an encoded configuration into the values that belong
+// in the top level of the file and those that belong in the active profile.
+// Values overridden by environment variables or flags are left out of both
+// so that a save never persists them.
+func (c *Config) splitLayers(node *yaml.Node, prefix string) (base, profile *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		return node, nil
+	}
+
+	base = &yaml.Node{Kind: yaml.MappingNode, Tag: node.Tag}
+	profile = &yaml.Node{Kind: yaml.MappingNode, Tag: node.Tag}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		path := strings.TrimPrefix(prefix+"."+key.Value, ".")
+
+		if value.Kind == yaml.MappingNode {
+			b, p := c.splitLayers(value, path)
+			if len(b.Content) > 0 {
+				base.Content = append(base.Content, key, b)
+			}
+			if len(p.Content) > 0 {
+				profile.Content = append(profile.Content, key, p)
+			}
+			continue
+		}
+
+		switch c.sources[path].Kind {
+		case SourceEnv, SourceFlag:
+		case SourceProfile:
+			profile.Content = append(profile.Content, key, value)
+		default:
+			base.Content = append(base.Content, key, value)
+		}
+	}
+	return base, profile
+}
+
+// checkProfileKeys reports unknown keys inside each profile
+func (v *validator) checkProfileKeys(profiles *yaml.Node) {
+	for i := 0; i+1 < len(profiles.Content); i += 2 {
+		name, profile := profiles.Content[i].Value, profiles.Content[i+1]
+		if profile.Kind != yaml.MappingNode {
+			v.errorf(at(profilesKey, name), "profile must be a mapping of config keys")
+			continue
+		}
+		v.checkKeys(profile, reflect.TypeOf(Config{}), at(profilesKey, name))
+	}
+}
+
+// profileLocation maps a key path to its location in the file when its
+// value came from the active profile
+func (c *Config) profileLocation(p keyPath) keyPath {
+	if c.Profile == "" || c.Source(p.String()).Kind != SourceProfile {
+		return p
+	}
+	return append(at(profilesKey, c.Profile), p...)
+}
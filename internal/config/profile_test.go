@@ -0,0 +1,165 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const profileConfigFile = `clusters:
+  - name: base
+    context: base-ctx
+defaults:
+  namespace: apps
+  refresh_interval: 10s
+ui:
+  theme: dark
+profiles:
+  # Production fleet
+  prod:
+    clusters:
+      - name: prod-eu
+        context: prod-eu
+    defaults:
+      refresh_interval: 30s
+      cluster: prod-eu
+  staging:
+    ui:
+      columns_name: 0
+`
+
+func TestLoadProfile(t *testing.T) {
+	path := writeTestConfig(t, profileConfigFile)
+
+	cfg, err := LoadProfile(path, "prod", "", "", "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "prod", cfg.Profile)
+	assert.Equal(t, []string{"prod", "staging"}, cfg.ProfileNames())
+	require.Len(t, cfg.Clusters, 1)
+	assert.Equal(t, "prod-eu", cfg.Clusters[0].Name)
+	assert.Equal(t, 30*time.Second, cfg.Defaults.RefreshInterval)
+	assert.Equal(t, "apps", cfg.Defaults.Namespace, "keys missing from the profile keep the top level value")
+	assert.Equal(t, "prod-eu", cfg.CurrentCluster)
+
+	assert.Equal(t, Source{Kind: SourceProfile, Detail: "prod"}, cfg.Source("defaults.refresh_interval"))
+	assert.Equal(t, Source{Kind: SourceProfile, Detail: "prod"}, cfg.Source("clusters[0].name"))
+	assert.Equal(t, Source{Kind: SourceFile}, cfg.Source("defaults.namespace"))
+	assert.Equal(t, Source{Kind: SourceDefault}, cfg.Source("defaults.max_concurrent_clusters"))
+	assert.Equal(t, Source{Kind: SourceFlag, Detail: "--profile"}, cfg.Source("current.profile"))
+}
+
+func TestLoadProfileFromEnv(t *testing.T) {
+	path := writeTestConfig(t, profileConfigFile)
+	t.Setenv(ProfileEnv, "prod")
+
+	cfg, err := Load(path, "", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, "prod", cfg.Profile)
+	assert.Equal(t, Source{Kind: SourceEnv, Detail: ProfileEnv}, cfg.Source("current.profile"))
+
+	_, err = LoadProfile(path, "missing", "", "", "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "available: prod, staging")
+}
+
+func TestLoadInvalidProfile(t *testing.T) {
+	path := writeTestConfig(t, profileConfigFile)
+
+	_, err := LoadProfile(path, "staging", "", "", "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), path+":20:7: profiles.staging.ui.columns_name: must be at least 1")
+}
+
+func TestEnvOverrides(t *testing.T) {
+	path := writeTestConfig(t, profileConfigFile)
+	t.Setenv("FLUXCLI_DEFAULTS_REFRESH_INTERVAL", "1m")
+	t.Setenv("FLUXCLI_UI_SHOW_AGE", "false")
+	t.Setenv("FLUXCLI_DEFAULTS_MAX_CONCURRENT_CLUSTERS", "2")
+	t.Setenv("FLUXCLI_DISCOVERY_INCLUDE", "prod-*, staging-*")
+
+	cfg, err := LoadProfile(path, "prod", "", "", "")
+	require.NoError(t, err)
+
+	assert.Equal(t, time.Minute, cfg.Defaults.RefreshInterval, "environment variables win over profiles")
+	assert.False(t, cfg.UI.ShowAge)
+	assert.Equal(t, 2, cfg.Defaults.MaxConcurrentClusters)
+	assert.Equal(t, []string{"prod-*", "staging-*"}, cfg.Discovery.Include)
+	assert.Equal(t, Source{Kind: SourceEnv, Detail: "FLUXCLI_DEFAULTS_REFRESH_INTERVAL"}, cfg.Source("defaults.refresh_interval"))
+
+	assert.Equal(t, "FLUXCLI_UI_COLUMNS_NAME", EnvVars()["ui.columns_name"])
+}
+
+func TestEnvOverridesInvalid(t *testing.T) {
+	path := writeTestConfig(t, profileConfigFile)
+
+	t.Setenv("FLUXCLI_DEFAULTS_REFRESH_INTERVAL", "soon")
+	_, err := Load(path, "", "", "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `FLUXCLI_DEFAULTS_REFRESH_INTERVAL="soon"`)
+
+	t.Setenv("FLUXCLI_DEFAULTS_REFRESH_INTERVAL", "0s")
+	_, err = Load(path, "", "", "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "defaults.refresh_interval: must be a positive duration such as 5s, got 0s (set by FLUXCLI_DEFAULTS_REFRESH_INTERVAL)")
+}
+
+func TestSaveWithProfileAndEnv(t *testing.T) {
+	path := writeTestConfig(t, profileConfigFile)
+	t.Setenv("FLUXCLI_UI_THEME", "light")
+
+	cfg, err := LoadProfile(path, "prod", "", "", "")
+	require.NoError(t, err)
+	cfg.AddCluster(ClusterConfig{Name: "prod-us", Context: "prod-us"})
+	cfg.Defaults.Namespace = "flux-system"
+	require.NoError(t, cfg.Save())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# Production fleet")
+	assert.Contains(t, string(data), "theme: dark", "environment overrides are not persisted")
+
+	base, err := LoadProfile(path, "", "", "", "")
+	require.NoError(t, err)
+	require.Len(t, base.Clusters, 1, "clusters of the profile stay in the profile")
+	assert.Equal(t, "base", base.Clusters[0].Name)
+	assert.Equal(t, 10*time.Second, base.Defaults.RefreshInterval)
+	assert.Equal(t, "flux-system", base.Defaults.Namespace)
+
+	prod, err := LoadProfile(path, "prod", "", "", "")
+	require.NoError(t, err)
+	require.Len(t, prod.Clusters, 2)
+	assert.Equal(t, "prod-us", prod.Clusters[1].Name)
+}
+
+func TestValidateFileProfiles(t *testing.T) {
+	path := writeTestConfig(t, profileConfigFile+"  typo:\n    uii:\n      theme: dark\n")
+
+	issues, err := ValidateFile(path)
+	require.NoError(t, err)
+	require.Len(t, issues, 2)
+	assert.Equal(t, "20:7: profiles.staging.ui.columns_name: must be at least 1, got 0", issues[0].String())
+	assert.Equal(t, `22:5: profiles.typo.uii: unknown key, did you mean "ui"?`, issues[1].String())
+}
+
+func TestEffectiveYAML(t *testing.T) {
+	path := writeTestConfig(t, profileConfigFile)
+	t.Setenv("FLUXCLI_UI_COLUMNS_STATUS", "20")
+
+	cfg, err := LoadProfile(path, "prod", "", "", "")
+	require.NoError(t, err)
+
+	data, err := cfg.EffectiveYAML()
+	require.NoError(t, err)
+	content := string(data)
+	assert.Contains(t, content, "with profile prod")
+	assert.Contains(t, content, "clusters: # profile prod")
+	assert.Contains(t, content, "refresh_interval: 30s # profile prod")
+	assert.Contains(t, content, "namespace: apps # file")
+	assert.Contains(t, content, "columns_status: 20 # env FLUXCLI_UI_COLUMNS_STATUS")
+	assert.Contains(t, content, "max_concurrent_clusters: 10 # default")
+	assert.NotContains(t, content, "description: \"\"")
+}
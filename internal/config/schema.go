@@ -0,0 +1,221 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+//go:generate go run ../../main.go config schema --output ../../docs/config.schema.json
+
+// jsonSchema is the subset of JSON Schema (draft 2020-12) used to describe
+// the configuration file
+type jsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties any                    `json:"additionalProperties,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	Minimum              *int                   `json:"minimum,omitempty"`
+	Default              any                    `json:"default,omitempty"`
+}
+
+// schemaField documents a configuration key in the generated schema
+type schemaField struct {
+	description string
+	enum        []string
+	pattern     string
+	minimum     *int
+	required    bool
+}
+
+func minimum(n int) *int {
+	return &n
+}
+
+// schemaFields holds the documentation and constraints of each key, keyed by
+// its dotted path. Sequence items and map values share the path of their
+// sequence or map.
+var schemaFields = map[string]schemaField{
+	"clusters":                         {description: "Clusters FluxCLI connects to"},
+	"clusters.name":                    {description: "Unique name of the cluster", required: true},
+	"clusters.context":                 {description: "Kubeconfig context of the cluster", required: true},
+	"clusters.kubeconfig":              {description: "Kubeconfig file, defaults to $KUBECONFIG or ~/.kube/config"},
+	"clusters.namespace":               {description: "Namespace shown when switching to the cluster"},
+	"clusters.color":                   {description: "Color used to highlight the cluster, an ANSI color number or hex code", pattern: colorPattern.String()},
+	"clusters.description":             {description: "Free form description"},
+	"clusters.read_only":               {description: "Block suspend, resume and reconcile on the cluster"},
+	"clusters.protected":               {description: "Ask to type the cluster name before suspend, resume and reconcile"},
+	"clusters.as":                      {description: "User or service account to impersonate, e.g. system:serviceaccount:team-a:flux"},
+	"clusters.as_groups":               {description: "Groups to impersonate, requires as"},
+	"clusters.token_file":              {description: "File holding a bearer token, used instead of the kubeconfig credentials"},
+	"clusters.exec":                    {description: "Credential plugin run instead of the kubeconfig credentials"},
+	"clusters.exec.command":            {description: "Command printing an ExecCredential", required: true},
+	"clusters.exec.args":               {description: "Arguments of the command"},
+	"clusters.exec.env":                {description: "Environment variables of the command"},
+	"clusters.exec.api_version":        {description: "ExecCredential API version, defaults to client.authentication.k8s.io/v1"},
+	"defaults":                         {description: "Default settings"},
+	"defaults.cluster":                 {description: "Cluster selected at startup"},
+	"defaults.namespace":               {description: "Namespace shown at startup"},
+	"defaults.refresh_interval":        {description: "Interval between resource refreshes, e.g. 5s", pattern: durationPattern},
+	"defaults.max_concurrent_clusters": {description: "Number of clusters refreshed in parallel", minimum: minimum(1)},
+	"defaults.events_enabled":          {description: "Stream Kubernetes events"},
+	"defaults.events_lookback":         {description: "Only show events seen within this window, e.g. 1h; 0 shows all", pattern: durationPattern},
+	"defaults.read_only":               {description: "Block suspend, resume and reconcile on every cluster, as --read-only"},
+	"ui":                               {description: "User interface settings"},
+	"ui.theme":                         {description: "Color theme: dark, light, high-contrast, colorblind or a theme defined in ui.themes. Colors are disabled when NO_COLOR is set"},
+	"ui.themes":                        {description: "User themes by name. A theme named after a built-in one changes its colors"},
+	"ui.themes.base":                   {description: "Built-in theme providing the colors that are not set, defaults to the theme of the same name or dark", enum: Themes},
+	"ui.themes.title":                  {description: "Application title", pattern: colorPattern.String()},
+	"ui.themes.cluster":                {description: "Current cluster in the header", pattern: colorPattern.String()},
+	"ui.themes.resource":               {description: "Current resource type in the header", pattern: colorPattern.String()},
+	"ui.themes.namespace":              {description: "Current namespace in the header", pattern: colorPattern.String()},
+	"ui.themes.success":                {description: "Status messages and healthy states", pattern: colorPattern.String()},
+	"ui.themes.warning":                {description: "Warnings and degraded states", pattern: colorPattern.String()},
+	"ui.themes.progressing":            {description: "Resources that are reconciling", pattern: colorPattern.String()},
+	"ui.themes.error":                  {description: "Errors and failed states", pattern: colorPattern.String()},
+	"ui.themes.muted":                  {description: "Help, notices and empty views", pattern: colorPattern.String()},
+	"ui.themes.border":                 {description: "Table borders and key hints", pattern: colorPattern.String()},
+	"ui.themes.selected_foreground":    {description: "Text of the selected row", pattern: colorPattern.String()},
+	"ui.themes.selected_background":    {description: "Background of the selected row", pattern: colorPattern.String()},
+	"ui.columns":                       {description: "Columns of the resource tables by kind (gitrepository, helmrepository, kustomization, helmrelease), in display order", enum: TableColumns},
+	"ui.show_age":                      {description: "Show the age column"},
+	"ui.show_message":                  {description: "Show the message column"},
+	"ui.show_namespace":                {description: "Show the namespace column"},
+	"ui.pane_events_height":            {description: "Rows of the events pane below the resources, 0 hides it", minimum: minimum(0)},
+	"ui.columns_name":                  {description: "Width of the name column", minimum: minimum(1)},
+	"ui.columns_status":                {description: "Width of the status column", minimum: minimum(1)},
+	"discovery":                        {description: "Import kubeconfig contexts as clusters at startup"},
+	"discovery.enabled":                {description: "Enable discovery"},
+	"discovery.kubeconfig":             {description: "Kubeconfig file to discover contexts in"},
+	"discovery.include":                {description: "Contexts to import, as globs or /regular expressions/"},
+	"discovery.exclude":                {description: "Contexts to skip, as globs or /regular expressions/"},
+	"audit":                            {description: "Log suspend, resume and reconcile, see fluxcli audit"},
+	"audit.enabled":                    {description: "Append every suspend, resume and reconcile to the audit log"},
+	"audit.path":                       {description: "Audit log file, defaults to ~/.fluxcli/audit.log"},
+	"audit.annotate":                   {description: "Also annotate suspended objects with who suspended them and when"},
+	"debug":                            {description: "Enable debug mode"},
+	"log_level":                        {description: "Log level", enum: LogLevels},
+	"keybindings":                      {description: "Keys of the UI actions; actions that are not set keep their default keys"},
+}
+
+// schemaFieldFor returns the documentation of the key at path. Key bindings
+// are documented by their action.
+func schemaFieldFor(path string) schemaField {
+	if action, ok := strings.CutPrefix(path, "keybindings."); ok {
+		for _, a := range KeyActions {
+			if a.Name == action {
+				return schemaField{description: a.Description}
+			}
+		}
+	}
+	return schemaFields[path]
+}
+
+// JSONSchema returns a JSON Schema describing the configuration file, for
+// editor completion and validation
+func JSONSchema() ([]byte, error) {
+	defaults := newConfig()
+	// Debug and log level default to their command line flags
+	defaults.Debug, defaults.LogLevel = false, "info"
+	defaults.KeyBindings = DefaultKeyBindings()
+
+	schema := schemaFor(reflect.TypeOf(Config{}), "", reflect.ValueOf(*defaults))
+	schema.Schema = "https://json-schema.org/draft/2020-12/schema"
+	schema.Title = "FluxCLI configuration"
+
+	// Profiles accept the top level keys, without defaults of their own
+	profile := schemaFor(reflect.TypeOf(Config{}), "", reflect.Value{})
+	profile.Description = "Values overriding the top level ones when the profile is selected"
+	schema.Properties[profilesKey] = &jsonSchema{
+		Description:          "Named profiles, selected with --profile or " + ProfileEnv,
+		Type:                 "object",
+		AdditionalProperties: profile,
+	}
+
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// schemaFor describes the type t found at path; defaults holds its built-in
+// default value, if any
+func schemaFor(t reflect.Type, path string, defaults reflect.Value) *jsonSchema {
+	field := schemaFieldFor(path)
+	schema := &jsonSchema{
+		Description: field.description,
+		Enum:        field.enum,
+		Pattern:     field.pattern,
+		Minimum:     field.minimum,
+	}
+
+	switch {
+	case t == reflect.TypeOf(time.Duration(0)):
+		schema.Type = "string"
+		if defaults.IsValid() && defaults.Int() != 0 {
+			schema.Default = time.Duration(defaults.Int()).String()
+		}
+		return schema
+	case t.Kind() == reflect.Pointer:
+		if defaults.IsValid() && !defaults.IsNil() {
+			return schemaFor(t.Elem(), path, defaults.Elem())
+		}
+		return schemaFor(t.Elem(), path, reflect.Value{})
+	case t.Kind() == reflect.Struct:
+		schema.Type = "object"
+		schema.AdditionalProperties = false
+		schema.Properties = make(map[string]*jsonSchema)
+		for name, f := range yamlFields(t) {
+			var value reflect.Value
+			if defaults.IsValid() {
+				value = defaults.FieldByIndex(f.Index)
+			}
+			key := strings.TrimPrefix(path+"."+name, ".")
+			schema.Properties[name] = schemaFor(f.Type, key, value)
+			if schemaFieldFor(key).required {
+				schema.Required = append(schema.Required, name)
+			}
+		}
+		slices.Sort(schema.Required)
+		return schema
+	case t.Kind() == reflect.Map:
+		schema.Type = "object"
+		schema.Enum = nil
+		elem := schemaFor(t.Elem(), path, reflect.Value{})
+		if t.Elem().Kind() != reflect.Struct {
+			elem.Description = ""
+		}
+		schema.AdditionalProperties = elem
+		return schema
+	case t.Kind() == reflect.Slice:
+		// Allowed values apply to the items
+		schema.Type = "array"
+		schema.Enum, schema.Pattern = nil, ""
+		schema.Items = schemaFor(t.Elem(), path, reflect.Value{})
+		schema.Items.Description = ""
+		if defaults.IsValid() && defaults.Len() > 0 {
+			schema.Default = defaults.Interface()
+		}
+		return schema
+	case t.Kind() == reflect.String:
+		schema.Type = "string"
+	case t.Kind() == reflect.Bool:
+		schema.Type = "boolean"
+	case t.Kind() == reflect.Int:
+		schema.Type = "integer"
+	}
+
+	if defaults.IsValid() && !defaults.IsZero() {
+		schema.Default = defaults.Interface()
+	}
+	return schema
+}
@@ -0,0 +1,263 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// readConfigFile decodes a config file into cfg and keeps its YAML document
+// so that comments and unknown keys survive a later save. A missing or empty
+// file leaves cfg untouched.
+func readConfigFile(cfg *Config, path string) error {
+	cfg.path = path
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return &ValidationError{File: path, Issues: []ValidationIssue{yamlIssue(err.Error(), nil)}}
+	}
+	if len(document.Content) == 0 {
+		return nil
+	}
+
+	cfg.document = &document
+	if root := cfg.root(); root != nil {
+		recordSources(cfg, root, "", Source{Kind: SourceFile})
+	}
+	if err := document.Decode(cfg); err != nil {
+		typeErr, ok := err.(*yaml.TypeError)
+		if !ok {
+			return fmt.Errorf("failed to decode config file %s: %w", path, err)
+		}
+		issues := make([]ValidationIssue, 0, len(typeErr.Errors))
+		for _, message := range typeErr.Errors {
+			issues = append(issues, yamlIssue(message, &document))
+		}
+		return &ValidationError{File: path, Issues: issues}
+	}
+
+	return nil
+}
+
+// marshal renders the configuration as YAML, merged into the document it
+// was loaded from. Values of the active profile are written to the profile
+// and values set by environment variables or flags are not written at all.
+func (c *Config) marshal() ([]byte, error) {
+	var current yaml.Node
+	if err := current.Encode(c); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	base, profile := c.splitLayers(&current, "")
+
+	if root := c.root(); root != nil {
+		mergeNode(root, base)
+		if target := c.profileNode(c.Profile); target != nil {
+			mergeNode(target, profile)
+		}
+	} else {
+		root = pruneNode(base)
+		if root == nil {
+			root = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		}
+		c.document = &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root}}
+	}
+
+	return marshalNode(c.document)
+}
+
+// marshalNode encodes a YAML node with two space indentation
+func marshalNode(node *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writeConfigFile writes the configuration to path atomically
+func (c *Config) writeConfigFile(path string) error {
+	data, err := c.marshal()
+	if err != nil {
+		return err
+	}
+
+	if err := writeFileAtomic(path, data); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	c.path = path
+
+	return nil
+}
+
+// writeFileAtomic replaces path with data via a temporary file in the same
+// directory, keeping the permissions of an existing file
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, mode); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
+
+// mergeNode updates dst with the values of src while keeping the comments,
+// key order and keys of dst that src does not know about
+func mergeNode(dst, src *yaml.Node) {
+	switch {
+	case dst.Kind == yaml.MappingNode && src.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(src.Content); i += 2 {
+			key, value := src.Content[i], src.Content[i+1]
+			if existing := mappingValue(dst, key.Value); existing != nil {
+				mergeNode(existing, value)
+				continue
+			}
+			if pruned := pruneNode(value); pruned != nil {
+				dst.Content = append(dst.Content, key, pruned)
+			}
+		}
+
+	case dst.Kind == yaml.SequenceNode && src.Kind == yaml.SequenceNode:
+		content := make([]*yaml.Node, 0, len(src.Content))
+		for _, item := range src.Content {
+			if existing := namedItem(dst, item); existing != nil {
+				mergeNode(existing, item)
+				content = append(content, existing)
+			} else if pruned := pruneNode(item); pruned != nil {
+				content = append(content, pruned)
+			} else {
+				content = append(content, item)
+			}
+		}
+		dst.Content = content
+		for _, item := range content {
+			if item.Kind != yaml.ScalarNode {
+				// Flow style (e.g. "clusters: []") cannot hold block mappings
+				dst.Style &^= yaml.FlowStyle
+				break
+			}
+		}
+
+	case dst.Kind == yaml.ScalarNode && src.Kind == yaml.ScalarNode:
+		if dst.Value == src.Value && dst.Tag == src.Tag {
+			return
+		}
+		dst.Value = src.Value
+		dst.Tag = src.Tag
+		dst.Style = src.Style
+
+	default:
+		dst.Kind = src.Kind
+		dst.Tag = src.Tag
+		dst.Value = src.Value
+		dst.Style = src.Style
+		dst.Content = src.Content
+	}
+}
+
+// mappingValue returns the value node for key in a mapping node
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// namedItem returns the mapping in sequence that has the same "name" as item
+func namedItem(sequence, item *yaml.Node) *yaml.Node {
+	if item.Kind != yaml.MappingNode {
+		return nil
+	}
+	name := mappingValue(item, "name")
+	if name == nil || name.Value == "" {
+		return nil
+	}
+
+	for _, candidate := range sequence.Content {
+		if candidate.Kind != yaml.MappingNode {
+			continue
+		}
+		if other := mappingValue(candidate, "name"); other != nil && other.Value == name.Value {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// pruneNode drops empty values from a freshly encoded node so that new
+// entries are written without blank fields. It returns nil when nothing is left.
+func pruneNode(node *yaml.Node) *yaml.Node {
+	switch node.Kind {
+	case yaml.MappingNode:
+		content := make([]*yaml.Node, 0, len(node.Content))
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if value := pruneNode(node.Content[i+1]); value != nil {
+				content = append(content, node.Content[i], value)
+			}
+		}
+		if len(content) == 0 {
+			return nil
+		}
+		node.Content = content
+		return node
+
+	case yaml.SequenceNode:
+		if len(node.Content) == 0 {
+			return nil
+		}
+		return node
+
+	case yaml.ScalarNode:
+		if node.Tag == "!!str" && node.Value == "" {
+			return nil
+		}
+		return node
+	}
+
+	return node
+}
@@ -0,0 +1,165 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfigFile = `# FluxCLI Configuration
+clusters:
+  # Production fleet
+  - name: production
+    context: prod-cluster
+    description: "Production environment" # shown in the cluster list
+    team: platform
+defaults:
+  namespace: apps
+  refresh_interval: 30s
+  max_concurrent_clusters: 3
+  events_enabled: false
+ui:
+  theme: light
+  pane_events_height: 8
+  columns_name: 40
+plugins:
+  - name: custom
+    enabled: true
+`
+
+func writeTestConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestLoadConfigFileKeys(t *testing.T) {
+	path := writeTestConfig(t, testConfigFile)
+
+	cfg, err := Load(path, "", "", "")
+	require.NoError(t, err)
+
+	assert.Equal(t, path, cfg.Path())
+	assert.Equal(t, "apps", cfg.Defaults.Namespace)
+	assert.Equal(t, 30*time.Second, cfg.Defaults.RefreshInterval)
+	assert.Equal(t, 3, cfg.Defaults.MaxConcurrentClusters)
+	assert.False(t, cfg.Defaults.EventsEnabled)
+	assert.Equal(t, "light", cfg.UI.Theme)
+	assert.Equal(t, 8, cfg.UI.PaneEventsHeight)
+	assert.Equal(t, 40, cfg.UI.ColumnsName)
+
+	// Keys missing from the file keep their defaults
+	assert.Equal(t, 15, cfg.UI.ColumnsStatus)
+	assert.True(t, cfg.UI.ShowAge)
+
+	require.Len(t, cfg.Clusters, 1)
+	assert.Equal(t, "prod-cluster", cfg.Clusters[0].Context)
+}
+
+func TestSaveRoundTrip(t *testing.T) {
+	path := writeTestConfig(t, testConfigFile)
+
+	cfg, err := Load(path, "", "", "")
+	require.NoError(t, err)
+	require.NoError(t, cfg.Save())
+
+	reloaded, err := Load(path, "", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, cfg.Clusters, reloaded.Clusters)
+	assert.Equal(t, cfg.Defaults, reloaded.Defaults)
+	assert.Equal(t, cfg.UI, reloaded.UI)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	content := string(data)
+	assert.Contains(t, content, "# FluxCLI Configuration")
+	assert.Contains(t, content, "# Production fleet")
+	assert.Contains(t, content, "# shown in the cluster list")
+	assert.Contains(t, content, "team: platform")
+	assert.Contains(t, content, "plugins:")
+	assert.Contains(t, content, "refresh_interval: 30s")
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// Saving an unchanged configuration is stable
+	require.NoError(t, reloaded.Save())
+	again, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(again))
+}
+
+func TestSaveClusterChanges(t *testing.T) {
+	path := writeTestConfig(t, testConfigFile)
+
+	cfg, err := Load(path, "", "", "")
+	require.NoError(t, err)
+
+	cfg.AddCluster(ClusterConfig{Name: "staging", Context: "staging-cluster"})
+	cfg.Defaults.RefreshInterval = 10 * time.Second
+	require.NoError(t, cfg.Save())
+
+	reloaded, err := Load(path, "", "", "")
+	require.NoError(t, err)
+	require.Len(t, reloaded.Clusters, 2)
+	assert.Equal(t, "staging", reloaded.Clusters[1].Name)
+	assert.Equal(t, "staging-cluster", reloaded.Clusters[1].Context)
+	assert.Equal(t, 10*time.Second, reloaded.Defaults.RefreshInterval)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# Production fleet")
+	assert.NotContains(t, string(data), "color: \"\"")
+
+	require.True(t, reloaded.RemoveCluster("production"))
+	require.NoError(t, reloaded.Save())
+
+	final, err := Load(path, "", "", "")
+	require.NoError(t, err)
+	require.Len(t, final.Clusters, 1)
+	assert.Equal(t, "staging", final.Clusters[0].Name)
+
+	// No temporary files are left behind
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestSaveToNewFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "config.yaml")
+
+	cfg, err := Load(path, "", "", "")
+	require.NoError(t, err)
+	cfg.AddCluster(ClusterConfig{Name: "dev", Context: "kind-dev"})
+	require.NoError(t, cfg.SaveTo(path))
+
+	reloaded, err := Load(path, "", "", "")
+	require.NoError(t, err)
+	require.Len(t, reloaded.Clusters, 1)
+	assert.Equal(t, cfg.Defaults, reloaded.Defaults)
+	assert.Equal(t, cfg.UI, reloaded.UI)
+}
+
+func TestSaveDefaultConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, createDefaultConfig(path))
+
+	cfg, err := Load(path, "", "", "")
+	require.NoError(t, err)
+	cfg.AddCluster(ClusterConfig{Name: "dev", Context: "kind-dev"})
+	require.NoError(t, cfg.Save())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# FluxCLI Configuration")
+	assert.Contains(t, string(data), "- name: dev")
+
+	reloaded, err := Load(path, "", "", "")
+	require.NoError(t, err)
+	require.Len(t, reloaded.Clusters, 1)
+}
@@ -0,0 +1,175 @@
+package config
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ThemeConfig is the palette of a UI theme. User themes only need to set
+// the colors they change; the others are taken from the base theme.
+type ThemeConfig struct {
+	Base               string `yaml:"base,omitempty"`
+	Title              string `yaml:"title,omitempty"`
+	Cluster            string `yaml:"cluster,omitempty"`
+	Resource           string `yaml:"resource,omitempty"`
+	Namespace          string `yaml:"namespace,omitempty"`
+	Success            string `yaml:"success,omitempty"`
+	Warning            string `yaml:"warning,omitempty"`
+	Progressing        string `yaml:"progressing,omitempty"`
+	Error              string `yaml:"error,omitempty"`
+	Muted              string `yaml:"muted,omitempty"`
+	Border             string `yaml:"border,omitempty"`
+	SelectedForeground string `yaml:"selected_foreground,omitempty"`
+	SelectedBackground string `yaml:"selected_background,omitempty"`
+}
+
+// DefaultTheme is used when ui.theme is empty or unknown
+const DefaultTheme = "dark"
+
+// BuiltinThemes are the palettes shipped with FluxCLI
+var BuiltinThemes = map[string]ThemeConfig{
+	"dark": {
+		Title:              "205",
+		Cluster:            "86",
+		Resource:           "81",
+		Namespace:          "226",
+		Success:            "86",
+		Warning:            "208",
+		Progressing:        "75",
+		Error:              "196",
+		Muted:              "244",
+		Border:             "240",
+		SelectedForeground: "229",
+		SelectedBackground: "57",
+	},
+	"light": {
+		Title:              "162",
+		Cluster:            "30",
+		Resource:           "25",
+		Namespace:          "130",
+		Success:            "28",
+		Warning:            "166",
+		Progressing:        "32",
+		Error:              "160",
+		Muted:              "242",
+		Border:             "249",
+		SelectedForeground: "255",
+		SelectedBackground: "25",
+	},
+	// Uses the 16 base colors, which terminals map to their most legible
+	// variants
+	"high-contrast": {
+		Title:              "15",
+		Cluster:            "14",
+		Resource:           "14",
+		Namespace:          "11",
+		Success:            "10",
+		Warning:            "11",
+		Progressing:        "12",
+		Error:              "9",
+		Muted:              "15",
+		Border:             "15",
+		SelectedForeground: "0",
+		SelectedBackground: "11",
+	},
+	// Okabe-Ito palette: success and error stay apart for red-green color
+	// blindness (blue versus vermillion)
+	"colorblind": {
+		Title:              "#CC79A7",
+		Cluster:            "#56B4E9",
+		Resource:           "#009E73",
+		Namespace:          "#F0E442",
+		Success:            "#56B4E9",
+		Warning:            "#E69F00",
+		Progressing:        "#0072B2",
+		Error:              "#D55E00",
+		Muted:              "244",
+		Border:             "240",
+		SelectedForeground: "#000000",
+		SelectedBackground: "#56B4E9",
+	},
+}
+
+// Themes are the names of the built-in themes
+var Themes = []string{"dark", "light", "high-contrast", "colorblind"}
+
+// ThemeNames returns the built-in themes followed by the user themes
+func (c *Config) ThemeNames() []string {
+	names := slices.Clone(Themes)
+	user := make([]string, 0, len(c.UI.Themes))
+	for name := range c.UI.Themes {
+		if !slices.Contains(names, name) {
+			user = append(user, name)
+		}
+	}
+	slices.Sort(user)
+	return append(names, user...)
+}
+
+// Palette returns the colors of the selected theme. A user theme is laid
+// over its base theme, which defaults to the built-in theme of the same name
+// or DefaultTheme.
+func (c *Config) Palette() ThemeConfig {
+	name := c.UI.Theme
+	theme, isUser := c.UI.Themes[name]
+	builtin, isBuiltin := BuiltinThemes[name]
+	if !isUser {
+		if !isBuiltin {
+			return BuiltinThemes[DefaultTheme]
+		}
+		return builtin
+	}
+
+	base := theme.Base
+	if base == "" {
+		base = DefaultTheme
+		if isBuiltin {
+			base = name
+		}
+	}
+	palette, ok := BuiltinThemes[base]
+	if !ok {
+		palette = BuiltinThemes[DefaultTheme]
+	}
+
+	// Copy every valid color the user theme sets
+	src, dst := reflect.ValueOf(theme), reflect.ValueOf(&palette).Elem()
+	for i := 0; i < src.NumField(); i++ {
+		if value := src.Field(i).String(); colorPattern.MatchString(value) {
+			dst.Field(i).SetString(value)
+		}
+	}
+	palette.Base = base
+	return palette
+}
+
+// checkThemes validates the selected theme and the user themes
+func (v *validator) checkThemes(c *Config) {
+	if theme := c.UI.Theme; theme != "" && !slices.Contains(c.ThemeNames(), theme) {
+		v.warnf(at("ui", "theme"), "unknown theme %q, expected one of %s", theme, strings.Join(c.ThemeNames(), ", "))
+	}
+
+	names := make([]string, 0, len(c.UI.Themes))
+	for name := range c.UI.Themes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		theme := c.UI.Themes[name]
+		p := at("ui", "themes", name)
+		if _, ok := BuiltinThemes[theme.Base]; theme.Base != "" && !ok {
+			v.warnf(p.key("base"), "unknown base theme %q, expected one of %s", theme.Base, strings.Join(Themes, ", "))
+		}
+
+		value := reflect.ValueOf(theme)
+		for key, field := range yamlFields(value.Type()) {
+			color := value.FieldByIndex(field.Index).String()
+			if key == "base" || color == "" || colorPattern.MatchString(color) {
+				continue
+			}
+			v.warnf(p.key(key), "color %q is not an ANSI color number or hex code", color)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const themeConfigFile = `ui:
+  theme: ocean
+  themes:
+    ocean:
+      base: light
+      title: "#0077be"
+      error: crimson
+    dark:
+      selected_background: "22"
+    broken:
+      base: solarized
+      bold: true
+`
+
+func TestPalette(t *testing.T) {
+	path := writeTestConfig(t, themeConfigFile)
+	cfg, err := Load(path, "", "", "")
+	require.NoError(t, err)
+
+	palette := cfg.Palette()
+	assert.Equal(t, "light", palette.Base)
+	assert.Equal(t, "#0077be", palette.Title)
+	assert.Equal(t, BuiltinThemes["light"].Error, palette.Error, "invalid colors fall back to the base theme")
+	assert.Equal(t, BuiltinThemes["light"].Success, palette.Success)
+
+	// A user theme named after a built-in one changes its colors
+	cfg.UI.Theme = "dark"
+	assert.Equal(t, "22", cfg.Palette().SelectedBackground)
+	assert.Equal(t, BuiltinThemes["dark"].Title, cfg.Palette().Title)
+
+	cfg.UI.Theme = "high-contrast"
+	assert.Equal(t, BuiltinThemes["high-contrast"], cfg.Palette())
+
+	cfg.UI.Theme = "missing"
+	assert.Equal(t, BuiltinThemes[DefaultTheme], cfg.Palette())
+
+	assert.Equal(t, []string{"dark", "light", "high-contrast", "colorblind", "broken", "ocean"}, cfg.ThemeNames())
+}
+
+func TestValidateThemes(t *testing.T) {
+	path := writeTestConfig(t, themeConfigFile+"  theme_: x\n")
+
+	issues, err := ValidateFile(path)
+	require.NoError(t, err)
+	assert.False(t, HasErrors(issues))
+
+	got := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		got = append(got, issue.String())
+	}
+	assert.Equal(t, []string{
+		`7:7: ui.themes.ocean.error: color "crimson" is not an ANSI color number or hex code`,
+		`11:7: ui.themes.broken.base: unknown base theme "solarized", expected one of dark, light, high-contrast, colorblind`,
+		`12:7: ui.themes.broken.bold: unknown key, it is ignored`,
+		`13:3: ui.theme_: unknown key, did you mean "theme"?`,
+	}, got)
+}
+
+func TestBuiltinThemesComplete(t *testing.T) {
+	require.Len(t, BuiltinThemes, len(Themes))
+	for _, name := range Themes {
+		theme, ok := BuiltinThemes[name]
+		require.True(t, ok, name)
+		for _, color := range []string{theme.Title, theme.Cluster, theme.Resource, theme.Namespace,
+			theme.Success, theme.Warning, theme.Error, theme.Muted, theme.Border,
+			theme.SelectedForeground, theme.SelectedBackground} {
+			assert.Regexp(t, colorPattern, color, name)
+		}
+	}
+}
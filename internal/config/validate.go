@@ -0,0 +1,563 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Severity describes how serious a validation issue is
+type Severity string
+
+const (
+	// SeverityError marks values FluxCLI cannot run with
+	SeverityError Severity = "error"
+	// SeverityWarning marks values that are ignored or likely mistakes
+	SeverityWarning Severity = "warning"
+)
+
+// ValidationIssue is a single problem found in a configuration file
+type ValidationIssue struct {
+	Severity Severity
+	Path     string // Dotted key path, e.g. "clusters[1].context"
+	Line     int    // 1-based line in the file, 0 when unknown
+	Column   int    // 1-based column in the file, 0 when unknown
+	Message  string
+}
+
+// String formats the issue as "line:column: path: message"
+func (i ValidationIssue) String() string {
+	var b strings.Builder
+	if i.Line > 0 {
+		fmt.Fprintf(&b, "%d:", i.Line)
+		if i.Column > 0 {
+			fmt.Fprintf(&b, "%d:", i.Column)
+		}
+		b.WriteString(" ")
+	}
+	if i.Path != "" {
+		fmt.Fprintf(&b, "%s: ", i.Path)
+	}
+	b.WriteString(i.Message)
+	return b.String()
+}
+
+// ValidationError is returned when a configuration file contains errors
+type ValidationError struct {
+	File   string
+	Issues []ValidationIssue
+}
+
+// Error lists every error level issue
+func (e *ValidationError) Error() string {
+	var lines []string
+	for _, issue := range e.Issues {
+		if issue.Severity != SeverityError {
+			continue
+		}
+		if issue.Line > 0 {
+			lines = append(lines, fmt.Sprintf("  %s:%s", e.File, issue))
+		} else {
+			lines = append(lines, fmt.Sprintf("  %s", issue))
+		}
+	}
+	return fmt.Sprintf("invalid configuration in %s:\n%s", e.File, strings.Join(lines, "\n"))
+}
+
+// HasErrors reports whether any of the issues is an error
+func HasErrors(issues []ValidationIssue) bool {
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// durationPattern matches the values accepted by time.ParseDuration
+const durationPattern = `^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+
+// colorPattern matches lipgloss colors: ANSI numbers and hex codes
+var colorPattern = regexp.MustCompile(`^(#[0-9a-fA-F]{6}|#[0-9a-fA-F]{3}|[0-9]{1,3})$`)
+
+// LogLevels are the accepted log levels
+var LogLevels = []string{"trace", "debug", "info", "warn", "error"}
+
+// ValidateFile reads and validates a configuration file without applying
+// any command line overrides
+func ValidateFile(path string) ([]ValidationIssue, error) {
+	path = ExpandPath(path)
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	issues, cfg, err := validateProfile(path, "")
+	if err != nil || cfg == nil {
+		return issues, err
+	}
+
+	// Every profile is checked merged over the top level values
+	seen := make(map[string]bool, len(issues))
+	for _, issue := range issues {
+		seen[string(issue.Severity)+issue.String()] = true
+	}
+	for _, name := range cfg.ProfileNames() {
+		profileIssues, _, err := validateProfile(path, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range profileIssues {
+			if key := string(issue.Severity) + issue.String(); !seen[key] {
+				seen[key] = true
+				issues = append(issues, issue)
+			}
+		}
+	}
+
+	sortIssues(issues)
+	return issues, nil
+}
+
+// validateProfile validates the file with a profile applied. The returned
+// config is nil when the file is not valid YAML.
+func validateProfile(path, profile string) ([]ValidationIssue, *Config, error) {
+	cfg := newConfig()
+	var issues []ValidationIssue
+
+	err := readConfigFile(cfg, path)
+	if err == nil {
+		err = cfg.applyProfile(profile)
+	}
+	if err != nil {
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			return nil, nil, err
+		}
+		if cfg.document == nil {
+			// The file is not valid YAML
+			return validationErr.Issues, nil, nil
+		}
+		// Values that failed to decode are reported alongside the others
+		issues = validationErr.Issues
+	}
+
+	issues = append(issues, cfg.Validate()...)
+	sortIssues(issues)
+	return issues, cfg, nil
+}
+
+// Validate checks the configuration values and, when it was loaded from a
+// file, reports keys FluxCLI does not know about. Issues are ordered by line.
+func (c *Config) Validate() []ValidationIssue {
+	v := &validator{config: c, document: c.root()}
+
+	v.checkClusters(c)
+	v.checkDefaults(c)
+	v.checkUI(c)
+	v.checkDiscovery(c)
+	v.checkKeyBindings(c)
+
+	if c.LogLevel != "" && !slices.Contains(LogLevels, c.LogLevel) {
+		v.warnf(at("log_level"), "unknown log level %q, expected one of %s", c.LogLevel, strings.Join(LogLevels, ", "))
+	}
+
+	if v.document != nil {
+		v.checkKeys(v.document, reflect.TypeOf(Config{}), nil)
+	}
+
+	sortIssues(v.issues)
+	return v.issues
+}
+
+// root returns the top level mapping of the loaded document
+func (c *Config) root() *yaml.Node {
+	if c.document == nil || len(c.document.Content) == 0 {
+		return nil
+	}
+	if root := c.document.Content[0]; root.Kind == yaml.MappingNode {
+		return root
+	}
+	return nil
+}
+
+// validator collects issues and resolves key paths to file positions
+type validator struct {
+	config   *Config
+	document *yaml.Node
+	issues   []ValidationIssue
+}
+
+func (v *validator) errorf(p keyPath, format string, args ...any) {
+	v.add(SeverityError, p, fmt.Sprintf(format, args...))
+}
+
+func (v *validator) warnf(p keyPath, format string, args ...any) {
+	v.add(SeverityWarning, p, fmt.Sprintf(format, args...))
+}
+
+func (v *validator) add(severity Severity, p keyPath, message string) {
+	if source := v.config.Source(p.String()); source.Kind == SourceEnv {
+		// Environment variables have no position in the file
+		v.issues = append(v.issues, ValidationIssue{
+			Severity: severity,
+			Path:     p.String(),
+			Message:  fmt.Sprintf("%s (set by %s)", message, source.Detail),
+		})
+		return
+	}
+
+	p = v.config.profileLocation(p)
+	issue := ValidationIssue{Severity: severity, Path: p.String(), Message: message}
+	if node := p.locate(v.document); node != nil {
+		issue.Line, issue.Column = node.Line, node.Column
+	}
+	v.issues = append(v.issues, issue)
+}
+
+func (v *validator) checkClusters(c *Config) {
+	seen := make(map[string]int)
+	for i, cluster := range c.Clusters {
+		p := at("clusters", i)
+
+		if cluster.Name == "" {
+			v.errorf(p, "cluster name is required")
+		} else if first, ok := seen[cluster.Name]; ok {
+			v.errorf(p.key("name"), "duplicate cluster name %q, already used by clusters[%d]", cluster.Name, first)
+		} else {
+			seen[cluster.Name] = i
+		}
+
+		if cluster.Context == "" {
+			v.warnf(p, "cluster %q has no context, the kubeconfig's current context is used", cluster.Name)
+		}
+
+		if cluster.Color != "" && !colorPattern.MatchString(cluster.Color) {
+			v.warnf(p.key("color"), "color %q is not an ANSI color number or hex code", cluster.Color)
+		}
+
+		v.checkClusterAuth(p, cluster)
+	}
+}
+
+// checkClusterAuth checks the credential and impersonation overrides of a
+// cluster
+func (v *validator) checkClusterAuth(p keyPath, cluster ClusterConfig) {
+	if len(cluster.AsGroups) > 0 && cluster.As == "" {
+		v.errorf(p.key("as_groups"), "groups can only be impersonated along with a user, set as")
+	}
+
+	if cluster.TokenFile != "" {
+		if cluster.Exec != nil {
+			v.errorf(p.key("exec"), "token_file and exec both replace the kubeconfig credentials, set only one")
+		}
+		if _, err := os.Stat(ExpandPath(cluster.TokenFile)); err != nil {
+			v.warnf(p.key("token_file"), "token file is not readable: %v", err)
+		}
+	}
+
+	if cluster.Exec != nil && cluster.Exec.Command == "" {
+		v.errorf(p.key("exec"), "exec needs a command")
+	}
+}
+
+func (v *validator) checkDefaults(c *Config) {
+	d := c.Defaults
+
+	if d.Cluster != "" {
+		if _, ok := c.GetCluster(d.Cluster); !ok {
+			v.errorf(at("defaults", "cluster"), "default cluster %q is not configured", d.Cluster)
+		}
+	}
+
+	if d.RefreshInterval <= 0 {
+		v.errorf(at("defaults", "refresh_interval"), "must be a positive duration such as 5s, got %s", d.RefreshInterval)
+	} else if d.RefreshInterval < time.Second {
+		v.warnf(at("defaults", "refresh_interval"), "%s is very short and puts load on the API servers; a plain number is read as nanoseconds, use a unit such as 5s", d.RefreshInterval)
+	}
+
+	if d.EventsLookback < 0 {
+		v.errorf(at("defaults", "events_lookback"), "must be a duration such as 1h or 0 for all events, got %s", d.EventsLookback)
+	}
+
+	if d.MaxConcurrentClusters < 1 {
+		v.errorf(at("defaults", "max_concurrent_clusters"), "must be at least 1, got %d", d.MaxConcurrentClusters)
+	}
+}
+
+func (v *validator) checkUI(c *Config) {
+	ui := c.UI
+
+	v.checkThemes(c)
+	v.checkColumns(c)
+
+	if ui.PaneEventsHeight < 0 {
+		v.errorf(at("ui", "pane_events_height"), "must not be negative, got %d", ui.PaneEventsHeight)
+	}
+	if ui.ColumnsName < 1 {
+		v.errorf(at("ui", "columns_name"), "must be at least 1, got %d", ui.ColumnsName)
+	}
+	if ui.ColumnsStatus < 1 {
+		v.errorf(at("ui", "columns_status"), "must be at least 1, got %d", ui.ColumnsStatus)
+	}
+}
+
+func (v *validator) checkDiscovery(c *Config) {
+	for _, list := range []struct {
+		key      string
+		patterns []string
+	}{
+		{"include", c.Discovery.Include},
+		{"exclude", c.Discovery.Exclude},
+	} {
+		for i, pattern := range list.patterns {
+			if _, err := matchPattern(pattern, ""); err != nil {
+				v.errorf(at("discovery", list.key, i), "%v", err)
+			}
+		}
+	}
+}
+
+// checkKeys reports mapping keys that do not correspond to a field of t
+func (v *validator) checkKeys(node *yaml.Node, t reflect.Type, p keyPath) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case node.Kind == yaml.MappingNode && t.Kind() == reflect.Struct:
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if len(p) == 0 && key.Value == profilesKey {
+				if value.Kind != yaml.MappingNode {
+					v.errorf(at(profilesKey), "profiles must be a mapping of profile names to config keys")
+					continue
+				}
+				v.checkProfileKeys(value)
+				continue
+			}
+			field, ok := fields[key.Value]
+			if !ok {
+				issue := ValidationIssue{
+					Severity: SeverityWarning,
+					Path:     p.key(key.Value).String(),
+					Line:     key.Line,
+					Column:   key.Column,
+					Message:  "unknown key, it is ignored",
+				}
+				if suggestion := closestKey(key.Value, fields); suggestion != "" {
+					issue.Message = fmt.Sprintf("unknown key, did you mean %q?", suggestion)
+				}
+				v.issues = append(v.issues, issue)
+				continue
+			}
+			v.checkKeys(value, field.Type, p.key(key.Value))
+		}
+
+	case node.Kind == yaml.MappingNode && t.Kind() == reflect.Map:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			v.checkKeys(node.Content[i+1], t.Elem(), p.key(node.Content[i].Value))
+		}
+
+	case node.Kind == yaml.SequenceNode && t.Kind() == reflect.Slice:
+		for i, item := range node.Content {
+			v.checkKeys(item, t.Elem(), append(p[:len(p):len(p)], i))
+		}
+	}
+}
+
+// yamlFields returns the struct fields of t keyed by their YAML name
+func yamlFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		fields[name] = field
+	}
+	return fields
+}
+
+// closestKey returns the known key nearest to key, if it is close enough to
+// be a typo
+func closestKey(key string, fields map[string]reflect.StructField) string {
+	best, bestDistance := "", 3
+	for name := range fields {
+		if d := editDistance(key, name); d < bestDistance || (d == bestDistance && name < best) {
+			best, bestDistance = name, d
+		}
+	}
+	if bestDistance >= 3 {
+		return ""
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
+
+// keyPath addresses a value in the configuration. Elements are mapping keys
+// (string) or sequence indexes (int).
+type keyPath []any
+
+func at(elements ...any) keyPath {
+	return keyPath(elements)
+}
+
+// key returns a copy of p extended by a mapping key
+func (p keyPath) key(name string) keyPath {
+	return append(p[:len(p):len(p)], name)
+}
+
+// String renders the path as "clusters[1].name"
+func (p keyPath) String() string {
+	var b strings.Builder
+	for _, element := range p {
+		switch e := element.(type) {
+		case int:
+			fmt.Fprintf(&b, "[%d]", e)
+		case string:
+			if b.Len() > 0 {
+				b.WriteString(".")
+			}
+			b.WriteString(e)
+		}
+	}
+	return b.String()
+}
+
+// locate returns the node the path points at, falling back to the closest
+// ancestor that exists in the document. Mapping values resolve to their key
+// so that positions point at the offending line.
+func (p keyPath) locate(root *yaml.Node) *yaml.Node {
+	if root == nil {
+		return nil
+	}
+
+	node, located := root, root
+	for _, element := range p {
+		switch e := element.(type) {
+		case string:
+			if node.Kind != yaml.MappingNode {
+				return located
+			}
+			found := false
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == e {
+					located, node = node.Content[i], node.Content[i+1]
+					found = true
+					break
+				}
+			}
+			if !found {
+				return located
+			}
+		case int:
+			if node.Kind != yaml.SequenceNode || e >= len(node.Content) {
+				return located
+			}
+			node = node.Content[e]
+			located = node
+		}
+	}
+
+	return located
+}
+
+// yamlLinePattern extracts the line number from yaml.v3 error messages
+var yamlLinePattern = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// yamlIssue converts a yaml.v3 parse or type error message into an issue.
+// When the document is known, the issue is attributed to the key on the
+// reported line.
+func yamlIssue(message string, document *yaml.Node) ValidationIssue {
+	issue := ValidationIssue{Severity: SeverityError, Message: message}
+	match := yamlLinePattern.FindStringSubmatch(message)
+	if match == nil {
+		return issue
+	}
+
+	issue.Line, _ = strconv.Atoi(match[1])
+	issue.Message = match[2]
+	if document != nil && len(document.Content) > 0 {
+		if p, key := keyAtLine(document.Content[0], issue.Line, nil); key != nil {
+			issue.Path = p.String()
+			issue.Column = key.Column
+		}
+	}
+	return issue
+}
+
+// keyAtLine finds the innermost mapping key on the given line
+func keyAtLine(node *yaml.Node, line int, p keyPath) (keyPath, *yaml.Node) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if found, inner := keyAtLine(value, line, p.key(key.Value)); inner != nil {
+				return found, inner
+			}
+			if key.Line == line {
+				return p.key(key.Value), key
+			}
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			if found, inner := keyAtLine(item, line, append(p[:len(p):len(p)], i)); inner != nil {
+				return found, inner
+			}
+		}
+	}
+	return nil, nil
+}
+
+// sortIssues orders issues by position; issues without a position go last
+func sortIssues(issues []ValidationIssue) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if (a.Line == 0) != (b.Line == 0) {
+			return b.Line == 0
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const invalidConfigFile = `clusters:
+  - name: production
+    context: prod-cluster
+    colour: "212"
+  - name: production
+    context: prod-cluster-2
+defaults:
+  cluster: staging
+  refresh_interval: 0s
+  max_concurrent_clusters: 0
+ui:
+  columns_name: wide
+discovery:
+  include: ["/[/"]
+`
+
+func TestValidateFile(t *testing.T) {
+	path := writeTestConfig(t, invalidConfigFile)
+
+	issues, err := ValidateFile(path)
+	require.NoError(t, err)
+	require.True(t, HasErrors(issues))
+
+	got := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		got = append(got, string(issue.Severity)+" "+issue.String())
+	}
+	assert.Equal(t, []string{
+		`warning 4:5: clusters[0].colour: unknown key, did you mean "color"?`,
+		`error 5:5: clusters[1].name: duplicate cluster name "production", already used by clusters[0]`,
+		`error 8:3: defaults.cluster: default cluster "staging" is not configured`,
+		`error 9:3: defaults.refresh_interval: must be a positive duration such as 5s, got 0s`,
+		`error 10:3: defaults.max_concurrent_clusters: must be at least 1, got 0`,
+		"error 12:3: ui.columns_name: cannot unmarshal !!str `wide` into int",
+		`error 14:13: discovery.include[0]: invalid context pattern "/[/": error parsing regexp: missing closing ]: ` + "`[`",
+	}, got)
+}
+
+func TestValidateFileSyntaxError(t *testing.T) {
+	path := writeTestConfig(t, "defaults:\n  refresh_interval: [\n")
+
+	issues, err := ValidateFile(path)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, SeverityError, issues[0].Severity)
+	assert.Equal(t, 2, issues[0].Line)
+}
+
+func TestValidateWarningsOnly(t *testing.T) {
+	path := writeTestConfig(t, testConfigFile)
+
+	issues, err := ValidateFile(path)
+	require.NoError(t, err)
+	assert.False(t, HasErrors(issues))
+	require.Len(t, issues, 2)
+	assert.Equal(t, "clusters[0].team", issues[0].Path)
+	assert.Equal(t, "unknown key, it is ignored", issues[0].Message)
+	assert.Equal(t, "plugins", issues[1].Path)
+}
+
+func TestLoadRejectsInvalidConfig(t *testing.T) {
+	path := writeTestConfig(t, invalidConfigFile)
+
+	_, err := Load(path, "", "", "")
+	require.Error(t, err)
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, path, validationErr.File)
+	assert.Contains(t, err.Error(), path+":12:3: ui.columns_name")
+}
+
+func TestLoadRejectsZeroRefreshInterval(t *testing.T) {
+	path := writeTestConfig(t, "defaults:\n  refresh_interval: 0s\n")
+
+	_, err := Load(path, "", "", "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), path+":2:3: defaults.refresh_interval: must be a positive duration")
+}
+
+func TestJSONSchemaUpToDate(t *testing.T) {
+	schema, err := JSONSchema()
+	require.NoError(t, err)
+
+	committed, err := os.ReadFile("../../docs/config.schema.json")
+	require.NoError(t, err)
+	assert.Equal(t, string(committed), string(schema), "run go generate ./internal/config to update docs/config.schema.json")
+}
+
+func TestValidateClusterAuth(t *testing.T) {
+	path := writeTestConfig(t, `clusters:
+  - name: tenant
+    context: prod
+    as_groups: [team-a]
+    token_file: /nonexistent/token
+    exec:
+      args: [eks, get-token]
+`)
+
+	issues, err := ValidateFile(path)
+	require.NoError(t, err)
+
+	got := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		got = append(got, string(issue.Severity)+" "+issue.Path+": "+issue.Message)
+	}
+	assert.Equal(t, []string{
+		"error clusters[0].as_groups: groups can only be impersonated along with a user, set as",
+		"warning clusters[0].token_file: token file is not readable: stat /nonexistent/token: no such file or directory",
+		"error clusters[0].exec: token_file and exec both replace the kubeconfig credentials, set only one",
+		"error clusters[0].exec: exec needs a command",
+	}, got)
+}
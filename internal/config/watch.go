@@ -0,0 +1,161 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDebounce is how long the watcher waits for a burst of file system
+// events to settle before reloading. Editors often write a file in several
+// steps (truncate, write, rename).
+const reloadDebounce = 250 * time.Millisecond
+
+// Reload is the outcome of reloading a changed configuration file. Err is
+// set when the file could not be read or is invalid, in which case the
+// previous configuration stays in effect.
+type Reload struct {
+	Config *Config
+	Err    error
+}
+
+// Watcher reloads a configuration file whenever it changes on disk
+type Watcher struct {
+	config   *Config
+	path     string
+	contents []byte
+	watcher  *fsnotify.Watcher
+	reloads  chan Reload
+	done     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewWatcher starts watching the file cfg was loaded from. The directory is
+// watched rather than the file so that editors and Save, which replace the
+// file with a new one, keep triggering reloads.
+func NewWatcher(cfg *Config) (*Watcher, error) {
+	if cfg.path == "" {
+		return nil, fmt.Errorf("configuration was not loaded from a file")
+	}
+
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config watcher: %w", err)
+	}
+	if err := fsWatcher.Add(filepath.Dir(cfg.path)); err != nil {
+		fsWatcher.Close()
+		return nil, fmt.Errorf("failed to watch %s: %w", cfg.path, err)
+	}
+
+	contents, _ := os.ReadFile(cfg.path)
+	w := &Watcher{
+		config:   cfg,
+		path:     filepath.Clean(cfg.path),
+		contents: contents,
+		watcher:  fsWatcher,
+		reloads:  make(chan Reload, 1),
+		done:     make(chan struct{}),
+	}
+
+	w.wg.Add(1)
+	go w.run()
+
+	return w, nil
+}
+
+// Reloads returns the channel reload results are delivered on
+func (w *Watcher) Reloads() <-chan Reload {
+	return w.reloads
+}
+
+// Close stops watching the configuration file
+func (w *Watcher) Close() error {
+	close(w.done)
+	err := w.watcher.Close()
+	w.wg.Wait()
+	return err
+}
+
+// run collects file system events and reloads once they settle
+func (w *Watcher) run() {
+	defer w.wg.Done()
+
+	debounce := time.NewTimer(reloadDebounce)
+	debounce.Stop()
+	defer debounce.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) != w.path {
+				continue
+			}
+			if event.Has(fsnotify.Write) || event.Has(fsnotify.Create) || event.Has(fsnotify.Rename) {
+				debounce.Reset(reloadDebounce)
+			}
+
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			w.send(Reload{Err: fmt.Errorf("config watcher: %w", err)})
+
+		case <-debounce.C:
+			w.reload()
+		}
+	}
+}
+
+// reload loads the file again and delivers the result, skipping writes that
+// did not change its contents
+func (w *Watcher) reload() {
+	contents, err := os.ReadFile(w.path)
+	if os.IsNotExist(err) {
+		// Replaced by a rename that has not completed yet
+		return
+	}
+	if err != nil {
+		w.send(Reload{Err: fmt.Errorf("failed to read config file: %w", err)})
+		return
+	}
+	if bytes.Equal(contents, w.contents) {
+		return
+	}
+	w.contents = contents
+
+	cfg, err := w.config.Reload()
+	if err != nil {
+		w.send(Reload{Err: err})
+		return
+	}
+	w.config = cfg
+	w.send(Reload{Config: cfg})
+}
+
+// send delivers a reload result, replacing one that was not consumed yet
+func (w *Watcher) send(reload Reload) {
+	for {
+		select {
+		case w.reloads <- reload:
+			return
+		case <-w.done:
+			return
+		default:
+			select {
+			case <-w.reloads:
+			default:
+			}
+		}
+	}
+}
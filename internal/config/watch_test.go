@@ -0,0 +1,72 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nextReload waits for the watcher to deliver a reload
+func nextReload(t *testing.T, w *Watcher) Reload {
+	t.Helper()
+	select {
+	case reload := <-w.Reloads():
+		return reload
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for config reload")
+		return Reload{}
+	}
+}
+
+func TestWatcherReloadsChanges(t *testing.T) {
+	path := writeTestConfig(t, "defaults:\n  refresh_interval: 5s\n")
+
+	cfg, err := Load(path, "", "ctx", "")
+	require.NoError(t, err)
+
+	w, err := NewWatcher(cfg)
+	require.NoError(t, err)
+	defer w.Close()
+
+	require.NoError(t, os.WriteFile(path, []byte("defaults:\n  refresh_interval: 30s\n"), 0600))
+	reload := nextReload(t, w)
+	require.NoError(t, reload.Err)
+	assert.Equal(t, 30*time.Second, reload.Config.Defaults.RefreshInterval)
+	assert.Equal(t, "ctx", reload.Config.CurrentContext, "command line overrides are reapplied")
+
+	// Invalid edits are reported and do not replace the configuration
+	require.NoError(t, os.WriteFile(path, []byte("defaults:\n  refresh_interval: 0s\n"), 0600))
+	reload = nextReload(t, w)
+	require.Error(t, reload.Err)
+	assert.Nil(t, reload.Config)
+	var validationErr *ValidationError
+	assert.ErrorAs(t, reload.Err, &validationErr)
+
+	// Atomic saves replace the file and are picked up as well
+	cfg.Defaults.RefreshInterval = 10 * time.Second
+	require.NoError(t, cfg.Save())
+	reload = nextReload(t, w)
+	require.NoError(t, reload.Err)
+	assert.Equal(t, 10*time.Second, reload.Config.Defaults.RefreshInterval)
+}
+
+func TestWatcherIgnoresUnchangedContents(t *testing.T) {
+	path := writeTestConfig(t, testConfigFile)
+
+	cfg, err := Load(path, "", "", "")
+	require.NoError(t, err)
+
+	w, err := NewWatcher(cfg)
+	require.NoError(t, err)
+	defer w.Close()
+
+	require.NoError(t, os.WriteFile(path, []byte(testConfigFile), 0600))
+	select {
+	case reload := <-w.Reloads():
+		t.Fatalf("unexpected reload: %+v", reload)
+	case <-time.After(3 * reloadDebounce):
+	}
+}
@@ -0,0 +1,164 @@
+// Package audit records the changes made to FluxCD resources in an
+// append-only JSON Lines file, one entry per line
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Result is the outcome of an audited operation
+type Result string
+
+const (
+	ResultSucceeded Result = "succeeded"
+	ResultFailed    Result = "failed"
+	ResultBlocked   Result = "blocked" // Refused before reaching the cluster, e.g. on a read-only cluster
+)
+
+// Results are the known results
+var Results = []Result{ResultSucceeded, ResultFailed, ResultBlocked}
+
+// Entry records one operation
+type Entry struct {
+	Time      time.Time `json:"time"`
+	User      string    `json:"user"`               // Operating system user
+	Identity  string    `json:"identity,omitempty"` // Kubernetes user the cluster authenticated
+	Cluster   string    `json:"cluster"`
+	Kind      string    `json:"kind"`
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Action    string    `json:"action"`
+	Reason    string    `json:"reason,omitempty"`
+	Result    Result    `json:"result"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// Filter selects entries. Empty fields match every entry.
+type Filter struct {
+	Cluster   string
+	Namespace string
+	Kind      string // Matched case-insensitively
+	Name      string
+	Action    string
+	User      string // Matches the operating system or the Kubernetes user
+	Result    Result
+	Since     time.Time
+}
+
+// Matches reports whether an entry passes the filter
+func (f Filter) Matches(entry Entry) bool {
+	switch {
+	case f.Cluster != "" && entry.Cluster != f.Cluster:
+		return false
+	case f.Namespace != "" && entry.Namespace != f.Namespace:
+		return false
+	case f.Kind != "" && !strings.EqualFold(entry.Kind, f.Kind):
+		return false
+	case f.Name != "" && entry.Name != f.Name:
+		return false
+	case f.Action != "" && entry.Action != f.Action:
+		return false
+	case f.User != "" && entry.User != f.User && entry.Identity != f.User:
+		return false
+	case f.Result != "" && entry.Result != f.Result:
+		return false
+	case !f.Since.IsZero() && entry.Time.Before(f.Since):
+		return false
+	}
+	return true
+}
+
+// writeMu serializes the appends of this process; appends of other
+// processes rely on O_APPEND writing each line at once
+var writeMu sync.Mutex
+
+// Append adds an entry to the log at path, creating the file and its
+// directory when they do not exist
+func Append(path string, entry Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+	data = append(data, '\n')
+
+	writeMu.Lock()
+	defer writeMu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return file.Close()
+}
+
+// Read returns the entries of the log at path that pass the filter, oldest
+// first. Lines that cannot be decoded, such as a line cut short by a crash,
+// are skipped and counted. A log that does not exist has no entries.
+func Read(path string, filter Filter) (entries []Entry, malformed int, err error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(strings.TrimSpace(string(line))) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			malformed++
+			continue
+		}
+		if filter.Matches(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return entries, malformed, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return entries, malformed, nil
+}
+
+// OSUser returns the name of the operating system user running fluxcli
+func OSUser() string {
+	if current, err := user.Current(); err == nil && current.Username != "" {
+		return current.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return os.Getenv("USERNAME")
+}
+
+// ParseResult converts a result name to a Result
+func ParseResult(name string) (Result, error) {
+	for _, result := range Results {
+		if string(result) == strings.ToLower(name) {
+			return result, nil
+		}
+	}
+	return "", fmt.Errorf("unknown result %q, expected succeeded, failed or blocked", name)
+}
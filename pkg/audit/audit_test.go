@@ -0,0 +1,77 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendAndRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fluxcli", "audit.log")
+	start := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	entries := []Entry{
+		{Time: start, User: "alice", Identity: "alice@example.com", Cluster: "prod", Kind: "Kustomization", Namespace: "flux-system", Name: "apps", Action: "suspend", Reason: "incident 42", Result: ResultSucceeded},
+		{Time: start.Add(time.Hour), User: "bob", Cluster: "prod", Kind: "HelmRelease", Namespace: "apps", Name: "podinfo", Action: "reconcile", Result: ResultBlocked, Error: "cluster prod is read-only, reconcile is blocked"},
+		{Time: start.Add(2 * time.Hour), User: "alice", Identity: "alice@example.com", Cluster: "prod", Kind: "Kustomization", Namespace: "flux-system", Name: "apps", Action: "resume", Result: ResultSucceeded},
+	}
+	for _, entry := range entries {
+		require.NoError(t, Append(path, entry))
+	}
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	all, malformed, err := Read(path, Filter{})
+	require.NoError(t, err)
+	assert.Zero(t, malformed)
+	assert.Equal(t, entries, all)
+
+	// The user matches the operating system or the Kubernetes user
+	byAlice, _, err := Read(path, Filter{User: "alice@example.com", Kind: "kustomization"})
+	require.NoError(t, err)
+	assert.Equal(t, []Entry{entries[0], entries[2]}, byAlice)
+
+	recent, _, err := Read(path, Filter{Since: start.Add(30 * time.Minute), Result: ResultBlocked})
+	require.NoError(t, err)
+	require.Len(t, recent, 1)
+	assert.Equal(t, "podinfo", recent[0].Name)
+}
+
+func TestReadSkipsMalformedLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	require.NoError(t, Append(path, Entry{User: "alice", Action: "suspend", Result: ResultSucceeded}))
+
+	// A line cut short, as by a crash while writing
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"user":"bob","act`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	entries, malformed, err := Read(path, Filter{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, malformed)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "alice", entries[0].User)
+
+	// A log that was never written has no entries
+	entries, malformed, err = Read(filepath.Join(t.TempDir(), "missing.log"), Filter{})
+	require.NoError(t, err)
+	assert.Zero(t, malformed)
+	assert.Empty(t, entries)
+}
+
+func TestParseResult(t *testing.T) {
+	result, err := ParseResult("Blocked")
+	require.NoError(t, err)
+	assert.Equal(t, ResultBlocked, result)
+
+	_, err = ParseResult("denied")
+	assert.EqualError(t, err, `unknown result "denied", expected succeeded, failed or blocked`)
+}
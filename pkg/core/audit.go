@@ -0,0 +1,118 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/malagant/fluxcli/pkg/audit"
+	"github.com/malagant/fluxcli/pkg/k8s"
+)
+
+// identityTimeout bounds looking up who a cluster authenticates the user as
+const identityTimeout = 5 * time.Second
+
+// change describes a change of the current cluster for the audit log
+type change struct {
+	action       string
+	resourceType k8s.ResourceType
+	name         string
+	reason       string
+}
+
+// record appends a change and its outcome to the audit log, if enabled. A
+// log that cannot be written is reported as an error update; the change
+// itself is not undone.
+func (m *Manager) record(c change, client *k8s.Client, err error) {
+	cfg := m.currentConfig()
+	if !cfg.Audit.Enabled {
+		return
+	}
+
+	entry := audit.Entry{
+		Time:      time.Now().UTC(),
+		User:      audit.OSUser(),
+		Identity:  m.identity(m.currentCluster, client),
+		Cluster:   m.currentCluster,
+		Kind:      string(c.resourceType),
+		Namespace: m.currentNamespace,
+		Name:      c.name,
+		Action:    c.action,
+		Reason:    c.reason,
+		Result:    changeResult(err),
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	path, pathErr := cfg.AuditPath()
+	if pathErr == nil {
+		pathErr = audit.Append(path, entry)
+	}
+	if pathErr != nil {
+		m.publishError(ErrorUpdate{
+			Cluster: m.currentCluster,
+			Error:   fmt.Errorf("failed to record %s of %s in the audit log: %w", c.action, c.name, pathErr),
+		})
+	}
+}
+
+// changeResult classifies the outcome of a change: blocked when a guard
+// refused it before it reached the cluster
+func changeResult(err error) audit.Result {
+	var (
+		readOnly    *ReadOnlyError
+		denied      *OperationDeniedError
+		unconfirmed *ConfirmationRequiredError
+	)
+	switch {
+	case err == nil:
+		return audit.ResultSucceeded
+	case errors.As(err, &readOnly), errors.As(err, &denied), errors.As(err, &unconfirmed):
+		return audit.ResultBlocked
+	}
+	return audit.ResultFailed
+}
+
+// identity returns the Kubernetes user a cluster authenticates the client
+// as. It is looked up once per connection; an empty name is returned when
+// the cluster cannot tell.
+func (m *Manager) identity(cluster string, client *k8s.Client) string {
+	if client == nil {
+		return ""
+	}
+
+	m.mu.RLock()
+	name, ok := m.identities[cluster]
+	m.mu.RUnlock()
+	if ok {
+		return name
+	}
+
+	ctx, cancel := context.WithTimeout(m.ctx, identityTimeout)
+	defer cancel()
+	name, err := client.Identity(ctx)
+	if err != nil {
+		return ""
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.identities[cluster] = name
+	return name
+}
+
+// suspendedBy returns who suspends a resource, for the SuspendedByAnnotation
+// when audit.annotate is set
+func (m *Manager) suspendedBy(client *k8s.Client) string {
+	if !m.currentConfig().Audit.Annotate {
+		return ""
+	}
+
+	by := audit.OSUser()
+	if identity := m.identity(m.currentCluster, client); identity != "" && identity != by {
+		by = fmt.Sprintf("%s (%s)", by, identity)
+	}
+	return by
+}
@@ -0,0 +1,99 @@
+package core
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	ctrlfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/malagant/fluxcli/internal/config"
+	"github.com/malagant/fluxcli/pkg/audit"
+	"github.com/malagant/fluxcli/pkg/k8s"
+)
+
+func TestAuditLog(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, kustomizev1.AddToScheme(scheme))
+	apps := &kustomizev1.Kustomization{ObjectMeta: metav1.ObjectMeta{Name: "apps", Namespace: "flux-system"}}
+
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("create", "selfsubjectreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.SelfSubjectReview)
+		review.Status.UserInfo.Username = "alice@example.com"
+		return true, review, nil
+	})
+	client := &k8s.Client{
+		Client:    ctrlfake.NewClientBuilder().WithScheme(scheme).WithObjects(apps).Build(),
+		Interface: clientset,
+	}
+
+	cfg := testConfig()
+	cfg.Audit = config.AuditConfig{Enabled: true, Path: filepath.Join(t.TempDir(), "audit.log"), Annotate: true}
+	cfg.Clusters = []config.ClusterConfig{{Name: "audit", Context: "audit", ReadOnly: true}}
+	m := NewManager(cfg)
+	defer m.cancel()
+	m.currentCluster = "prod"
+	m.clusters["prod"] = client
+	m.clusters["audit"] = client
+
+	require.NoError(t, m.SuspendResource(k8s.ResourceTypeKustomization, "apps", "incident 42"))
+
+	// The object records who suspended it
+	var suspended kustomizev1.Kustomization
+	require.NoError(t, client.Get(context.Background(), types.NamespacedName{Name: "apps", Namespace: "flux-system"}, &suspended))
+	assert.True(t, suspended.Spec.Suspend)
+	assert.Equal(t, audit.OSUser()+" (alice@example.com)", suspended.Annotations[k8s.SuspendedByAnnotation])
+	assert.NotEmpty(t, suspended.Annotations[k8s.SuspendedAtAnnotation])
+
+	m.currentCluster = "audit"
+	assert.Error(t, m.ResumeResource(k8s.ResourceTypeKustomization, "apps"))
+	m.currentCluster = "dev"
+	assert.Error(t, m.ReconcileResource(k8s.ResourceTypeKustomization, "apps"))
+
+	entries, malformed, err := audit.Read(cfg.Audit.Path, audit.Filter{})
+	require.NoError(t, err)
+	assert.Zero(t, malformed)
+	require.Len(t, entries, 3)
+
+	assert.Equal(t, audit.OSUser(), entries[0].User)
+	assert.Equal(t, "alice@example.com", entries[0].Identity)
+	assert.Equal(t, "prod", entries[0].Cluster)
+	assert.Equal(t, "Kustomization", entries[0].Kind)
+	assert.Equal(t, "flux-system", entries[0].Namespace)
+	assert.Equal(t, "apps", entries[0].Name)
+	assert.Equal(t, "suspend", entries[0].Action)
+	assert.Equal(t, "incident 42", entries[0].Reason)
+	assert.Equal(t, audit.ResultSucceeded, entries[0].Result)
+
+	assert.Equal(t, audit.ResultBlocked, entries[1].Result)
+	assert.Equal(t, "cluster audit is read-only, resume is blocked", entries[1].Error)
+
+	// A cluster that is not connected has no identity
+	assert.Equal(t, audit.ResultFailed, entries[2].Result)
+	assert.Empty(t, entries[2].Identity)
+
+	// Disabled, nothing is recorded
+	cfg.Audit.Enabled = false
+	m.currentCluster = "prod"
+	require.NoError(t, m.ResumeResource(k8s.ResourceTypeKustomization, "apps"))
+	entries, _, err = audit.Read(cfg.Audit.Path, audit.Filter{})
+	require.NoError(t, err)
+	assert.Len(t, entries, 3)
+
+	// Resume forgets who suspended the object
+	var resumed kustomizev1.Kustomization
+	require.NoError(t, client.Get(context.Background(), types.NamespacedName{Name: "apps", Namespace: "flux-system"}, &resumed))
+	assert.False(t, resumed.Spec.Suspend)
+	assert.NotContains(t, resumed.Annotations, k8s.SuspendedByAnnotation)
+	assert.NotContains(t, resumed.Annotations, k8s.SuspendReasonAnnotation)
+}
@@ -0,0 +1,495 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/malagant/fluxcli/pkg/k8s"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// ConnectionState represents the connection state of a cluster
+type ConnectionState string
+
+const (
+	ConnectionConnecting  ConnectionState = "Connecting"
+	ConnectionConnected   ConnectionState = "Connected"
+	ConnectionDegraded    ConnectionState = "Degraded"
+	ConnectionUnreachable ConnectionState = "Unreachable"
+	ConnectionAuthExpired ConnectionState = "AuthExpired"
+
+	// ConnectionContextMissing flags a cluster whose context disappeared
+	// from its kubeconfig
+	ConnectionContextMissing ConnectionState = "ContextMissing"
+
+	// ConnectionRemoved is published once when a cluster is no longer
+	// configured. The cluster is not part of GetConnectionStates afterwards.
+	ConnectionRemoved ConnectionState = "Removed"
+)
+
+const (
+	// healthCheckInterval is how often a connected cluster is probed
+	healthCheckInterval = 15 * time.Second
+
+	// degradedThreshold is the number of consecutive failed health checks
+	// after which a degraded cluster is considered unreachable
+	degradedThreshold = 3
+
+	reconnectInitialDelay = 1 * time.Second
+	reconnectMaxDelay     = 2 * time.Minute
+
+	// fluxDiscoveryInterval is how often the Flux installation of a
+	// connected cluster is inspected again
+	fluxDiscoveryInterval = time.Minute
+)
+
+// ConnectionUpdate represents a change in a cluster's connection state
+type ConnectionUpdate struct {
+	Cluster   string
+	State     ConnectionState
+	Error     error
+	Attempt   int
+	NextRetry time.Time
+	Since     time.Time
+
+	// Flux is the Flux installation found on the cluster, nil until it was
+	// inspected. It is kept while the cluster reconnects.
+	Flux *k8s.FluxInstall
+}
+
+// clusterTarget describes how to reach a cluster
+type clusterTarget struct {
+	name       string
+	kubeconfig string
+	context    string
+	auth       k8s.AuthOptions
+	discovered bool
+}
+
+// addTarget registers a cluster to be supervised. It reports false for a
+// duplicate name.
+func (m *Manager) addTarget(target clusterTarget) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, existing := range m.targets {
+		if existing.name == target.name {
+			return false
+		}
+	}
+	m.targets = append(m.targets, target)
+	return true
+}
+
+// startTarget registers a cluster and starts supervising its connection
+func (m *Manager) startTarget(target clusterTarget) {
+	if !m.addTarget(target) {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.mu.Lock()
+	m.stopTarget[target.name] = cancel
+	m.connections[target.name] = ConnectionUpdate{
+		Cluster: target.name,
+		State:   ConnectionConnecting,
+		Since:   time.Now(),
+	}
+	m.mu.Unlock()
+
+	m.supervisors.Add(1)
+	go m.superviseCluster(ctx, target)
+}
+
+// removeTarget stops supervising a cluster and forgets its client and
+// connection state
+func (m *Manager) removeTarget(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if cancel, ok := m.stopTarget[name]; ok {
+		cancel()
+		delete(m.stopTarget, name)
+	}
+	delete(m.clusters, name)
+	delete(m.connections, name)
+	delete(m.permissions, name)
+	delete(m.identities, name)
+	for i, target := range m.targets {
+		if target.name == name {
+			m.targets = append(m.targets[:i], m.targets[i+1:]...)
+			break
+		}
+	}
+}
+
+// discoverFlux inspects the Flux installation of a connected cluster and
+// publishes it with the connection state. Failures keep the previous result,
+// as the health check reports unreachable clusters.
+func (m *Manager) discoverFlux(ctx context.Context, name string) {
+	client, ok := m.clusterClient(name)
+	if !ok {
+		return
+	}
+
+	discoverCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	install, err := client.DiscoverFlux(discoverCtx, "")
+	if err != nil {
+		return
+	}
+
+	m.mu.RLock()
+	current, exists := m.connections[name]
+	m.mu.RUnlock()
+	if !exists {
+		return
+	}
+	current.Flux = install
+	m.setConnectionState(ctx, current)
+}
+
+// GetFluxInstall returns the Flux installation of a cluster, if it was
+// inspected
+func (m *Manager) GetFluxInstall(cluster string) (*k8s.FluxInstall, bool) {
+	state, ok := m.GetConnectionState(cluster)
+	if !ok || state.Flux == nil {
+		return nil, false
+	}
+	return state.Flux, true
+}
+
+// fluxSummary describes an installation for change detection, including
+// its problems
+func fluxSummary(install *k8s.FluxInstall) string {
+	if install == nil {
+		return ""
+	}
+	return install.Summary() + "\n" + strings.Join(install.Problems(), "\n")
+}
+
+// hasFlux probes a connected cluster for FluxCD CRDs. Probe failures are
+// treated as Flux being present so that the cluster is not dropped.
+func (m *Manager) hasFlux(ctx context.Context, name string) bool {
+	client, ok := m.clusterClient(name)
+	if !ok {
+		return false
+	}
+
+	installed, err := client.HasFluxCRDs(ctx)
+	return err != nil || installed
+}
+
+// backoff computes exponential reconnect delays
+type backoff struct {
+	initial time.Duration
+	max     time.Duration
+	attempt int
+}
+
+// newBackoff creates a backoff with the default reconnect delays
+func newBackoff() *backoff {
+	return &backoff{initial: reconnectInitialDelay, max: reconnectMaxDelay}
+}
+
+// Next returns the delay before the next attempt and advances the backoff
+func (b *backoff) Next() time.Duration {
+	delay := b.initial
+	for i := 0; i < b.attempt && delay < b.max; i++ {
+		delay *= 2
+	}
+	if delay > b.max {
+		delay = b.max
+	}
+	b.attempt++
+	return delay
+}
+
+// Attempt returns the number of delays handed out since the last reset
+func (b *backoff) Attempt() int {
+	return b.attempt
+}
+
+// Reset resets the backoff after a successful attempt
+func (b *backoff) Reset() {
+	b.attempt = 0
+}
+
+// classifyConnectionError maps a connection error to a connection state
+func classifyConnectionError(err error) ConnectionState {
+	if err == nil {
+		return ConnectionConnected
+	}
+	if apierrors.IsUnauthorized(err) {
+		return ConnectionAuthExpired
+	}
+	if clientcmd.IsContextNotFound(err) {
+		return ConnectionContextMissing
+	}
+
+	errStr := strings.ToLower(err.Error())
+	if strings.Contains(errStr, "unauthorized") ||
+		strings.Contains(errStr, "token has expired") ||
+		strings.Contains(errStr, "credentials have expired") ||
+		strings.Contains(errStr, "getting credentials") {
+		return ConnectionAuthExpired
+	}
+
+	return ConnectionUnreachable
+}
+
+// GetConnectionUpdates returns the channel for connection state updates
+func (m *Manager) GetConnectionUpdates() <-chan ConnectionUpdate {
+	return m.connectionUpdates
+}
+
+// GetConnectionStates returns the connection state of every known cluster
+// in configuration order
+func (m *Manager) GetConnectionStates() []ConnectionUpdate {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	states := make([]ConnectionUpdate, 0, len(m.targets))
+	for _, target := range m.targets {
+		if state, ok := m.connections[target.name]; ok {
+			states = append(states, state)
+		}
+	}
+	return states
+}
+
+// GetConnectionState returns the connection state of a single cluster
+func (m *Manager) GetConnectionState(cluster string) (ConnectionUpdate, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	state, ok := m.connections[cluster]
+	return state, ok
+}
+
+// setConnectionState records a cluster's connection state and publishes it
+// when it changed. Updates from a supervisor whose context was cancelled
+// because the cluster was removed are dropped.
+func (m *Manager) setConnectionState(ctx context.Context, update ConnectionUpdate) {
+	m.mu.Lock()
+	if ctx.Err() != nil {
+		m.mu.Unlock()
+		return
+	}
+	previous, exists := m.connections[update.Cluster]
+	if exists && previous.State == update.State {
+		update.Since = previous.Since
+	} else {
+		update.Since = time.Now()
+	}
+	if update.Flux == nil {
+		update.Flux = previous.Flux
+	}
+	m.connections[update.Cluster] = update
+	m.mu.Unlock()
+
+	if exists && previous.State == update.State && previous.Attempt == update.Attempt &&
+		errorString(previous.Error) == errorString(update.Error) &&
+		fluxSummary(previous.Flux) == fluxSummary(update.Flux) {
+		return
+	}
+
+	m.publishConnectionUpdate(update)
+}
+
+// publishConnectionUpdate sends a connection update to the UI
+func (m *Manager) publishConnectionUpdate(update ConnectionUpdate) {
+	if m.ctx.Err() != nil {
+		return
+	}
+	select {
+	case m.connectionUpdates <- update:
+	case <-m.ctx.Done():
+	}
+}
+
+// degradesConnection reports whether a failed request says something about
+// the connection: transport errors, timeouts, server errors and rejected
+// credentials do. A reachable API server refusing a request, such as
+// Forbidden or a kind it does not serve, does not.
+func degradesConnection(err error) bool {
+	if meta.IsNoMatchError(err) {
+		return false
+	}
+	var status apierrors.APIStatus
+	if errors.As(err, &status) {
+		code := status.Status().Code
+		return code == http.StatusUnauthorized || code >= http.StatusInternalServerError
+	}
+	return true
+}
+
+// markDegraded flags a connected cluster as degraded after a failed request
+// that degrades the connection
+func (m *Manager) markDegraded(cluster string, err error) {
+	if !degradesConnection(err) {
+		return
+	}
+
+	m.mu.RLock()
+	current, exists := m.connections[cluster]
+	m.mu.RUnlock()
+
+	if !exists || current.State != ConnectionConnected {
+		return
+	}
+
+	state := ConnectionDegraded
+	if classifyConnectionError(err) == ConnectionAuthExpired {
+		state = ConnectionAuthExpired
+	}
+	m.setConnectionState(m.ctx, ConnectionUpdate{Cluster: cluster, State: state, Error: err})
+}
+
+// disconnectCluster drops the client of a cluster so that it is no longer
+// refreshed until it reconnects
+func (m *Manager) disconnectCluster(name string) {
+	m.mu.Lock()
+	delete(m.clusters, name)
+	m.mu.Unlock()
+}
+
+// clusterClient returns the client of a connected cluster
+func (m *Manager) clusterClient(name string) (*k8s.Client, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	client, ok := m.clusters[name]
+	return client, ok
+}
+
+// superviseCluster keeps a cluster connected, reconnecting with exponential
+// backoff whenever the connection cannot be established or is lost
+func (m *Manager) superviseCluster(ctx context.Context, target clusterTarget) {
+	defer m.supervisors.Done()
+
+	reconnect := newBackoff()
+	failures := 0
+	var discovered time.Time // When the Flux installation was last inspected
+
+	for {
+		client, connected := m.clusterClient(target.name)
+		if !connected {
+			m.setConnectionState(ctx, ConnectionUpdate{
+				Cluster: target.name,
+				State:   ConnectionConnecting,
+				Attempt: reconnect.Attempt() + 1,
+			})
+
+			if err := m.connectToCluster(ctx, target); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				delay := reconnect.Next()
+				m.setConnectionState(ctx, ConnectionUpdate{
+					Cluster:   target.name,
+					State:     classifyConnectionError(err),
+					Error:     err,
+					Attempt:   reconnect.Attempt(),
+					NextRetry: time.Now().Add(delay),
+				})
+				if !sleep(ctx, delay) {
+					return
+				}
+				continue
+			}
+
+			reconnect.Reset()
+			failures = 0
+
+			// Discovered contexts are only kept when Flux is installed
+			if target.discovered && !m.hasFlux(ctx, target.name) {
+				m.removeTarget(target.name)
+				return
+			}
+			target.discovered = false
+
+			m.setConnectionState(ctx, ConnectionUpdate{Cluster: target.name, State: ConnectionConnected})
+
+			// Populate the cluster right away instead of waiting for the next tick
+			if client, ok := m.clusterClient(target.name); ok {
+				name := target.name
+				m.background(func() { m.refreshClusterResources(name, client, refreshedResourceTypes) })
+			}
+			m.discoverFlux(ctx, target.name)
+			discovered = time.Now()
+			continue
+		}
+
+		if !sleep(ctx, healthCheckInterval) {
+			return
+		}
+
+		err := client.TestConnection(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			failures = 0
+			m.setConnectionState(ctx, ConnectionUpdate{Cluster: target.name, State: ConnectionConnected})
+			if time.Since(discovered) >= fluxDiscoveryInterval {
+				m.discoverFlux(ctx, target.name)
+				discovered = time.Now()
+			}
+			continue
+		}
+
+		failures++
+		state := classifyConnectionError(err)
+		if state == ConnectionUnreachable && failures < degradedThreshold {
+			m.setConnectionState(ctx, ConnectionUpdate{
+				Cluster: target.name,
+				State:   ConnectionDegraded,
+				Error:   err,
+				Attempt: failures,
+			})
+			continue
+		}
+
+		// Connection lost or credentials rejected, reconnect from scratch
+		m.disconnectCluster(target.name)
+		delay := reconnect.Next()
+		m.setConnectionState(ctx, ConnectionUpdate{
+			Cluster:   target.name,
+			State:     state,
+			Error:     err,
+			Attempt:   reconnect.Attempt(),
+			NextRetry: time.Now().Add(delay),
+		})
+		if !sleep(ctx, delay) {
+			return
+		}
+	}
+}
+
+// sleep waits for the given duration and reports false if ctx was cancelled
+// in the meantime
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// errorString returns the message of an error or an empty string for nil
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,145 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/malagant/fluxcli/internal/config"
+	"github.com/malagant/fluxcli/pkg/k8s"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestBackoffNext(t *testing.T) {
+	b := &backoff{initial: time.Second, max: 10 * time.Second}
+
+	assert.Equal(t, 1*time.Second, b.Next())
+	assert.Equal(t, 2*time.Second, b.Next())
+	assert.Equal(t, 4*time.Second, b.Next())
+	assert.Equal(t, 8*time.Second, b.Next())
+	assert.Equal(t, 10*time.Second, b.Next())
+	assert.Equal(t, 10*time.Second, b.Next())
+	assert.Equal(t, 6, b.Attempt())
+
+	b.Reset()
+	assert.Equal(t, 0, b.Attempt())
+	assert.Equal(t, 1*time.Second, b.Next())
+}
+
+func TestClassifyConnectionError(t *testing.T) {
+	assert.Equal(t, ConnectionConnected, classifyConnectionError(nil))
+	assert.Equal(t, ConnectionUnreachable, classifyConnectionError(errors.New("dial tcp 10.0.0.1:6443: i/o timeout")))
+	assert.Equal(t, ConnectionAuthExpired, classifyConnectionError(apierrors.NewUnauthorized("token expired")))
+	assert.Equal(t, ConnectionAuthExpired, classifyConnectionError(errors.New("getting credentials: exec: executable aws failed")))
+}
+
+func TestDegradesConnection(t *testing.T) {
+	events := schema.GroupResource{Group: "events.k8s.io", Resource: "events"}
+
+	assert.True(t, degradesConnection(errors.New("dial tcp 10.0.0.1:6443: connect: connection refused")))
+	assert.True(t, degradesConnection(apierrors.NewServiceUnavailable("etcd is unavailable")))
+	assert.True(t, degradesConnection(apierrors.NewTimeoutError("list timed out", 1)))
+	assert.True(t, degradesConnection(apierrors.NewUnauthorized("token expired")))
+	assert.False(t, degradesConnection(apierrors.NewForbidden(events, "", errors.New("no RBAC"))))
+	assert.False(t, degradesConnection(apierrors.NewNotFound(events, "")))
+	assert.False(t, degradesConnection(&meta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "image.toolkit.fluxcd.io", Kind: "ImagePolicy"}}))
+}
+
+func TestRefreshKeepsForbiddenClusterConnected(t *testing.T) {
+	refresh := func(listErr error) ConnectionState {
+		clientset := fake.NewSimpleClientset()
+		clientset.PrependReactor("list", "events", func(k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, listErr
+		})
+
+		m := NewManager(testConfig())
+		defer m.cancel()
+		m.clusters["prod"] = &k8s.Client{Interface: clientset}
+		m.setConnectionState(m.ctx, ConnectionUpdate{Cluster: "prod", State: ConnectionConnected})
+
+		m.refreshEvents()
+		select {
+		case update := <-m.errorUpdates:
+			require.Error(t, update.Error)
+		case <-time.After(5 * time.Second):
+			t.Fatal("the failed refresh was not reported")
+		}
+		state, _ := m.GetConnectionState("prod")
+		return state.State
+	}
+
+	forbidden := apierrors.NewForbidden(schema.GroupResource{Group: "events.k8s.io", Resource: "events"}, "", errors.New("no RBAC"))
+	assert.Equal(t, ConnectionConnected, refresh(forbidden))
+	assert.Equal(t, ConnectionDegraded, refresh(errors.New("dial tcp 10.0.0.1:6443: i/o timeout")))
+}
+
+func TestSetConnectionStateKeepsSince(t *testing.T) {
+	m := NewManager(testConfig())
+	defer m.cancel()
+
+	m.setConnectionState(m.ctx, ConnectionUpdate{Cluster: "prod", State: ConnectionUnreachable, Attempt: 1})
+	first, ok := m.GetConnectionState("prod")
+	assert.True(t, ok)
+
+	m.setConnectionState(m.ctx, ConnectionUpdate{Cluster: "prod", State: ConnectionUnreachable, Attempt: 2})
+	second, _ := m.GetConnectionState("prod")
+	assert.Equal(t, first.Since, second.Since)
+	assert.Equal(t, 2, second.Attempt)
+	assert.Len(t, m.connectionUpdates, 2)
+
+	m.setConnectionState(m.ctx, ConnectionUpdate{Cluster: "prod", State: ConnectionConnected})
+	third, _ := m.GetConnectionState("prod")
+	assert.Equal(t, ConnectionConnected, third.State)
+	assert.False(t, third.Since.Before(second.Since))
+}
+
+func TestSetConnectionStateKeepsFlux(t *testing.T) {
+	m := NewManager(testConfig())
+	defer m.cancel()
+
+	install := &k8s.FluxInstall{Namespace: "flux-system", Version: "v2.3.0", APIs: []k8s.FluxAPI{{Group: "source.toolkit.fluxcd.io", Versions: []string{"v1"}}}}
+	m.setConnectionState(m.ctx, ConnectionUpdate{Cluster: "prod", State: ConnectionConnected})
+	m.setConnectionState(m.ctx, ConnectionUpdate{Cluster: "prod", State: ConnectionConnected, Flux: install})
+	assert.Len(t, m.connectionUpdates, 2, "a changed installation is published")
+
+	// State changes keep the installation
+	m.setConnectionState(m.ctx, ConnectionUpdate{Cluster: "prod", State: ConnectionDegraded})
+	got, ok := m.GetFluxInstall("prod")
+	assert.True(t, ok)
+	assert.Same(t, install, got)
+
+	_, ok = m.GetFluxInstall("staging")
+	assert.False(t, ok)
+}
+
+// testConfig returns a minimal configuration for manager tests
+func testConfig() *config.Config {
+	return &config.Config{
+		Defaults: config.DefaultConfig{
+			Namespace:             "flux-system",
+			RefreshInterval:       5 * time.Second,
+			MaxConcurrentClusters: 10,
+		},
+		CurrentContext:   "default",
+		CurrentNamespace: "flux-system",
+	}
+}
+
+func TestRefreshSettingsGuardInvalidValues(t *testing.T) {
+	cfg := testConfig()
+	cfg.Defaults.RefreshInterval = 0
+	cfg.Defaults.MaxConcurrentClusters = 0
+
+	m := NewManager(cfg)
+	defer m.cancel()
+
+	assert.Equal(t, defaultRefreshInterval, m.refreshInterval())
+	assert.Equal(t, 1, m.maxConcurrentClusters())
+}
@@ -0,0 +1,53 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/malagant/fluxcli/internal/config"
+	"github.com/malagant/fluxcli/pkg/k8s"
+)
+
+// diagnoseTimeout bounds diagnosing a cluster, which reviews the access to
+// every Flux kind
+const diagnoseTimeout = 30 * time.Second
+
+// Diagnose checks the connectivity, the Flux APIs and the access to the
+// Flux kinds of a cluster in the current namespace. A cluster that is not
+// connected is diagnosed with a new client, so that the reason it cannot
+// connect is reported.
+func (m *Manager) Diagnose(ctx context.Context, cluster string) (*k8s.Diagnosis, error) {
+	client, ok := m.clusterClient(cluster)
+	if !ok {
+		target, exists := m.target(cluster)
+		if !exists {
+			return nil, fmt.Errorf("unknown cluster %s", cluster)
+		}
+		var err error
+		client, err = k8s.NewClient(config.ExpandPath(target.kubeconfig), target.context, m.currentNamespace, target.auth)
+		if err != nil {
+			return &k8s.Diagnosis{
+				Namespace: m.currentNamespace,
+				Checks:    []k8s.Check{k8s.ConnectionCheck(err)},
+			}, nil
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, diagnoseTimeout)
+	defer cancel()
+	return client.Diagnose(ctx, m.currentNamespace), nil
+}
+
+// target returns the configured target of a cluster
+func (m *Manager) target(name string) (clusterTarget, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, target := range m.targets {
+		if target.name == name {
+			return target, true
+		}
+	}
+	return clusterTarget{}, false
+}
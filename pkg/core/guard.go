@@ -0,0 +1,82 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/malagant/fluxcli/pkg/k8s"
+)
+
+// confirmationTTL is how long a typed confirmation allows the next change
+// of a protected cluster
+const confirmationTTL = time.Minute
+
+// ReadOnlyError is returned for changes of a read-only cluster
+type ReadOnlyError struct {
+	Cluster   string
+	Operation string
+}
+
+func (e *ReadOnlyError) Error() string {
+	return fmt.Sprintf("cluster %s is read-only, %s is blocked", e.Cluster, e.Operation)
+}
+
+// ConfirmationRequiredError is returned for changes of a protected cluster
+// that were not confirmed with ConfirmChange
+type ConfirmationRequiredError struct {
+	Cluster   string
+	Operation string
+}
+
+func (e *ConfirmationRequiredError) Error() string {
+	return fmt.Sprintf("cluster %s is protected, type its name to confirm %s", e.Cluster, e.Operation)
+}
+
+// IsReadOnly reports whether changes of a cluster are blocked
+func (m *Manager) IsReadOnly(cluster string) bool {
+	return m.currentConfig().IsReadOnly(cluster)
+}
+
+// IsProtected reports whether changes of a cluster must be confirmed by
+// typing its name
+func (m *Manager) IsProtected(cluster string) bool {
+	return m.currentConfig().IsProtected(cluster)
+}
+
+// ConfirmChange allows the next change of a protected cluster when typed
+// is the cluster name. The confirmation expires after confirmationTTL.
+func (m *Manager) ConfirmChange(cluster, typed string) error {
+	if typed != cluster {
+		return fmt.Errorf("%q does not match the cluster name %s", typed, cluster)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.confirmed[cluster] = time.Now()
+	return nil
+}
+
+// checkChange guards every change of the current cluster: read-only
+// clusters block it, the user's role must allow it and protected clusters
+// need a confirmation, which the change uses up
+func (m *Manager) checkChange(operation string, resourceType k8s.ResourceType) error {
+	cluster := m.currentCluster
+	if m.IsReadOnly(cluster) {
+		return &ReadOnlyError{Cluster: cluster, Operation: operation}
+	}
+	if err := m.checkOperation(operation, resourceType); err != nil {
+		return err
+	}
+	if !m.IsProtected(cluster) {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	confirmed, ok := m.confirmed[cluster]
+	delete(m.confirmed, cluster)
+	if !ok || time.Since(confirmed) > confirmationTTL {
+		return &ConfirmationRequiredError{Cluster: cluster, Operation: operation}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/malagant/fluxcli/internal/config"
+	"github.com/malagant/fluxcli/pkg/k8s"
+)
+
+func TestCheckChange(t *testing.T) {
+	cfg := testConfig()
+	cfg.Clusters = []config.ClusterConfig{
+		{Name: "prod", Context: "prod", Protected: true},
+		{Name: "audit", Context: "audit", ReadOnly: true},
+		{Name: "dev", Context: "dev"},
+	}
+	m := NewManager(cfg)
+	defer m.cancel()
+
+	m.currentCluster = "dev"
+	assert.NoError(t, m.checkChange("suspend", k8s.ResourceTypeKustomization))
+
+	m.currentCluster = "audit"
+	var readOnly *ReadOnlyError
+	require.True(t, errors.As(m.checkChange("suspend", k8s.ResourceTypeKustomization), &readOnly))
+	assert.Equal(t, "cluster audit is read-only, suspend is blocked", readOnly.Error())
+
+	// Protected clusters need the name typed for each change
+	m.currentCluster = "prod"
+	var unconfirmed *ConfirmationRequiredError
+	assert.True(t, errors.As(m.checkChange("reconcile", k8s.ResourceTypeKustomization), &unconfirmed))
+	assert.Error(t, m.ConfirmChange("prod", "dev"))
+	require.NoError(t, m.ConfirmChange("prod", "prod"))
+	assert.NoError(t, m.checkChange("reconcile", k8s.ResourceTypeKustomization))
+	assert.True(t, errors.As(m.checkChange("reconcile", k8s.ResourceTypeKustomization), &unconfirmed))
+
+	// Read-only mode covers every cluster and operation
+	cfg.SetReadOnly()
+	m.currentCluster = "dev"
+	m.clusters["dev"] = &k8s.Client{Interface: fake.NewSimpleClientset()}
+	assert.True(t, errors.As(m.ResumeResource(k8s.ResourceTypeHelmRelease, "podinfo"), &readOnly))
+	assert.True(t, errors.As(m.ReconcileResource(k8s.ResourceTypeHelmRelease, "podinfo"), &readOnly))
+}
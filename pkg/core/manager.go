@@ -18,9 +18,32 @@ type Manager struct {
 	mu       sync.RWMutex
 	
 	// Event channels for UI updates
-	resourceUpdates chan ResourceUpdate
-	eventUpdates    chan EventUpdate
-	errorUpdates    chan ErrorUpdate
+	resourceUpdates   chan ResourceUpdate
+	eventUpdates      chan EventUpdate
+	errorUpdates      chan ErrorUpdate
+	connectionUpdates chan ConnectionUpdate
+	
+	// Connection tracking
+	targets     []clusterTarget
+	connections map[string]ConnectionUpdate
+	supervisors sync.WaitGroup
+	workers     sync.WaitGroup // Refresh loops and the refreshes they start
+	stopTarget  map[string]context.CancelFunc
+
+	// Reviewed permissions by cluster and namespace
+	permissions map[string]map[string]reviewedPermissions
+
+	// Confirmed changes of protected clusters, by cluster
+	confirmed map[string]time.Time
+
+	// Kubernetes user of each connected cluster, for the audit log
+	identities map[string]string
+
+	// Signals the refresh loop that the refresh interval changed
+	intervalUpdates chan time.Duration
+
+	// Asks the refresh loop to refresh without waiting for the interval
+	refreshRequests chan struct{}
 	
 	// Internal state
 	currentCluster   string
@@ -58,7 +81,15 @@ func NewManager(cfg *config.Config) *Manager {
 		resourceUpdates: make(chan ResourceUpdate, 100),
 		eventUpdates:    make(chan EventUpdate, 100),
 		errorUpdates:    make(chan ErrorUpdate, 100),
-		currentCluster:  cfg.CurrentContext,
+		connectionUpdates: make(chan ConnectionUpdate, 100),
+		connections:     make(map[string]ConnectionUpdate),
+		stopTarget:      make(map[string]context.CancelFunc),
+		permissions:     make(map[string]map[string]reviewedPermissions),
+		confirmed:       make(map[string]time.Time),
+		identities:      make(map[string]string),
+		intervalUpdates: make(chan time.Duration, 1),
+		refreshRequests: make(chan struct{}, 1),
+		currentCluster:  cfg.CurrentCluster,
 		currentNamespace: cfg.CurrentNamespace,
 		ctx:             ctx,
 		cancel:          cancel,
@@ -67,55 +98,80 @@ func NewManager(cfg *config.Config) *Manager {
 
 // Start initializes the manager and starts background processes
 func (m *Manager) Start() error {
-	// Initialize default cluster connection
-	if err := m.connectToCluster(m.currentCluster, m.config.CurrentKubeConfig, m.config.CurrentContext); err != nil {
-		return fmt.Errorf("failed to connect to default cluster: %w", err)
+	targets, err := configuredTargets(m.config)
+	if err != nil {
+		m.errorUpdates <- ErrorUpdate{Error: err}
 	}
 
-	// Initialize configured clusters
-	for _, clusterCfg := range m.config.Clusters {
-		if err := m.connectToCluster(clusterCfg.Name, clusterCfg.Kubeconfig, clusterCfg.Context); err != nil {
-			m.errorUpdates <- ErrorUpdate{
-				Cluster: clusterCfg.Name,
-				Error:   fmt.Errorf("failed to connect to cluster %s: %w", clusterCfg.Name, err),
-			}
-		}
+	// Connect in the background so that an unreachable cluster, including the
+	// default context, does not prevent the others from being used. Every
+	// cluster is supervised so that failed or dropped connections are retried.
+	for _, target := range targets {
+		m.startTarget(target)
 	}
 
 	// Start background refresh
-	go m.startResourceRefresh()
-	go m.startEventRefresh()
+	m.background(m.startResourceRefresh)
+	m.background(m.startEventRefresh)
 
 	return nil
 }
 
-// Stop stops the manager and closes all connections
+// Stop stops the manager and closes all connections. The update channels
+// are closed once nothing can send on them anymore.
 func (m *Manager) Stop() {
 	m.cancel()
+	m.supervisors.Wait()
+	m.workers.Wait()
 	close(m.resourceUpdates)
 	close(m.eventUpdates)
 	close(m.errorUpdates)
+	close(m.connectionUpdates)
 }
 
 // connectToCluster establishes a connection to a Kubernetes cluster
-func (m *Manager) connectToCluster(name, kubeconfig, context string) error {
-	client, err := k8s.NewClient(kubeconfig, context, m.currentNamespace)
+func (m *Manager) connectToCluster(ctx context.Context, target clusterTarget) error {
+	client, err := k8s.NewClient(config.ExpandPath(target.kubeconfig), target.context, m.currentNamespace, target.auth)
 	if err != nil {
 		return err
 	}
 
 	// Test connection
-	if err := client.TestConnection(m.ctx); err != nil {
+	if err := client.TestConnection(ctx); err != nil {
 		return fmt.Errorf("connection test failed: %w", err)
 	}
 
 	m.mu.Lock()
-	m.clusters[name] = client
-	m.mu.Unlock()
+	defer m.mu.Unlock()
+	if ctx.Err() != nil {
+		// The cluster was removed while connecting
+		return ctx.Err()
+	}
+	m.clusters[target.name] = client
 
 	return nil
 }
 
+// background runs f in a goroutine that Stop waits for
+func (m *Manager) background(f func()) {
+	m.workers.Add(1)
+	go func() {
+		defer m.workers.Done()
+		f()
+	}()
+}
+
+// publishError sends an error update to the UI unless the manager stopped
+func (m *Manager) publishError(update ErrorUpdate) {
+	if m.ctx.Err() != nil {
+		return
+	}
+	select {
+	case m.errorUpdates <- update:
+	case <-m.ctx.Done():
+	}
+}
+
 // GetResourceUpdates returns the channel for resource updates
 func (m *Manager) GetResourceUpdates() <-chan ResourceUpdate {
 	return m.resourceUpdates
@@ -131,14 +187,16 @@ func (m *Manager) GetErrorUpdates() <-chan ErrorUpdate {
 	return m.errorUpdates
 }
 
-// GetClusters returns the list of available clusters
+// GetClusters returns the list of connected clusters in configuration order
 func (m *Manager) GetClusters() []string {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	
 	clusters := make([]string, 0, len(m.clusters))
-	for name := range m.clusters {
-		clusters = append(clusters, name)
+	for _, target := range m.targets {
+		if _, connected := m.clusters[target.name]; connected {
+			clusters = append(clusters, target.name)
+		}
 	}
 	return clusters
 }
@@ -185,46 +243,45 @@ func (m *Manager) ListResources(resourceType k8s.ResourceType) ([]k8s.Resource,
 	ctx, cancel := context.WithTimeout(m.ctx, 10*time.Second)
 	defer cancel()
 
-	switch resourceType {
-	case k8s.ResourceTypeGitRepository:
-		return client.ListGitRepositories(ctx, m.currentNamespace)
-	case k8s.ResourceTypeHelmRepository:
-		return client.ListHelmRepositories(ctx, m.currentNamespace)
-	case k8s.ResourceTypeKustomization:
-		return client.ListKustomizations(ctx, m.currentNamespace)
-	case k8s.ResourceTypeHelmRelease:
-		return client.ListHelmReleases(ctx, m.currentNamespace)
-	default:
-		return nil, fmt.Errorf("unsupported resource type: %s", resourceType)
-	}
+	return client.ListResources(ctx, resourceType, m.currentNamespace)
 }
 
-// SuspendResource suspends a FluxCD resource
-func (m *Manager) SuspendResource(resourceType k8s.ResourceType, name string) error {
+// SuspendResource suspends a FluxCD resource, recording reason if given
+func (m *Manager) SuspendResource(resourceType k8s.ResourceType, name, reason string) (err error) {
 	m.mu.RLock()
 	client, exists := m.clusters[m.currentCluster]
 	m.mu.RUnlock()
+	defer func() { m.record(change{"suspend", resourceType, name, reason}, client, err) }()
 	
 	if !exists {
 		return fmt.Errorf("cluster %s not connected", m.currentCluster)
 	}
 
+	if err := m.checkChange("suspend", resourceType); err != nil {
+		return err
+	}
+
 	ctx, cancel := context.WithTimeout(m.ctx, 10*time.Second)
 	defer cancel()
 
-	return client.SuspendResource(ctx, resourceType, name, m.currentNamespace)
+	return client.SuspendResource(ctx, resourceType, name, m.currentNamespace, reason, m.suspendedBy(client))
 }
 
 // ResumeResource resumes a FluxCD resource
-func (m *Manager) ResumeResource(resourceType k8s.ResourceType, name string) error {
+func (m *Manager) ResumeResource(resourceType k8s.ResourceType, name string) (err error) {
 	m.mu.RLock()
 	client, exists := m.clusters[m.currentCluster]
 	m.mu.RUnlock()
+	defer func() { m.record(change{"resume", resourceType, name, ""}, client, err) }()
 	
 	if !exists {
 		return fmt.Errorf("cluster %s not connected", m.currentCluster)
 	}
 
+	if err := m.checkChange("resume", resourceType); err != nil {
+		return err
+	}
+
 	ctx, cancel := context.WithTimeout(m.ctx, 10*time.Second)
 	defer cancel()
 
@@ -232,43 +289,113 @@ func (m *Manager) ResumeResource(resourceType k8s.ResourceType, name string) err
 }
 
 // ReconcileResource triggers reconciliation of a FluxCD resource
-func (m *Manager) ReconcileResource(resourceType k8s.ResourceType, name string) error {
+func (m *Manager) ReconcileResource(resourceType k8s.ResourceType, name string) (err error) {
 	m.mu.RLock()
 	client, exists := m.clusters[m.currentCluster]
 	m.mu.RUnlock()
+	defer func() { m.record(change{"reconcile", resourceType, name, ""}, client, err) }()
 	
 	if !exists {
 		return fmt.Errorf("cluster %s not connected", m.currentCluster)
 	}
 
+	if err := m.checkChange("reconcile", resourceType); err != nil {
+		return err
+	}
+
 	ctx, cancel := context.WithTimeout(m.ctx, 10*time.Second)
 	defer cancel()
 
 	return client.ReconcileResource(ctx, resourceType, name, m.currentNamespace)
 }
 
+// controllerLogTail is the number of log lines of each controller pod shown
+// before new lines are followed
+const controllerLogTail = 1000
+
+// StreamControllerLogs streams the log of the Flux controller that
+// reconciles kind in the current cluster until ctx is done
+func (m *Manager) StreamControllerLogs(ctx context.Context, kind string) (<-chan k8s.LogEntry, error) {
+	m.mu.RLock()
+	client, exists := m.clusters[m.currentCluster]
+	m.mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("cluster %s not connected", m.currentCluster)
+	}
+
+	controller, err := k8s.ControllerForKind(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.StreamControllerLogs(ctx, k8s.LogOptions{
+		Controller: controller,
+		TailLines:  controllerLogTail,
+		Follow:     true,
+	})
+}
+
+// defaultRefreshInterval is used when the configured interval is not positive
+const defaultRefreshInterval = 5 * time.Second
+
+// refreshedResourceTypes are the resource types refreshed in the background
+var refreshedResourceTypes = []k8s.ResourceType{
+	k8s.ResourceTypeGitRepository,
+	k8s.ResourceTypeHelmRepository,
+	k8s.ResourceTypeKustomization,
+	k8s.ResourceTypeHelmRelease,
+}
+
 // startResourceRefresh starts the background resource refresh process
 func (m *Manager) startResourceRefresh() {
-	ticker := time.NewTicker(m.config.Defaults.RefreshInterval)
+	ticker := time.NewTicker(m.refreshInterval())
 	defer ticker.Stop()
 
-	resourceTypes := []k8s.ResourceType{
-		k8s.ResourceTypeGitRepository,
-		k8s.ResourceTypeHelmRepository,
-		k8s.ResourceTypeKustomization,
-		k8s.ResourceTypeHelmRelease,
-	}
-
 	for {
 		select {
 		case <-m.ctx.Done():
 			return
+		case interval := <-m.intervalUpdates:
+			ticker.Reset(interval)
 		case <-ticker.C:
-			m.refreshResources(resourceTypes)
+			m.refreshResources(refreshedResourceTypes)
+		case <-m.refreshRequests:
+			m.refreshResources(refreshedResourceTypes)
+			if m.currentConfig().Defaults.EventsEnabled {
+				m.refreshEvents()
+			}
+			ticker.Reset(m.refreshInterval())
 		}
 	}
 }
 
+// Refresh refreshes the resources and events of every cluster now rather
+// than at the next refresh interval. Requests made while a refresh is
+// pending are merged into it.
+func (m *Manager) Refresh() {
+	select {
+	case m.refreshRequests <- struct{}{}:
+	default:
+	}
+}
+
+// refreshInterval returns the configured refresh interval. Configurations
+// are validated on load, but a non-positive interval would panic the ticker.
+func (m *Manager) refreshInterval() time.Duration {
+	interval := m.currentConfig().Defaults.RefreshInterval
+	if interval <= 0 {
+		return defaultRefreshInterval
+	}
+	return interval
+}
+
+// maxConcurrentClusters returns the number of clusters refreshed in
+// parallel, at least one so that the semaphore cannot block forever
+func (m *Manager) maxConcurrentClusters() int {
+	return max(m.currentConfig().Defaults.MaxConcurrentClusters, 1)
+}
+
 // refreshResources refreshes all resources for all clusters
 func (m *Manager) refreshResources(resourceTypes []k8s.ResourceType) {
 	m.mu.RLock()
@@ -279,7 +406,7 @@ func (m *Manager) refreshResources(resourceTypes []k8s.ResourceType) {
 	m.mu.RUnlock()
 
 	var wg sync.WaitGroup
-	semaphore := make(chan struct{}, m.config.Defaults.MaxConcurrentClusters)
+	semaphore := make(chan struct{}, m.maxConcurrentClusters())
 
 	for clusterName, client := range clusters {
 		wg.Add(1)
@@ -288,57 +415,55 @@ func (m *Manager) refreshResources(resourceTypes []k8s.ResourceType) {
 			semaphore <- struct{}{}
 			defer func() { <-semaphore }()
 
-			for _, resourceType := range resourceTypes {
-				resources, err := m.listResourcesForCluster(c, resourceType)
-				if err != nil {
-					m.errorUpdates <- ErrorUpdate{
-						Cluster: name,
-						Error:   fmt.Errorf("failed to list %s: %w", resourceType, err),
-					}
-					continue
-				}
-
-				select {
-				case m.resourceUpdates <- ResourceUpdate{
-					Cluster:   name,
-					Resources: resources,
-					Type:      resourceType,
-				}:
-				case <-m.ctx.Done():
-					return
-				}
-			}
+			m.refreshClusterResources(name, c, resourceTypes)
 		}(clusterName, client)
 	}
 
 	wg.Wait()
 }
 
+// refreshClusterResources refreshes all resources of a single cluster
+func (m *Manager) refreshClusterResources(name string, c *k8s.Client, resourceTypes []k8s.ResourceType) {
+	for _, resourceType := range resourceTypes {
+		resources, err := m.listResourcesForCluster(c, resourceType)
+		if err != nil {
+			m.markDegraded(name, err)
+			m.publishError(ErrorUpdate{
+				Cluster: name,
+				Error:   fmt.Errorf("failed to list %s: %w", resourceType, err),
+			})
+			continue
+		}
+
+		for i := range resources {
+			resources[i].Cluster = name
+		}
+		// Review before the update, so that it shows which objects are read-only
+		m.reviewPermissions(name, c, append(resourceNamespaces(resources), m.currentNamespace))
+
+		select {
+		case m.resourceUpdates <- ResourceUpdate{
+			Cluster:   name,
+			Resources: resources,
+			Type:      resourceType,
+		}:
+		case <-m.ctx.Done():
+			return
+		}
+	}
+}
+
 // listResourcesForCluster lists resources for a specific cluster and type
 func (m *Manager) listResourcesForCluster(client *k8s.Client, resourceType k8s.ResourceType) ([]k8s.Resource, error) {
 	ctx, cancel := context.WithTimeout(m.ctx, 10*time.Second)
 	defer cancel()
 
-	switch resourceType {
-	case k8s.ResourceTypeGitRepository:
-		return client.ListGitRepositories(ctx, "")
-	case k8s.ResourceTypeHelmRepository:
-		return client.ListHelmRepositories(ctx, "")
-	case k8s.ResourceTypeKustomization:
-		return client.ListKustomizations(ctx, "")
-	case k8s.ResourceTypeHelmRelease:
-		return client.ListHelmReleases(ctx, "")
-	default:
-		return nil, fmt.Errorf("unsupported resource type: %s", resourceType)
-	}
+	return client.ListResources(ctx, resourceType, "")
 }
 
-// startEventRefresh starts the background event refresh process
+// startEventRefresh starts the background event refresh process. Events
+// can be enabled and disabled by reloading the configuration.
 func (m *Manager) startEventRefresh() {
-	if !m.config.Defaults.EventsEnabled {
-		return
-	}
-
 	ticker := time.NewTicker(2 * time.Second) // Events refresh more frequently
 	defer ticker.Stop()
 
@@ -347,7 +472,9 @@ func (m *Manager) startEventRefresh() {
 		case <-m.ctx.Done():
 			return
 		case <-ticker.C:
-			m.refreshEvents()
+			if m.currentConfig().Defaults.EventsEnabled {
+				m.refreshEvents()
+			}
 		}
 	}
 }
@@ -361,17 +488,19 @@ func (m *Manager) refreshEvents() {
 	}
 	m.mu.RUnlock()
 
-	for clusterName, client := range clusters {
-		go func(name string, c *k8s.Client) {
+	lookback := m.currentConfig().Defaults.EventsLookback
+	for name, c := range clusters {
+		m.background(func() {
 			ctx, cancel := context.WithTimeout(m.ctx, 5*time.Second)
 			defer cancel()
 
-			events, err := c.GetEvents(ctx, "")
+			events, err := c.GetEvents(ctx, k8s.EventListOptions{Since: lookback})
 			if err != nil {
-				m.errorUpdates <- ErrorUpdate{
+				m.markDegraded(name, err)
+				m.publishError(ErrorUpdate{
 					Cluster: name,
 					Error:   fmt.Errorf("failed to get events: %w", err),
-				}
+				})
 				return
 			}
 
@@ -383,6 +512,6 @@ func (m *Manager) refreshEvents() {
 			case <-m.ctx.Done():
 				return
 			}
-		}(clusterName, client)
+		})
 	}
 }
@@ -0,0 +1,83 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/malagant/fluxcli/pkg/k8s"
+)
+
+func TestRefresh(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, kustomizev1.AddToScheme(scheme))
+	apps := &kustomizev1.Kustomization{ObjectMeta: metav1.ObjectMeta{Name: "apps", Namespace: "flux-system"}}
+
+	cfg := testConfig()
+	cfg.Defaults.RefreshInterval = time.Hour
+	m := NewManager(cfg)
+	defer m.cancel()
+	m.clusters["prod"] = &k8s.Client{
+		Client:    ctrlfake.NewClientBuilder().WithScheme(scheme).WithObjects(apps).Build(),
+		Interface: fake.NewSimpleClientset(),
+	}
+	go m.startResourceRefresh()
+
+	// Requests made before the refresh loop picks them up are merged
+	m.Refresh()
+	m.Refresh()
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case update := <-m.GetResourceUpdates():
+			if update.Type != k8s.ResourceTypeKustomization {
+				continue
+			}
+			assert.Equal(t, "prod", update.Cluster)
+			require.Len(t, update.Resources, 1)
+			assert.Equal(t, "apps", update.Resources[0].Name)
+			return
+		case <-timeout:
+			t.Fatal("Refresh did not list the resources before the refresh interval")
+		}
+	}
+}
+
+func TestStopWaitsForRefreshes(t *testing.T) {
+	m := NewManager(testConfig())
+	client := &k8s.Client{
+		// A cluster that fails slowly, after the manager stopped
+		Client: ctrlfake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				time.Sleep(20 * time.Millisecond)
+				return errors.New("connection refused")
+			},
+		}).Build(),
+		Interface: fake.NewSimpleClientset(),
+	}
+	var refreshed atomic.Bool
+	m.background(func() {
+		m.refreshClusterResources("prod", client, refreshedResourceTypes)
+		refreshed.Store(true)
+	})
+
+	// The update channels are only closed after the refresh gave up, which
+	// would otherwise panic sending its error
+	m.Stop()
+	assert.True(t, refreshed.Load())
+	for range m.GetErrorUpdates() {
+	}
+}
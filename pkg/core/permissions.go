@@ -0,0 +1,109 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/malagant/fluxcli/pkg/k8s"
+)
+
+// permissionsTTL is how long the reviewed permissions of a namespace are
+// used before they are reviewed again
+const permissionsTTL = 5 * time.Minute
+
+// reviewedPermissions are the permissions of a namespace with the time they
+// were reviewed. Permissions is nil when the review failed, so that it is
+// not retried before the TTL.
+type reviewedPermissions struct {
+	permissions *k8s.Permissions
+	reviewed    time.Time
+}
+
+// OperationDeniedError is returned for operations the user's role does not
+// allow, before they are attempted
+type OperationDeniedError struct {
+	Operation string
+	Resource  k8s.FluxResource
+	Namespace string
+}
+
+func (e *OperationDeniedError) Error() string {
+	return fmt.Sprintf("your role may not %s %s in namespace %s, which %s needs",
+		strings.Join(k8s.OperationVerbs, " and "), e.Resource, e.Namespace, e.Operation)
+}
+
+// reviewPermissions reviews the permissions of the user in the namespaces
+// of a cluster whose review is missing or older than permissionsTTL
+func (m *Manager) reviewPermissions(name string, client *k8s.Client, namespaces []string) {
+	for _, namespace := range namespaces {
+		if namespace == "" {
+			continue
+		}
+
+		m.mu.RLock()
+		entry, exists := m.permissions[name][namespace]
+		m.mu.RUnlock()
+		if exists && time.Since(entry.reviewed) < permissionsTTL {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(m.ctx, 10*time.Second)
+		permissions, err := client.ReviewPermissions(ctx, namespace)
+		cancel()
+		if err != nil {
+			permissions = nil
+		}
+
+		m.mu.Lock()
+		if m.permissions[name] == nil {
+			m.permissions[name] = make(map[string]reviewedPermissions)
+		}
+		m.permissions[name][namespace] = reviewedPermissions{permissions: permissions, reviewed: time.Now()}
+		m.mu.Unlock()
+	}
+}
+
+// OperationPermission tells whether the user may suspend, resume and
+// reconcile objects of a resource type in a namespace of a cluster. It is
+// unknown until the namespace was reviewed with the resources of the
+// cluster.
+func (m *Manager) OperationPermission(cluster string, resourceType k8s.ResourceType, namespace string) k8s.Permission {
+	resource, ok := k8s.FluxResourceForKind(string(resourceType))
+	if !ok {
+		return k8s.PermissionUnknown
+	}
+
+	m.mu.RLock()
+	entry, exists := m.permissions[cluster][namespace]
+	m.mu.RUnlock()
+	if !exists || entry.permissions == nil {
+		return k8s.PermissionUnknown
+	}
+	return entry.permissions.CheckAll(k8s.OperationVerbs, resource)
+}
+
+// checkOperation returns an OperationDeniedError when the user may not
+// perform an operation on objects of a resource type in the current
+// namespace of the current cluster
+func (m *Manager) checkOperation(operation string, resourceType k8s.ResourceType) error {
+	if m.OperationPermission(m.currentCluster, resourceType, m.currentNamespace) != k8s.PermissionDenied {
+		return nil
+	}
+	resource, _ := k8s.FluxResourceForKind(string(resourceType))
+	return &OperationDeniedError{Operation: operation, Resource: resource, Namespace: m.currentNamespace}
+}
+
+// resourceNamespaces returns the distinct namespaces of resources
+func resourceNamespaces(resources []k8s.Resource) []string {
+	var namespaces []string
+	seen := make(map[string]bool)
+	for _, resource := range resources {
+		if !seen[resource.Namespace] {
+			seen[resource.Namespace] = true
+			namespaces = append(namespaces, resource.Namespace)
+		}
+	}
+	return namespaces
+}
@@ -0,0 +1,54 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/malagant/fluxcli/pkg/k8s"
+)
+
+func TestOperationPermission(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	reviews := 0
+	clientset.PrependReactor("create", "selfsubjectrulesreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		reviews++
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectRulesReview)
+		if review.Spec.Namespace == "apps" {
+			review.Status.ResourceRules = []authorizationv1.ResourceRule{
+				{Verbs: []string{"get", "update"}, APIGroups: []string{"kustomize.toolkit.fluxcd.io"}, Resources: []string{"kustomizations"}},
+			}
+		}
+		return true, review, nil
+	})
+	client := &k8s.Client{Interface: clientset}
+
+	m := NewManager(testConfig())
+	defer m.cancel()
+	m.clusters[m.currentCluster] = client
+
+	assert.Equal(t, k8s.PermissionUnknown, m.OperationPermission(m.currentCluster, k8s.ResourceTypeKustomization, "apps"))
+
+	m.reviewPermissions(m.currentCluster, client, []string{"apps", "prod", "apps"})
+	assert.Equal(t, 2, reviews)
+	assert.Equal(t, k8s.PermissionAllowed, m.OperationPermission(m.currentCluster, k8s.ResourceTypeKustomization, "apps"))
+	assert.Equal(t, k8s.PermissionDenied, m.OperationPermission(m.currentCluster, k8s.ResourceTypeHelmRelease, "apps"))
+	assert.Equal(t, k8s.PermissionDenied, m.OperationPermission(m.currentCluster, k8s.ResourceTypeKustomization, "prod"))
+
+	// Reviews are reused until they expire
+	m.reviewPermissions(m.currentCluster, client, []string{"apps"})
+	assert.Equal(t, 2, reviews)
+
+	// Denied operations fail before they are attempted
+	m.SetCurrentNamespace("prod")
+	err := m.SuspendResource(k8s.ResourceTypeKustomization, "apps", "")
+	var denied *OperationDeniedError
+	require.True(t, errors.As(err, &denied))
+	assert.Equal(t, "your role may not get and update kustomizations.kustomize.toolkit.fluxcd.io in namespace prod, which suspend needs", err.Error())
+}
@@ -0,0 +1,154 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/malagant/fluxcli/internal/config"
+	"github.com/malagant/fluxcli/pkg/k8s"
+)
+
+// ApplyConfig switches the manager to a reloaded configuration. Clusters
+// that were added are connected, clusters that were removed or whose
+// kubeconfig, context or credentials changed are disconnected (and
+// reconnected with the new settings), and a changed refresh interval takes
+// effect immediately.
+// Only a discovery failure is returned; the remaining changes still apply.
+func (m *Manager) ApplyConfig(cfg *config.Config) error {
+	m.mu.Lock()
+	m.config = cfg
+	current := append([]clusterTarget(nil), m.targets...)
+	m.mu.Unlock()
+
+	targets, err := configuredTargets(cfg)
+
+	wanted := make(map[string]clusterTarget, len(targets))
+	for _, target := range targets {
+		// The first target of a name wins, as in addTarget
+		if _, ok := wanted[target.name]; !ok {
+			wanted[target.name] = target
+		}
+	}
+
+	for _, target := range current {
+		if want, ok := wanted[target.name]; ok &&
+			want.kubeconfig == target.kubeconfig && want.context == target.context &&
+			reflect.DeepEqual(want.auth, target.auth) {
+			continue
+		}
+		m.removeTarget(target.name)
+		m.publishConnectionUpdate(ConnectionUpdate{
+			Cluster: target.name,
+			State:   ConnectionRemoved,
+			Since:   time.Now(),
+		})
+	}
+
+	for _, target := range targets {
+		m.startTarget(target)
+	}
+
+	// Replace an interval the refresh loop has not picked up yet
+	select {
+	case <-m.intervalUpdates:
+	default:
+	}
+	m.intervalUpdates <- m.refreshInterval()
+
+	return err
+}
+
+// currentConfig returns the configuration in effect
+func (m *Manager) currentConfig() *config.Config {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.config
+}
+
+// configuredTargets lists the clusters of a configuration: the startup
+// cluster, the configured clusters and, when enabled, the discovered
+// kubeconfig contexts that no configured cluster targets already.
+// Discovery errors are returned along with the clusters that could be
+// determined.
+func configuredTargets(cfg *config.Config) ([]clusterTarget, error) {
+	targets := []clusterTarget{{
+		name:       cfg.CurrentCluster,
+		kubeconfig: cfg.CurrentKubeConfig,
+		context:    cfg.CurrentContext,
+		auth:       ClusterAuth(cfg, cfg.CurrentCluster),
+	}}
+	for _, clusterCfg := range cfg.Clusters {
+		targets = append(targets, clusterTarget{
+			name:       clusterCfg.Name,
+			kubeconfig: clusterCfg.Kubeconfig,
+			context:    clusterCfg.Context,
+			auth:       AuthOptions(clusterCfg),
+		})
+	}
+
+	if !cfg.Discovery.Enabled {
+		return targets, nil
+	}
+
+	discovered, err := config.DiscoverClusters(cfg.Discovery)
+	if err != nil {
+		err = fmt.Errorf("failed to discover clusters: %w", err)
+	}
+	for _, clusterCfg := range discovered {
+		if targetsContext(targets, clusterCfg.Kubeconfig, clusterCfg.Context) {
+			continue
+		}
+		targets = append(targets, clusterTarget{
+			name:       clusterCfg.Name,
+			kubeconfig: clusterCfg.Kubeconfig,
+			context:    clusterCfg.Context,
+			auth:       AuthOptions(clusterCfg),
+			discovered: true,
+		})
+	}
+	return targets, err
+}
+
+// targetsContext reports whether one of the targets uses the given context
+// of the same kubeconfig
+func targetsContext(targets []clusterTarget, kubeconfig, contextName string) bool {
+	for _, target := range targets {
+		if target.context == contextName && config.SameKubeconfig(target.kubeconfig, kubeconfig) {
+			return true
+		}
+	}
+	return false
+}
+
+// AuthOptions returns the credential and impersonation overrides of a
+// configured cluster for its client
+func AuthOptions(cluster config.ClusterConfig) k8s.AuthOptions {
+	auth := k8s.AuthOptions{
+		As:       cluster.As,
+		AsGroups: cluster.AsGroups,
+	}
+	if cluster.TokenFile != "" {
+		auth.TokenFile = config.ExpandPath(cluster.TokenFile)
+	}
+	if cluster.Exec != nil {
+		auth.Exec = &k8s.ExecOptions{
+			Command:    cluster.Exec.Command,
+			Args:       cluster.Exec.Args,
+			Env:        cluster.Exec.Env,
+			APIVersion: cluster.Exec.APIVersion,
+		}
+	}
+	return auth
+}
+
+// ClusterAuth returns the credential and impersonation overrides of the
+// named cluster, none when it is not configured
+func ClusterAuth(cfg *config.Config, name string) k8s.AuthOptions {
+	cluster, ok := cfg.GetCluster(name)
+	if !ok {
+		return k8s.AuthOptions{}
+	}
+	return AuthOptions(*cluster)
+}
@@ -0,0 +1,121 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/malagant/fluxcli/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clusterNames returns the names of the clusters the manager tracks
+func clusterNames(m *Manager) []string {
+	var names []string
+	for _, state := range m.GetConnectionStates() {
+		names = append(names, state.Cluster)
+	}
+	return names
+}
+
+func TestApplyConfig(t *testing.T) {
+	cfg := testConfig()
+	cfg.CurrentCluster = "default"
+	cfg.CurrentKubeConfig = "/nonexistent/kubeconfig"
+	cfg.Clusters = []config.ClusterConfig{
+		{Name: "prod", Context: "prod", Kubeconfig: "/nonexistent/kubeconfig"},
+		{Name: "staging", Context: "staging", Kubeconfig: "/nonexistent/kubeconfig"},
+	}
+
+	m := NewManager(cfg)
+	require.NoError(t, m.Start())
+	defer m.Stop()
+	assert.Equal(t, []string{"default", "prod", "staging"}, clusterNames(m))
+
+	reloaded := *cfg
+	reloaded.Clusters = []config.ClusterConfig{
+		{Name: "prod", Context: "prod", Kubeconfig: "/nonexistent/kubeconfig"},
+		{Name: "dev", Context: "dev", Kubeconfig: "/nonexistent/kubeconfig"},
+	}
+	reloaded.Defaults.RefreshInterval = 30 * time.Second
+	require.NoError(t, m.ApplyConfig(&reloaded))
+
+	assert.Equal(t, []string{"default", "prod", "dev"}, clusterNames(m))
+	assert.Equal(t, 30*time.Second, m.refreshInterval())
+
+	removed := false
+	timeout := time.After(5 * time.Second)
+	for !removed {
+		select {
+		case update := <-m.GetConnectionUpdates():
+			removed = update.Cluster == "staging" && update.State == ConnectionRemoved
+		case <-timeout:
+			t.Fatal("no removal published for staging")
+		}
+	}
+	_, tracked := m.GetConnectionState("staging")
+	assert.False(t, tracked)
+}
+
+func TestConfiguredTargets_SkipsConfiguredContexts(t *testing.T) {
+	dir := t.TempDir()
+	kubeconfig := filepath.Join(dir, "kubeconfig")
+	require.NoError(t, os.WriteFile(kubeconfig, []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: dev
+  cluster:
+    server: https://127.0.0.1:6443
+contexts:
+- name: kind-dev
+  context:
+    cluster: dev
+    user: dev
+- name: kind-prod
+  context:
+    cluster: dev
+    user: dev
+users:
+- name: dev
+  user:
+    token: secret
+`), 0600))
+
+	cfg := testConfig()
+	cfg.CurrentCluster = "default"
+	cfg.CurrentKubeConfig = "/nonexistent/kubeconfig"
+	// The same kubeconfig, spelled differently
+	cfg.Clusters = []config.ClusterConfig{
+		{Name: "dev", Context: "kind-dev", Kubeconfig: dir + "/./kubeconfig"},
+	}
+	cfg.Discovery = config.DiscoveryConfig{Enabled: true, Kubeconfig: kubeconfig}
+
+	targets, err := configuredTargets(cfg)
+	require.NoError(t, err)
+	var names []string
+	for _, target := range targets {
+		names = append(names, target.name)
+	}
+	assert.Equal(t, []string{"default", "dev", "kind-prod"}, names)
+}
+
+func TestClusterAuth(t *testing.T) {
+	cluster := config.ClusterConfig{
+		As:        "system:serviceaccount:team-a:flux",
+		TokenFile: "~/tokens/prod",
+		Exec:      &config.ExecConfig{Command: "kubelogin", Env: map[string]string{"MODE": "azurecli"}},
+	}
+
+	auth := AuthOptions(cluster)
+	assert.Equal(t, "system:serviceaccount:team-a:flux", auth.As)
+	assert.Equal(t, config.ExpandPath("~/tokens/prod"), auth.TokenFile)
+	require.NotNil(t, auth.Exec)
+	assert.Equal(t, "kubelogin", auth.Exec.Command)
+	assert.Equal(t, map[string]string{"MODE": "azurecli"}, auth.Exec.Env)
+
+	cfg := &config.Config{Clusters: []config.ClusterConfig{{Name: "prod", As: "alice"}}}
+	assert.Equal(t, "alice", ClusterAuth(cfg, "prod").As)
+	assert.Empty(t, ClusterAuth(cfg, "dev").As)
+}
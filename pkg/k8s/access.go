@@ -0,0 +1,185 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FluxResource is the API resource of a Flux kind
+type FluxResource struct {
+	Kind     string
+	Group    string
+	Resource string // Plural name, as used in RBAC rules
+}
+
+// String returns the resource as resource.group, as in RBAC error messages
+func (r FluxResource) String() string {
+	return fmt.Sprintf("%s.%s", r.Resource, r.Group)
+}
+
+// FluxResources are the resources of the Flux kinds, in the order of
+// FluxKinds
+var FluxResources = []FluxResource{
+	{Kind: "GitRepository", Group: "source.toolkit.fluxcd.io", Resource: "gitrepositories"},
+	{Kind: "OCIRepository", Group: "source.toolkit.fluxcd.io", Resource: "ocirepositories"},
+	{Kind: "HelmRepository", Group: "source.toolkit.fluxcd.io", Resource: "helmrepositories"},
+	{Kind: "HelmChart", Group: "source.toolkit.fluxcd.io", Resource: "helmcharts"},
+	{Kind: "Bucket", Group: "source.toolkit.fluxcd.io", Resource: "buckets"},
+	{Kind: "Kustomization", Group: "kustomize.toolkit.fluxcd.io", Resource: "kustomizations"},
+	{Kind: "HelmRelease", Group: "helm.toolkit.fluxcd.io", Resource: "helmreleases"},
+	{Kind: "ImageRepository", Group: "image.toolkit.fluxcd.io", Resource: "imagerepositories"},
+	{Kind: "ImagePolicy", Group: "image.toolkit.fluxcd.io", Resource: "imagepolicies"},
+	{Kind: "ImageUpdateAutomation", Group: "image.toolkit.fluxcd.io", Resource: "imageupdateautomations"},
+	{Kind: "Alert", Group: "notification.toolkit.fluxcd.io", Resource: "alerts"},
+	{Kind: "Provider", Group: "notification.toolkit.fluxcd.io", Resource: "providers"},
+	{Kind: "Receiver", Group: "notification.toolkit.fluxcd.io", Resource: "receivers"},
+}
+
+// FluxResourceForKind returns the resource of a Flux kind
+func FluxResourceForKind(kind string) (FluxResource, bool) {
+	for _, resource := range FluxResources {
+		if strings.EqualFold(resource.Kind, kind) {
+			return resource, true
+		}
+	}
+	return FluxResource{}, false
+}
+
+// CanI asks the API server whether the current user may perform a verb on
+// a resource in a namespace, all namespaces when empty. The reason explains
+// a denial when the authorizer gives one.
+func (c *Client) CanI(ctx context.Context, verb string, resource FluxResource, namespace string) (allowed bool, reason string, err error) {
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      verb,
+				Group:     resource.Group,
+				Resource:  resource.Resource,
+			},
+		},
+	}
+
+	result, err := c.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return false, "", fmt.Errorf("failed to review access to %s: %w", resource, err)
+	}
+	return result.Status.Allowed, result.Status.Reason, nil
+}
+
+// Permission tells whether the user may perform an action
+type Permission int
+
+const (
+	PermissionUnknown Permission = iota // Not reviewed, or the authorizer cannot tell
+	PermissionAllowed
+	PermissionDenied
+)
+
+// OperationVerbs are the verbs suspend, resume and reconcile need: they get
+// the object and update it
+var OperationVerbs = []string{"get", "update"}
+
+// Permissions are the rules of the user in a namespace, as listed by a
+// SelfSubjectRulesReview
+type Permissions struct {
+	Namespace  string
+	Rules      []authorizationv1.ResourceRule
+	Incomplete bool // The authorizer could not list every rule
+
+	// Access review results by verb/resource, asked for when the rules are
+	// incomplete
+	reviewed map[string]bool
+}
+
+// Check tells whether the rules allow a verb on every object of a resource.
+// Rules restricted to some objects and incomplete rules leave the
+// permission unknown rather than denied.
+func (p *Permissions) Check(verb string, resource FluxResource) Permission {
+	if allowed, ok := p.reviewed[verb+"/"+resource.Resource]; ok {
+		if allowed {
+			return PermissionAllowed
+		}
+		return PermissionDenied
+	}
+
+	permission := PermissionDenied
+	if p.Incomplete {
+		permission = PermissionUnknown
+	}
+	for _, rule := range p.Rules {
+		if !matchesRule(rule.Verbs, verb) || !matchesRule(rule.APIGroups, resource.Group) || !matchesRule(rule.Resources, resource.Resource) {
+			continue
+		}
+		if len(rule.ResourceNames) == 0 {
+			return PermissionAllowed
+		}
+		permission = PermissionUnknown
+	}
+	return permission
+}
+
+// CheckAll tells whether the rules allow all verbs on a resource: denied
+// when one verb is denied, unknown when one is unknown
+func (p *Permissions) CheckAll(verbs []string, resource FluxResource) Permission {
+	result := PermissionAllowed
+	for _, verb := range verbs {
+		switch p.Check(verb, resource) {
+		case PermissionDenied:
+			return PermissionDenied
+		case PermissionUnknown:
+			result = PermissionUnknown
+		}
+	}
+	return result
+}
+
+// matchesRule reports whether a rule's values contain value or the
+// wildcard
+func matchesRule(values []string, value string) bool {
+	for _, v := range values {
+		if v == value || v == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// ReviewPermissions lists the rules of the user in a namespace. When the
+// authorizer cannot list every rule, as webhook authorizers, the
+// OperationVerbs are reviewed for each Flux kind instead.
+func (c *Client) ReviewPermissions(ctx context.Context, namespace string) (*Permissions, error) {
+	review := &authorizationv1.SelfSubjectRulesReview{
+		Spec: authorizationv1.SelfSubjectRulesReviewSpec{Namespace: namespace},
+	}
+	result, err := c.AuthorizationV1().SelfSubjectRulesReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to review rules in %s: %w", namespace, err)
+	}
+
+	permissions := &Permissions{
+		Namespace:  namespace,
+		Rules:      result.Status.ResourceRules,
+		Incomplete: result.Status.Incomplete,
+	}
+	if !permissions.Incomplete {
+		return permissions, nil
+	}
+
+	permissions.reviewed = make(map[string]bool)
+	for _, resource := range FluxResources {
+		for _, verb := range OperationVerbs {
+			allowed, _, err := c.CanI(ctx, verb, resource, namespace)
+			if err != nil {
+				// Keep what is known, the rest stays unknown
+				return permissions, nil
+			}
+			permissions.reviewed[verb+"/"+resource.Resource] = allowed
+		}
+	}
+	return permissions, nil
+}
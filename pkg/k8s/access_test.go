@@ -0,0 +1,69 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// reviewRules makes the rules reviews of a fake clientset return rules
+func reviewRules(clientset *fake.Clientset, incomplete bool, rules ...authorizationv1.ResourceRule) {
+	clientset.PrependReactor("create", "selfsubjectrulesreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectRulesReview)
+		review.Status.ResourceRules = rules
+		review.Status.Incomplete = incomplete
+		return true, review, nil
+	})
+}
+
+func TestFluxResourceForKind(t *testing.T) {
+	resource, ok := FluxResourceForKind("kustomization")
+	require.True(t, ok)
+	assert.Equal(t, "kustomizations.kustomize.toolkit.fluxcd.io", resource.String())
+
+	_, ok = FluxResourceForKind("Deployment")
+	assert.False(t, ok)
+	assert.Len(t, FluxResources, len(FluxKinds))
+}
+
+func TestReviewPermissions(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	reviewRules(clientset, false,
+		authorizationv1.ResourceRule{Verbs: []string{"get", "list", "watch"}, APIGroups: []string{"*"}, Resources: []string{"*"}},
+		authorizationv1.ResourceRule{Verbs: []string{"update"}, APIGroups: []string{"kustomize.toolkit.fluxcd.io"}, Resources: []string{"kustomizations"}},
+		authorizationv1.ResourceRule{Verbs: []string{"*"}, APIGroups: []string{"helm.toolkit.fluxcd.io"}, Resources: []string{"helmreleases"}, ResourceNames: []string{"podinfo"}},
+	)
+	c := &Client{Interface: clientset}
+
+	permissions, err := c.ReviewPermissions(context.Background(), "apps")
+	require.NoError(t, err)
+
+	kustomizations, _ := FluxResourceForKind("Kustomization")
+	gitRepositories, _ := FluxResourceForKind("GitRepository")
+	helmReleases, _ := FluxResourceForKind("HelmRelease")
+	assert.Equal(t, PermissionAllowed, permissions.CheckAll(OperationVerbs, kustomizations))
+	assert.Equal(t, PermissionDenied, permissions.CheckAll(OperationVerbs, gitRepositories))
+	// Rules for some objects only do not tell about the others
+	assert.Equal(t, PermissionUnknown, permissions.Check("update", helmReleases))
+}
+
+func TestReviewPermissions_Incomplete(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	reviewRules(clientset, true)
+	allowAccess(clientset, "update/gitrepositories")
+	c := &Client{Interface: clientset}
+
+	permissions, err := c.ReviewPermissions(context.Background(), "apps")
+	require.NoError(t, err)
+
+	kustomizations, _ := FluxResourceForKind("Kustomization")
+	gitRepositories, _ := FluxResourceForKind("GitRepository")
+	assert.Equal(t, PermissionAllowed, permissions.CheckAll(OperationVerbs, kustomizations))
+	assert.Equal(t, PermissionDenied, permissions.CheckAll(OperationVerbs, gitRepositories))
+}
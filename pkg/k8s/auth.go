@@ -0,0 +1,112 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// DefaultExecAPIVersion is the credential API version used for exec
+// plugins that do not set one
+const DefaultExecAPIVersion = "client.authentication.k8s.io/v1"
+
+// AuthOptions override the credentials of a kubeconfig context. The zero
+// value keeps the kubeconfig credentials.
+type AuthOptions struct {
+	As        string       // User to impersonate, e.g. system:serviceaccount:team-a:flux
+	AsGroups  []string     // Groups to impersonate, only with As
+	TokenFile string       // Bearer token file, replaces the kubeconfig credentials
+	Exec      *ExecOptions // Credential plugin, replaces the kubeconfig credentials
+}
+
+// ExecOptions configure an exec credential plugin
+type ExecOptions struct {
+	Command    string
+	Args       []string
+	Env        map[string]string
+	APIVersion string
+}
+
+// apply replaces the credentials and impersonation of a client configuration.
+// A token file or exec plugin replaces every credential of the kubeconfig
+// user, so the two never combine; a token file wins over an exec plugin.
+func (a AuthOptions) apply(config *rest.Config) {
+	switch {
+	case a.TokenFile != "":
+		clearCredentials(config)
+		config.BearerTokenFile = a.TokenFile
+
+	case a.Exec != nil:
+		clearCredentials(config)
+		config.ExecProvider = a.Exec.execConfig()
+	}
+
+	if a.As != "" || len(a.AsGroups) > 0 {
+		config.Impersonate = rest.ImpersonationConfig{
+			UserName: a.As,
+			Groups:   a.AsGroups,
+		}
+	}
+}
+
+// clearCredentials removes every credential of the kubeconfig user,
+// including a client certificate, which would otherwise still authenticate
+func clearCredentials(config *rest.Config) {
+	config.BearerToken, config.BearerTokenFile = "", ""
+	config.Username, config.Password = "", ""
+	config.AuthProvider = nil
+	config.ExecProvider = nil
+	config.CertFile, config.KeyFile = "", ""
+	config.CertData, config.KeyData = nil, nil
+}
+
+// execConfig converts the options to a client-go exec configuration. The
+// plugin never runs interactively, as the terminal may belong to the UI.
+func (e *ExecOptions) execConfig() *clientcmdapi.ExecConfig {
+	exec := &clientcmdapi.ExecConfig{
+		Command:         e.Command,
+		Args:            e.Args,
+		APIVersion:      e.APIVersion,
+		InteractiveMode: clientcmdapi.NeverExecInteractiveMode,
+	}
+	if exec.APIVersion == "" {
+		exec.APIVersion = DefaultExecAPIVersion
+	}
+
+	names := make([]string, 0, len(e.Env))
+	for name := range e.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		exec.Env = append(exec.Env, clientcmdapi.ExecEnvVar{Name: name, Value: e.Env[name]})
+	}
+
+	return exec
+}
+
+// Identity returns the user the cluster authenticates the client as, from a
+// SelfSubjectReview. Clusters that do not serve the review, before
+// Kubernetes 1.28, are reported by the impersonated user if there is one.
+func (c *Client) Identity(ctx context.Context) (string, error) {
+	var impersonated string
+	if c.Config != nil {
+		impersonated = c.Config.Impersonate.UserName
+	}
+
+	review, err := c.AuthenticationV1().SelfSubjectReviews().Create(ctx, &authenticationv1.SelfSubjectReview{}, metav1.CreateOptions{})
+	switch {
+	case err == nil && review.Status.UserInfo.Username != "":
+		return review.Status.UserInfo.Username, nil
+	case impersonated != "":
+		return impersonated, nil
+	case err != nil:
+		return "", fmt.Errorf("failed to review the user identity: %w", err)
+	}
+	return "", nil
+}
@@ -0,0 +1,134 @@
+package k8s
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const authKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+  - name: prod
+    cluster:
+      server: https://prod.example.com
+contexts:
+  - name: prod
+    context:
+      cluster: prod
+      user: admin
+current-context: prod
+users:
+  - name: admin
+    user:
+      token: admin-token
+      exec:
+        apiVersion: client.authentication.k8s.io/v1
+        command: kubelogin
+        interactiveMode: IfAvailable
+`
+
+// certKubeconfig authenticates with a client certificate, as kind,
+// minikube and kubeadm write it
+const certKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+  - name: kind
+    cluster:
+      server: https://127.0.0.1:6443
+contexts:
+  - name: kind
+    context:
+      cluster: kind
+      user: kind-admin
+current-context: kind
+users:
+  - name: kind-admin
+    user:
+      client-certificate-data: Y2VydA==
+      client-key-data: a2V5
+`
+
+func writeKubeconfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestBuildConfig_KubeconfigCredentials(t *testing.T) {
+	config, err := buildConfig(writeKubeconfig(t, authKubeconfig), "prod", AuthOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "admin-token", config.BearerToken)
+	require.NotNil(t, config.ExecProvider)
+	assert.Equal(t, "kubelogin", config.ExecProvider.Command)
+	assert.Empty(t, config.Impersonate.UserName)
+}
+
+func TestBuildConfig_AuthOptions(t *testing.T) {
+	kubeconfig := writeKubeconfig(t, authKubeconfig)
+
+	// Impersonation keeps the kubeconfig credentials
+	config, err := buildConfig(kubeconfig, "prod", AuthOptions{
+		As:       "system:serviceaccount:team-a:flux",
+		AsGroups: []string{"team-a"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "admin-token", config.BearerToken)
+	assert.Equal(t, "system:serviceaccount:team-a:flux", config.Impersonate.UserName)
+	assert.Equal(t, []string{"team-a"}, config.Impersonate.Groups)
+
+	// A token file replaces them
+	config, err = buildConfig(kubeconfig, "prod", AuthOptions{TokenFile: "/var/run/secrets/token"})
+	require.NoError(t, err)
+	assert.Empty(t, config.BearerToken)
+	assert.Equal(t, "/var/run/secrets/token", config.BearerTokenFile)
+	assert.Nil(t, config.ExecProvider)
+
+	// So does an exec plugin
+	config, err = buildConfig(kubeconfig, "prod", AuthOptions{Exec: &ExecOptions{
+		Command: "aws",
+		Args:    []string{"eks", "get-token", "--cluster-name", "prod"},
+		Env:     map[string]string{"AWS_REGION": "eu-west-1", "AWS_PROFILE": "prod"},
+	}})
+	require.NoError(t, err)
+	assert.Empty(t, config.BearerToken)
+	require.NotNil(t, config.ExecProvider)
+	assert.Equal(t, "aws", config.ExecProvider.Command)
+	assert.Equal(t, DefaultExecAPIVersion, config.ExecProvider.APIVersion)
+	assert.Equal(t, clientcmdapi.NeverExecInteractiveMode, config.ExecProvider.InteractiveMode)
+	assert.Equal(t, []clientcmdapi.ExecEnvVar{
+		{Name: "AWS_PROFILE", Value: "prod"},
+		{Name: "AWS_REGION", Value: "eu-west-1"},
+	}, config.ExecProvider.Env)
+}
+
+func TestBuildConfig_AuthOptionsReplaceClientCertificate(t *testing.T) {
+	kubeconfig := writeKubeconfig(t, certKubeconfig)
+
+	config, err := buildConfig(kubeconfig, "kind", AuthOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("cert"), config.CertData)
+
+	config, err = buildConfig(kubeconfig, "kind", AuthOptions{TokenFile: "/var/run/secrets/token"})
+	require.NoError(t, err)
+	assert.Equal(t, "/var/run/secrets/token", config.BearerTokenFile)
+	assert.Empty(t, config.CertData)
+	assert.Empty(t, config.KeyData)
+	assert.Empty(t, config.CertFile)
+	assert.Empty(t, config.KeyFile)
+	assert.Nil(t, config.ExecProvider)
+
+	config, err = buildConfig(kubeconfig, "kind", AuthOptions{Exec: &ExecOptions{Command: "kubelogin"}})
+	require.NoError(t, err)
+	require.NotNil(t, config.ExecProvider)
+	assert.Equal(t, "kubelogin", config.ExecProvider.Command)
+	assert.Empty(t, config.CertData)
+	assert.Empty(t, config.KeyData)
+	assert.Empty(t, config.BearerToken)
+	assert.Empty(t, config.BearerTokenFile)
+}
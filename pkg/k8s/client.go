@@ -3,6 +3,8 @@ package k8s
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	helmv2 "github.com/fluxcd/helm-controller/api/v2beta1"
@@ -12,6 +14,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -26,11 +29,14 @@ type Client struct {
 	Context   string
 	Cluster   string
 	Namespace string
+
+	coreEvents atomic.Bool // The cluster does not serve events.k8s.io/v1
 }
 
-// NewClient creates a new Kubernetes client
-func NewClient(kubeconfig, context, namespace string) (*Client, error) {
-	config, err := buildConfig(kubeconfig, context)
+// NewClient creates a new Kubernetes client. The auth options override the
+// credentials of the kubeconfig context.
+func NewClient(kubeconfig, context, namespace string, auth AuthOptions) (*Client, error) {
+	config, err := buildConfig(kubeconfig, context, auth)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build config: %w", err)
 	}
@@ -74,7 +80,7 @@ func NewClient(kubeconfig, context, namespace string) (*Client, error) {
 }
 
 // buildConfig builds a Kubernetes client configuration
-func buildConfig(kubeconfig, context string) (*rest.Config, error) {
+func buildConfig(kubeconfig, context string, auth AuthOptions) (*rest.Config, error) {
 	configLoader := clientcmd.NewDefaultClientConfigLoadingRules()
 	if kubeconfig != "" {
 		configLoader.ExplicitPath = kubeconfig
@@ -90,7 +96,13 @@ func buildConfig(kubeconfig, context string) (*rest.Config, error) {
 		configOverrides,
 	)
 
-	return clientConfig.ClientConfig()
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	auth.apply(config)
+
+	return config, nil
 }
 
 // TestConnection tests the connection to the Kubernetes cluster
@@ -102,6 +114,43 @@ func (c *Client) TestConnection(ctx context.Context) error {
 	return err
 }
 
+// HasFluxCRDs reports whether the cluster serves any FluxCD toolkit API
+// group. The discovery requests end at the deadline of ctx.
+func (c *Client) HasFluxCRDs(ctx context.Context) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, fmt.Errorf("failed to discover API groups: %w", err)
+	}
+	groups, err := c.discoveryFor(ctx).ServerGroups()
+	if err != nil {
+		return false, fmt.Errorf("failed to discover API groups: %w", err)
+	}
+
+	for _, group := range groups.Groups {
+		if strings.HasSuffix(group.Name, fluxGroupSuffix) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// discoveryFor returns a discovery client whose requests time out at the
+// deadline of ctx, as the discovery calls do not take a context
+func (c *Client) discoveryFor(ctx context.Context) discovery.DiscoveryInterface {
+	deadline, ok := ctx.Deadline()
+	if !ok || c.Config == nil {
+		return c.Discovery()
+	}
+
+	config := rest.CopyConfig(c.Config)
+	config.Timeout = time.Until(deadline)
+	client, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return c.Discovery()
+	}
+	return client
+}
+
 // GetCurrentContext returns the current Kubernetes context
 func (c *Client) GetCurrentContext() string {
 	return c.Context
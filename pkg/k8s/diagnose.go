@@ -0,0 +1,248 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CheckStatus is the outcome of a diagnostic check
+type CheckStatus string
+
+const (
+	CheckPassed  CheckStatus = "passed"
+	CheckWarning CheckStatus = "warning"
+	CheckFailed  CheckStatus = "failed"
+)
+
+// Icon returns the symbol the check is printed with
+func (s CheckStatus) Icon() string {
+	switch s {
+	case CheckPassed:
+		return "✔"
+	case CheckWarning:
+		return "!"
+	default:
+		return "✗"
+	}
+}
+
+// DiagnosticVerbs are the verbs FluxCLI needs on every Flux kind: list and
+// watch to show the objects, patch to suspend, resume and reconcile them
+var DiagnosticVerbs = []string{"list", "watch", "patch"}
+
+// Check is the result of one diagnostic check
+type Check struct {
+	Name    string
+	Status  CheckStatus
+	Message string
+	Hint    string // What to do about a warning or failure
+}
+
+// Diagnosis is the result of diagnosing a cluster
+type Diagnosis struct {
+	Namespace string // Namespace the access was reviewed in, all when empty
+	Checks    []Check
+}
+
+// Count returns the number of checks with a status
+func (d *Diagnosis) Count(status CheckStatus) int {
+	count := 0
+	for _, check := range d.Checks {
+		if check.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// Healthy reports whether no check failed
+func (d *Diagnosis) Healthy() bool {
+	return d.Count(CheckFailed) == 0
+}
+
+// Status returns the worst status of the checks
+func (d *Diagnosis) Status() CheckStatus {
+	switch {
+	case d.Count(CheckFailed) > 0:
+		return CheckFailed
+	case d.Count(CheckWarning) > 0:
+		return CheckWarning
+	default:
+		return CheckPassed
+	}
+}
+
+// Summary describes the diagnosis in a few words
+func (d *Diagnosis) Summary() string {
+	failed, warnings := d.Count(CheckFailed), d.Count(CheckWarning)
+	switch {
+	case failed == 0 && warnings == 0:
+		return "all checks passed"
+	case failed == 0:
+		return fmt.Sprintf("%d warning(s)", warnings)
+	default:
+		return fmt.Sprintf("%d failed, %d warning(s)", failed, warnings)
+	}
+}
+
+func (d *Diagnosis) add(check Check) {
+	d.Checks = append(d.Checks, check)
+}
+
+// ConnectionCheck turns the error of connecting to a cluster into a check
+// with a hint on how to fix it
+func ConnectionCheck(err error) Check {
+	check := Check{Name: "Connectivity", Status: CheckFailed, Message: err.Error()}
+	var netErr net.Error
+	switch {
+	case apierrors.IsUnauthorized(err):
+		check.Hint = "the credentials were rejected: log in again or refresh the token in your kubeconfig"
+	case apierrors.IsForbidden(err):
+		// Authenticated, the missing permission is only on the probe
+		check.Status = CheckPassed
+		check.Message = "connected, but the user may not read namespaces"
+	case errors.As(err, &netErr) && netErr.Timeout(), errors.Is(err, context.DeadlineExceeded):
+		check.Hint = "the API server did not answer in time: check the network, VPN or proxy"
+	case strings.Contains(err.Error(), "connection refused"), strings.Contains(err.Error(), "no such host"):
+		check.Hint = "the API server cannot be reached: check the server URL of the kubeconfig context"
+	case strings.Contains(err.Error(), "certificate"):
+		check.Hint = "the server certificate is not trusted: check certificate-authority-data of the kubeconfig cluster"
+	case strings.Contains(err.Error(), "kubeconfig"), strings.Contains(err.Error(), "context"):
+		check.Hint = "check the kubeconfig and context of the cluster in the FluxCLI config"
+	default:
+		check.Hint = "check that kubectl works with the same kubeconfig and context"
+	}
+	return check
+}
+
+// Diagnose checks what FluxCLI needs from the cluster: that the API server
+// answers, that the Flux APIs FluxCLI reads are served, that the namespace
+// exists, and that the user may list, watch and patch every Flux kind in
+// the namespace, all namespaces when empty
+func (c *Client) Diagnose(ctx context.Context, namespace string) *Diagnosis {
+	diagnosis := &Diagnosis{Namespace: namespace}
+
+	if err := c.TestConnection(ctx); err != nil {
+		check := ConnectionCheck(err)
+		diagnosis.add(check)
+		if check.Status == CheckFailed {
+			return diagnosis
+		}
+	} else {
+		diagnosis.add(Check{Name: "Connectivity", Status: CheckPassed, Message: "API server reachable"})
+	}
+
+	install, err := c.DiscoverFlux(ctx, "")
+	if err != nil {
+		diagnosis.add(Check{Name: "Flux APIs", Status: CheckFailed, Message: err.Error(),
+			Hint: "the user may need permission to use discovery and list deployments in flux-system"})
+		install = nil
+	} else {
+		diagnoseAPIs(diagnosis, install)
+	}
+
+	if namespace != "" {
+		diagnosis.add(c.namespaceCheck(ctx, namespace))
+	}
+
+	for _, resource := range FluxResources {
+		if install != nil && !install.Serves(resource.Group) {
+			// Already reported as a missing API, reviews would pass or fail misleadingly
+			continue
+		}
+		diagnosis.add(c.accessCheck(ctx, resource, namespace))
+	}
+	return diagnosis
+}
+
+// diagnoseAPIs adds a check per API group FluxCLI reads
+func diagnoseAPIs(diagnosis *Diagnosis, install *FluxInstall) {
+	for _, apiVersion := range readAPIVersions {
+		group, _, _ := strings.Cut(apiVersion, "/")
+		check := Check{Name: "API " + group}
+		switch {
+		case install.Serves(apiVersion):
+			check.Status = CheckPassed
+			check.Message = fmt.Sprintf("%s served", apiVersion)
+		case install.Serves(group):
+			check.Status = CheckWarning
+			check.Message = fmt.Sprintf("%s is not served (served: %s), objects of this group are not shown",
+				apiVersion, strings.Join(install.api(group).Versions, ", "))
+			check.Hint = "upgrade or downgrade Flux to a version serving " + apiVersion
+		default:
+			check.Status = CheckFailed
+			check.Message = "CRDs not installed, lists of this group stay empty"
+			check.Hint = "install Flux on the cluster (flux install) or check that kubectl get crds lists " + group
+		}
+		diagnosis.add(check)
+	}
+}
+
+// namespaceCheck checks that the namespace FluxCLI shows exists
+func (c *Client) namespaceCheck(ctx context.Context, namespace string) Check {
+	check := Check{Name: "Namespace " + namespace, Status: CheckPassed, Message: "exists"}
+	_, err := c.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	switch {
+	case err == nil:
+	case apierrors.IsNotFound(err):
+		check.Status = CheckFailed
+		check.Message = "namespace does not exist"
+		check.Hint = "pick another namespace with --namespace or the cluster's namespace in the config"
+	case apierrors.IsForbidden(err):
+		check.Status = CheckWarning
+		check.Message = "the user may not read the namespace, its existence is unknown"
+	default:
+		check.Status = CheckWarning
+		check.Message = err.Error()
+	}
+	return check
+}
+
+// accessCheck reviews the DiagnosticVerbs on a Flux kind. Missing list or
+// watch fail the check as the objects cannot be shown, missing patch only
+// warns as the objects are read-only then.
+func (c *Client) accessCheck(ctx context.Context, resource FluxResource, namespace string) Check {
+	check := Check{Name: "Access " + resource.Kind, Status: CheckPassed}
+
+	var denied []string
+	for _, verb := range DiagnosticVerbs {
+		allowed, _, err := c.CanI(ctx, verb, resource, namespace)
+		if err != nil {
+			check.Status = CheckWarning
+			check.Message = err.Error()
+			check.Hint = "the user may need permission to create selfsubjectaccessreviews"
+			return check
+		}
+		if !allowed {
+			denied = append(denied, verb)
+		}
+	}
+
+	scope := "in all namespaces"
+	if namespace != "" {
+		scope = "in namespace " + namespace
+	}
+	if len(denied) == 0 {
+		check.Message = fmt.Sprintf("%s allowed %s", strings.Join(DiagnosticVerbs, ", "), scope)
+		return check
+	}
+
+	check.Message = fmt.Sprintf("%s denied %s", strings.Join(denied, ", "), scope)
+	check.Hint = fmt.Sprintf("ask for a role granting %s on %s %s", strings.Join(denied, ", "), resource, scope)
+	check.Status = CheckWarning
+	for _, verb := range denied {
+		if verb != "patch" {
+			check.Status = CheckFailed
+		}
+	}
+	if check.Status == CheckWarning {
+		check.Message += ", suspend, resume and reconcile will fail"
+	}
+	return check
+}
@@ -0,0 +1,102 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// allowAccess makes the access reviews of a fake clientset allow everything
+// but the denied verb/resource pairs
+func allowAccess(clientset *fake.Clientset, denied ...string) {
+	clientset.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		attributes := review.Spec.ResourceAttributes
+		review.Status.Allowed = true
+		for _, deny := range denied {
+			if deny == attributes.Verb+"/"+attributes.Resource {
+				review.Status.Allowed = false
+			}
+		}
+		return true, review, nil
+	})
+}
+
+func TestDiagnose(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "apps"}},
+	)
+	clientset.Resources = fluxAPIs(
+		"source.toolkit.fluxcd.io/v1",
+		"kustomize.toolkit.fluxcd.io/v1",
+		"helm.toolkit.fluxcd.io/v2",
+	)
+	allowAccess(clientset, "patch/kustomizations", "list/helmreleases")
+	c := &Client{Interface: clientset}
+
+	diagnosis := c.Diagnose(context.Background(), "apps")
+
+	checks := map[string]Check{}
+	for _, check := range diagnosis.Checks {
+		checks[check.Name] = check
+	}
+	assert.Equal(t, CheckPassed, checks["Connectivity"].Status)
+	assert.Equal(t, CheckPassed, checks["API source.toolkit.fluxcd.io"].Status)
+	assert.Equal(t, CheckWarning, checks["API helm.toolkit.fluxcd.io"].Status)
+	assert.Equal(t, CheckPassed, checks["Namespace apps"].Status)
+	assert.Equal(t, CheckPassed, checks["Access GitRepository"].Status)
+
+	kustomization := checks["Access Kustomization"]
+	assert.Equal(t, CheckWarning, kustomization.Status)
+	assert.Equal(t, "patch denied in namespace apps, suspend, resume and reconcile will fail", kustomization.Message)
+	assert.Equal(t, "ask for a role granting patch on kustomizations.kustomize.toolkit.fluxcd.io in namespace apps", kustomization.Hint)
+
+	assert.Equal(t, CheckFailed, checks["Access HelmRelease"].Status)
+
+	// Kinds of groups that are not served are not reviewed
+	assert.NotContains(t, checks, "Access Alert")
+	assert.False(t, diagnosis.Healthy())
+	assert.Equal(t, "1 failed, 2 warning(s)", diagnosis.Summary())
+}
+
+func TestDiagnose_MissingNamespace(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}})
+	allowAccess(clientset)
+	c := &Client{Interface: clientset}
+
+	diagnosis := c.Diagnose(context.Background(), "typo")
+
+	var namespace Check
+	for _, check := range diagnosis.Checks {
+		if check.Name == "Namespace typo" {
+			namespace = check
+		}
+	}
+	assert.Equal(t, CheckFailed, namespace.Status)
+	assert.NotEmpty(t, namespace.Hint)
+}
+
+func TestConnectionCheck(t *testing.T) {
+	unauthorized := ConnectionCheck(apierrors.NewUnauthorized("token expired"))
+	assert.Equal(t, CheckFailed, unauthorized.Status)
+	assert.Contains(t, unauthorized.Hint, "log in again")
+
+	forbidden := ConnectionCheck(apierrors.NewForbidden(schema.GroupResource{Resource: "namespaces"}, "default", errors.New("denied")))
+	assert.Equal(t, CheckPassed, forbidden.Status)
+
+	refused := ConnectionCheck(errors.New("dial tcp 127.0.0.1:6443: connect: connection refused"))
+	assert.Contains(t, refused.Hint, "server URL")
+	require.Equal(t, "✗", refused.Status.Icon())
+}
@@ -0,0 +1,219 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// eventListConcurrency bounds the event list requests of a GetEvents call
+// that run at the same time
+const eventListConcurrency = 4
+
+// fluxGroupSuffix is the suffix of the API groups of the Flux controllers
+const fluxGroupSuffix = ".toolkit.fluxcd.io"
+
+// FluxKinds are the kinds of the Flux controllers whose events are listed
+var FluxKinds = []string{
+	"GitRepository",
+	"OCIRepository",
+	"HelmRepository",
+	"HelmChart",
+	"Bucket",
+	"Kustomization",
+	"HelmRelease",
+	"ImageRepository",
+	"ImagePolicy",
+	"ImageUpdateAutomation",
+	"Alert",
+	"Provider",
+	"Receiver",
+}
+
+// EventListOptions selects the events listed by GetEvents
+type EventListOptions struct {
+	Namespace string        // All namespaces when empty
+	Kinds     []string      // Kinds of the involved objects, FluxKinds when empty
+	Since     time.Duration // Only events seen within this window, all when zero
+}
+
+// GetEvents lists the events of Flux objects. Field selectors cannot match
+// one of several kinds, so the events are listed once per kind, at most
+// eventListConcurrency at a time, and then checked for a Flux API group, as
+// other APIs may define the same kinds. The events.k8s.io/v1 API is used
+// when the cluster serves it; its events are returned in the core form with
+// their series.
+func (c *Client) GetEvents(ctx context.Context, opts EventListOptions) ([]corev1.Event, error) {
+	kinds := opts.Kinds
+	if len(kinds) == 0 {
+		kinds = FluxKinds
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		events    []corev1.Event
+		errs      []error
+		semaphore = make(chan struct{}, eventListConcurrency)
+	)
+	for _, kind := range kinds {
+		wg.Add(1)
+		go func(kind string) {
+			defer wg.Done()
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				return
+			}
+			list, err := c.listEvents(ctx, opts.Namespace, kind)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				// The other kinds are of no use without this one
+				errs = append(errs, fmt.Errorf("failed to list %s events: %w", kind, err))
+				cancel()
+				return
+			}
+			events = append(events, list...)
+		}(kind)
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+
+	cutoff := time.Now().Add(-opts.Since)
+	fluxEvents := make([]corev1.Event, 0, len(events))
+	for _, event := range events {
+		if opts.Since > 0 && EventLastSeen(event).Before(cutoff) {
+			continue
+		}
+		if !isFluxAPIVersion(event.InvolvedObject.APIVersion) {
+			continue
+		}
+		fluxEvents = append(fluxEvents, event)
+	}
+
+	return fluxEvents, nil
+}
+
+// listEvents lists the events of one involved kind, from events.k8s.io/v1
+// unless the cluster is known not to serve it
+func (c *Client) listEvents(ctx context.Context, namespace, kind string) ([]corev1.Event, error) {
+	if !c.coreEvents.Load() {
+		list, err := c.EventsV1().Events(namespace).List(ctx, metav1.ListOptions{
+			FieldSelector: fields.OneTermEqualSelector("regarding.kind", kind).String(),
+		})
+		if err == nil {
+			events := make([]corev1.Event, len(list.Items))
+			for i, event := range list.Items {
+				events[i] = coreEvent(event)
+			}
+			return events, nil
+		}
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		// Clusters before Kubernetes 1.19 only serve core events
+		c.coreEvents.Store(true)
+	}
+
+	list, err := c.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("involvedObject.kind", kind).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// isFluxAPIVersion reports whether an API version belongs to a Flux API
+// group, such as source.toolkit.fluxcd.io/v1
+func isFluxAPIVersion(apiVersion string) bool {
+	group, _, _ := strings.Cut(apiVersion, "/")
+	return strings.HasSuffix(group, fluxGroupSuffix)
+}
+
+// coreEvent converts an events.k8s.io/v1 event to the core form. The
+// deprecated fields carry the counts and timestamps of core events.
+func coreEvent(event eventsv1.Event) corev1.Event {
+	core := corev1.Event{
+		ObjectMeta:          event.ObjectMeta,
+		InvolvedObject:      event.Regarding,
+		Reason:              event.Reason,
+		Message:             event.Note,
+		Type:                event.Type,
+		Action:              event.Action,
+		EventTime:           event.EventTime,
+		ReportingController: event.ReportingController,
+		ReportingInstance:   event.ReportingInstance,
+		Count:               event.DeprecatedCount,
+		FirstTimestamp:      event.DeprecatedFirstTimestamp,
+		LastTimestamp:       event.DeprecatedLastTimestamp,
+		Source: corev1.EventSource{
+			Component: event.DeprecatedSource.Component,
+			Host:      event.DeprecatedSource.Host,
+		},
+	}
+	if event.Related != nil {
+		related := *event.Related
+		core.Related = &related
+	}
+	if event.Series != nil {
+		core.Series = &corev1.EventSeries{
+			Count:            event.Series.Count,
+			LastObservedTime: event.Series.LastObservedTime,
+		}
+	}
+	return core
+}
+
+// EventFirstSeen returns when an event was first reported. Events of the
+// events.k8s.io API only set the event time.
+func EventFirstSeen(event corev1.Event) time.Time {
+	switch {
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
+
+// EventLastSeen returns when an event was last reported, which is later
+// than the first time for repeated events
+func EventLastSeen(event corev1.Event) time.Time {
+	switch {
+	case event.Series != nil && !event.Series.LastObservedTime.IsZero():
+		return event.Series.LastObservedTime.Time
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	default:
+		return EventFirstSeen(event)
+	}
+}
+
+// EventCount returns how often an event was reported
+func EventCount(event corev1.Event) int {
+	if event.Series != nil {
+		return int(event.Series.Count)
+	}
+	return int(event.Count)
+}
@@ -0,0 +1,177 @@
+package k8s
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// eventLists records the event list requests of a fake clientset
+type eventLists struct {
+	mu       sync.Mutex
+	count    int
+	inFlight int
+	peak     int
+}
+
+// withFieldSelectors makes the fake clientset serve events.k8s.io/v1 or not
+// and apply the field selectors of event lists, which the object tracker
+// ignores
+func withFieldSelectors(clientset *fake.Clientset, served bool) *eventLists {
+	lists := &eventLists{}
+	clientset.PrependReactor("list", "events", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		lists.mu.Lock()
+		lists.count++
+		lists.inFlight++
+		lists.peak = max(lists.peak, lists.inFlight)
+		lists.mu.Unlock()
+		defer func() {
+			lists.mu.Lock()
+			lists.inFlight--
+			lists.mu.Unlock()
+		}()
+		// Keep the requests open long enough to overlap
+		time.Sleep(5 * time.Millisecond)
+
+		list := action.(k8stesting.ListAction)
+		selector := list.GetListRestrictions().Fields
+		switch list.GetResource().Group {
+		case "events.k8s.io":
+			if !served {
+				return true, nil, apierrors.NewNotFound(schema.GroupResource{Group: "events.k8s.io", Resource: "events"}, "")
+			}
+			obj, err := clientset.Tracker().List(list.GetResource(), eventsv1.SchemeGroupVersion.WithKind("Event"), list.GetNamespace())
+			if err != nil {
+				return true, nil, err
+			}
+			events := obj.(*eventsv1.EventList)
+			events.Items = slices.DeleteFunc(events.Items, func(e eventsv1.Event) bool {
+				return !selector.Matches(fields.Set{"regarding.kind": e.Regarding.Kind})
+			})
+			return true, events, nil
+		default:
+			obj, err := clientset.Tracker().List(list.GetResource(), corev1.SchemeGroupVersion.WithKind("Event"), list.GetNamespace())
+			if err != nil {
+				return true, nil, err
+			}
+			events := obj.(*corev1.EventList)
+			events.Items = slices.DeleteFunc(events.Items, func(e corev1.Event) bool {
+				return !selector.Matches(fields.Set{"involvedObject.kind": e.InvolvedObject.Kind})
+			})
+			return true, events, nil
+		}
+	})
+	return lists
+}
+
+func eventNames(events []corev1.Event) []string {
+	names := make([]string, len(events))
+	for i, event := range events {
+		names[i] = event.Name
+	}
+	slices.Sort(names)
+	return names
+}
+
+func TestGetEventsV1(t *testing.T) {
+	now := time.Now()
+	event := func(name, kind, apiVersion string, lastSeen time.Time) *eventsv1.Event {
+		return &eventsv1.Event{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "flux-system"},
+			Regarding:  corev1.ObjectReference{Kind: kind, APIVersion: apiVersion, Name: "apps"},
+			Note:       "message of " + name,
+			Type:       corev1.EventTypeNormal,
+			EventTime:  metav1.NewMicroTime(lastSeen.Add(-time.Hour)),
+			Series:     &eventsv1.EventSeries{Count: 3, LastObservedTime: metav1.NewMicroTime(lastSeen)},
+		}
+	}
+
+	clientset := fake.NewSimpleClientset(
+		event("kustomization", "Kustomization", "kustomize.toolkit.fluxcd.io/v1", now),
+		event("image-policy", "ImagePolicy", "image.toolkit.fluxcd.io/v1beta2", now),
+		event("old", "Kustomization", "kustomize.toolkit.fluxcd.io/v1", now.Add(-2*time.Hour)),
+		event("other-helmrelease", "HelmRelease", "example.com/v1", now),
+		event("pod", "Pod", "v1", now),
+	)
+	lists := withFieldSelectors(clientset, true)
+	c := &Client{Interface: clientset}
+
+	events, err := c.GetEvents(context.Background(), EventListOptions{Since: time.Hour})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"image-policy", "kustomization"}, eventNames(events))
+	assert.False(t, c.coreEvents.Load())
+	assert.Equal(t, len(FluxKinds), lists.count, "each kind is listed with its own field selector")
+	assert.LessOrEqual(t, lists.peak, eventListConcurrency)
+
+	for _, e := range events {
+		assert.Equal(t, "message of "+e.Name, e.Message)
+		assert.Equal(t, 3, EventCount(e))
+		assert.WithinDuration(t, now, EventLastSeen(e), time.Millisecond)
+		assert.WithinDuration(t, now.Add(-time.Hour), EventFirstSeen(e), time.Millisecond)
+	}
+
+	// Without a lookback window old events are listed too
+	events, err = c.GetEvents(context.Background(), EventListOptions{Kinds: []string{"Kustomization"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"kustomization", "old"}, eventNames(events))
+}
+
+func TestGetEventsCoreFallback(t *testing.T) {
+	now := metav1.Now()
+	clientset := fake.NewSimpleClientset(
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "helmrelease", Namespace: "apps"},
+			InvolvedObject: corev1.ObjectReference{Kind: "HelmRelease", APIVersion: "helm.toolkit.fluxcd.io/v2", Name: "podinfo"},
+			FirstTimestamp: now,
+			LastTimestamp:  now,
+			Count:          2,
+		},
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "pod", Namespace: "apps"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", APIVersion: "v1", Name: "podinfo"},
+			LastTimestamp:  now,
+		},
+	)
+	withFieldSelectors(clientset, false)
+	c := &Client{Interface: clientset}
+
+	events, err := c.GetEvents(context.Background(), EventListOptions{Namespace: "apps", Since: time.Hour})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"helmrelease"}, eventNames(events))
+	assert.Equal(t, 2, EventCount(events[0]))
+	assert.True(t, c.coreEvents.Load())
+}
+
+func TestGetEventsError(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("list", "events", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Group: "events.k8s.io", Resource: "events"}, "", nil)
+	})
+	c := &Client{Interface: clientset}
+
+	_, err := c.GetEvents(context.Background(), EventListOptions{Kinds: []string{"Kustomization"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to list Kustomization events")
+	assert.False(t, c.coreEvents.Load())
+}
+
+func TestIsFluxAPIVersion(t *testing.T) {
+	assert.True(t, isFluxAPIVersion("source.toolkit.fluxcd.io/v1"))
+	assert.True(t, isFluxAPIVersion("notification.toolkit.fluxcd.io/v1beta3"))
+	assert.False(t, isFluxAPIVersion("v1"))
+	assert.False(t, isFluxAPIVersion("toolkit.fluxcd.io.example.com/v1"))
+}
@@ -0,0 +1,188 @@
+package k8s
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HealthState is the health of a Flux resource, computed from its conditions
+// the way kstatus does for Flux objects
+type HealthState string
+
+const (
+	// HealthReady means the last reconciliation of the current spec succeeded
+	HealthReady HealthState = "Ready"
+	// HealthProgressing means the controller has not yet observed the
+	// current spec or is still determining the result
+	HealthProgressing HealthState = "Progressing"
+	// HealthReconciling means a reconciliation is running
+	HealthReconciling HealthState = "Reconciling"
+	// HealthSuspended means reconciliation is suspended
+	HealthSuspended HealthState = "Suspended"
+	// HealthFailed means the last reconciliation failed and will be retried
+	HealthFailed HealthState = "Failed"
+	// HealthStalled means reconciliation cannot succeed without a change
+	HealthStalled HealthState = "Stalled"
+	// HealthUnknown means the resource reports no status
+	HealthUnknown HealthState = "Unknown"
+)
+
+// HealthStates lists every health state from the most to the least severe
+var HealthStates = []HealthState{
+	HealthStalled,
+	HealthFailed,
+	HealthUnknown,
+	HealthProgressing,
+	HealthReconciling,
+	HealthSuspended,
+	HealthReady,
+}
+
+// Severity orders health states for sorting, lower values are more severe
+func (h HealthState) Severity() int {
+	for i, state := range HealthStates {
+		if state == h {
+			return i
+		}
+	}
+	return len(HealthStates)
+}
+
+// Healthy reports whether the state needs no attention
+func (h HealthState) Healthy() bool {
+	return h == HealthReady || h == HealthSuspended
+}
+
+// Exit codes reported by commands that check the health of resources
+const (
+	ExitHealthy     = 0 // Every resource is ready or suspended
+	ExitUnhealthy   = 2 // A resource failed or is stalled
+	ExitProgressing = 3 // A resource is still progressing or reports no status
+)
+
+// ExitCode returns the command exit code for a resource in this state
+func (h HealthState) ExitCode() int {
+	switch h {
+	case HealthReady, HealthSuspended:
+		return ExitHealthy
+	case HealthFailed, HealthStalled:
+		return ExitUnhealthy
+	default:
+		return ExitProgressing
+	}
+}
+
+// ParseHealthState parses a health state name, ignoring case
+func ParseHealthState(name string) (HealthState, error) {
+	for _, state := range HealthStates {
+		if strings.EqualFold(string(state), name) {
+			return state, nil
+		}
+	}
+	names := make([]string, len(HealthStates))
+	for i, state := range HealthStates {
+		names[i] = strings.ToLower(string(state))
+	}
+	return "", fmt.Errorf("unknown health state %q, expected one of %s", name, strings.Join(names, ", "))
+}
+
+// ComputeHealth derives the health of a resource. The checks follow kstatus:
+// suspension first, then the Stalled condition, a generation the controller
+// has not observed yet, the Reconciling condition and finally the Ready
+// condition.
+func ComputeHealth(suspended bool, generation, observedGeneration int64, conditions []metav1.Condition) HealthState {
+	if suspended {
+		return HealthSuspended
+	}
+	if cond := metav1Condition(conditions, "Stalled"); cond != nil && cond.Status == metav1.ConditionTrue {
+		return HealthStalled
+	}
+	if observedGeneration < generation {
+		return HealthProgressing
+	}
+	if cond := metav1Condition(conditions, "Reconciling"); cond != nil && cond.Status == metav1.ConditionTrue {
+		return HealthReconciling
+	}
+
+	ready := metav1Condition(conditions, "Ready")
+	switch {
+	case ready == nil:
+		return HealthUnknown
+	case ready.Status == metav1.ConditionTrue:
+		return HealthReady
+	case ready.Status == metav1.ConditionFalse:
+		return HealthFailed
+	default:
+		return HealthProgressing
+	}
+}
+
+// metav1Condition returns the condition of the given type, or nil
+func metav1Condition(conditions []metav1.Condition, conditionType string) *metav1.Condition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+// setStatus fills the status fields of a resource from the generations and
+// conditions of its object. Ready, Status and Message come from the Ready
+// condition.
+func setStatus(resource *Resource, generation, observedGeneration int64, conditions []metav1.Condition) {
+	resource.Generation = generation
+	resource.ObservedGeneration = observedGeneration
+	for _, cond := range conditions {
+		resource.Conditions = append(resource.Conditions, Condition{
+			Type:               cond.Type,
+			Status:             string(cond.Status),
+			Reason:             cond.Reason,
+			Message:            cond.Message,
+			LastTransitionTime: cond.LastTransitionTime.Time,
+		})
+	}
+
+	if ready := metav1Condition(conditions, "Ready"); ready != nil {
+		resource.Ready = ready.Status == metav1.ConditionTrue
+		resource.Status = ready.Reason
+		resource.Message = ready.Message
+	}
+	resource.Health = ComputeHealth(resource.Suspended, generation, observedGeneration, conditions)
+}
+
+// SortByHealth sorts resources from the most to the least severe health
+// state, then by namespace and name
+func SortByHealth(resources []Resource) {
+	sort.SliceStable(resources, func(i, j int) bool {
+		a, b := resources[i], resources[j]
+		if a.Health.Severity() != b.Health.Severity() {
+			return a.Health.Severity() < b.Health.Severity()
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+}
+
+// FilterByHealth returns the resources in one of the given states, or all
+// resources when no state is given
+func FilterByHealth(resources []Resource, states ...HealthState) []Resource {
+	if len(states) == 0 {
+		return resources
+	}
+	filtered := make([]Resource, 0, len(resources))
+	for _, resource := range resources {
+		for _, state := range states {
+			if resource.Health == state {
+				filtered = append(filtered, resource)
+				break
+			}
+		}
+	}
+	return filtered
+}
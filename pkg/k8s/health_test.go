@@ -0,0 +1,82 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestComputeHealth(t *testing.T) {
+	condition := func(conditionType string, status metav1.ConditionStatus) metav1.Condition {
+		return metav1.Condition{Type: conditionType, Status: status}
+	}
+	ready := condition("Ready", metav1.ConditionTrue)
+
+	for name, tc := range map[string]struct {
+		suspended          bool
+		generation         int64
+		observedGeneration int64
+		conditions         []metav1.Condition
+		want               HealthState
+	}{
+		"ready":             {generation: 2, observedGeneration: 2, conditions: []metav1.Condition{ready}, want: HealthReady},
+		"failed":            {conditions: []metav1.Condition{condition("Ready", metav1.ConditionFalse)}, want: HealthFailed},
+		"ready unknown":     {conditions: []metav1.Condition{condition("Ready", metav1.ConditionUnknown)}, want: HealthProgressing},
+		"new generation":    {generation: 3, observedGeneration: 2, conditions: []metav1.Condition{ready}, want: HealthProgressing},
+		"never reconciled":  {generation: 1, want: HealthProgressing},
+		"reconciling":       {conditions: []metav1.Condition{ready, condition("Reconciling", metav1.ConditionTrue)}, want: HealthReconciling},
+		"reconciling false": {conditions: []metav1.Condition{ready, condition("Reconciling", metav1.ConditionFalse)}, want: HealthReady},
+		"stalled":           {generation: 2, observedGeneration: 1, conditions: []metav1.Condition{condition("Stalled", metav1.ConditionTrue), condition("Reconciling", metav1.ConditionTrue)}, want: HealthStalled},
+		"suspended":         {suspended: true, conditions: []metav1.Condition{condition("Stalled", metav1.ConditionTrue)}, want: HealthSuspended},
+		"no status":         {want: HealthUnknown},
+	} {
+		got := ComputeHealth(tc.suspended, tc.generation, tc.observedGeneration, tc.conditions)
+		assert.Equal(t, tc.want, got, name)
+	}
+}
+
+func TestSetStatusUsesReadyCondition(t *testing.T) {
+	// The Ready condition is not the last one, as with HelmRepositories
+	var resource Resource
+	setStatus(&resource, 1, 1, []metav1.Condition{
+		{Type: "Ready", Status: metav1.ConditionFalse, Reason: "FailedFetch", Message: "404 Not Found"},
+		{Type: "ArtifactInStorage", Status: metav1.ConditionTrue, Reason: "Succeeded"},
+	})
+
+	assert.False(t, resource.Ready)
+	assert.Equal(t, "FailedFetch", resource.Status)
+	assert.Equal(t, "404 Not Found", resource.Message)
+	assert.Equal(t, HealthFailed, resource.Health)
+	assert.Len(t, resource.Conditions, 2)
+}
+
+func TestHealthStates(t *testing.T) {
+	state, err := ParseHealthState("stalled")
+	require.NoError(t, err)
+	assert.Equal(t, HealthStalled, state)
+	_, err = ParseHealthState("broken")
+	assert.EqualError(t, err, `unknown health state "broken", expected one of stalled, failed, unknown, progressing, reconciling, suspended, ready`)
+
+	resources := []Resource{
+		{Name: "b", Health: HealthReady},
+		{Name: "c", Health: HealthFailed},
+		{Name: "a", Health: HealthReady},
+		{Name: "d", Health: HealthStalled},
+		{Name: "e", Health: HealthProgressing},
+	}
+	SortByHealth(resources)
+	names := make([]string, len(resources))
+	for i, resource := range resources {
+		names[i] = resource.Name
+	}
+	assert.Equal(t, []string{"d", "c", "e", "a", "b"}, names)
+
+	assert.Len(t, FilterByHealth(resources, HealthReady, HealthFailed), 3)
+	assert.Len(t, FilterByHealth(resources), 5)
+
+	assert.Equal(t, ExitHealthy, HealthSuspended.ExitCode())
+	assert.Equal(t, ExitUnhealthy, HealthStalled.ExitCode())
+	assert.Equal(t, ExitProgressing, HealthReconciling.ExitCode())
+}
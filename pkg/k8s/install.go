@@ -0,0 +1,280 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// versionLabel is the label Flux sets to its version on the objects it
+// installs
+const versionLabel = "app.kubernetes.io/version"
+
+// optionalControllers are the controllers Flux only installs on request
+var optionalControllers = []string{"image-reflector-controller", "image-automation-controller"}
+
+// controllerAPIs maps the controllers to the API groups of their CRDs
+var controllerAPIs = map[string]string{
+	"source-controller":           "source.toolkit.fluxcd.io",
+	"kustomize-controller":        "kustomize.toolkit.fluxcd.io",
+	"helm-controller":             "helm.toolkit.fluxcd.io",
+	"notification-controller":     "notification.toolkit.fluxcd.io",
+	"image-reflector-controller":  "image.toolkit.fluxcd.io",
+	"image-automation-controller": "image.toolkit.fluxcd.io",
+}
+
+// readAPIVersions are the API versions FluxCLI reads and writes
+var readAPIVersions = []string{
+	"source.toolkit.fluxcd.io/v1",
+	"kustomize.toolkit.fluxcd.io/v1",
+	"helm.toolkit.fluxcd.io/v2beta1",
+}
+
+// FluxInstall describes the Flux installation of a cluster
+type FluxInstall struct {
+	Namespace         string
+	Version           string // Flux version from the version labels, empty when unknown
+	KubernetesVersion string
+	Controllers       []ControllerStatus // Every known controller, installed or not
+	APIs              []FluxAPI          // Served Flux API groups
+}
+
+// ControllerStatus describes the deployment of a Flux controller
+type ControllerStatus struct {
+	Name          string
+	Installed     bool
+	Optional      bool // Only installed on request, such as the image controllers
+	Image         string
+	Version       string // Tag of the image
+	Replicas      int32
+	ReadyReplicas int32
+	Ready         bool
+	Message       string // Why the deployment is not ready
+}
+
+// FluxAPI is a served Flux API group with its versions, preferred first
+type FluxAPI struct {
+	Group    string
+	Versions []string
+}
+
+// Installed reports whether any Flux controller or API was found
+func (i *FluxInstall) Installed() bool {
+	if len(i.APIs) > 0 {
+		return true
+	}
+	for _, controller := range i.Controllers {
+		if controller.Installed {
+			return true
+		}
+	}
+	return false
+}
+
+// Problems lists what is wrong with the installation: required controllers
+// that are missing or not ready, and the API problems
+func (i *FluxInstall) Problems() []string {
+	if !i.Installed() {
+		return []string{fmt.Sprintf("Flux is not installed in namespace %s", i.Namespace)}
+	}
+
+	var problems []string
+	for _, controller := range i.Controllers {
+		switch {
+		case !controller.Installed && !controller.Optional:
+			problems = append(problems, fmt.Sprintf("%s is not installed", controller.Name))
+		case controller.Installed && !controller.Ready:
+			problems = append(problems, fmt.Sprintf("%s is not ready: %s", controller.Name, controller.Message))
+		}
+	}
+	return append(problems, i.APIProblems()...)
+}
+
+// APIProblems lists the APIs that are not served although their controller
+// runs or FluxCLI reads them
+func (i *FluxInstall) APIProblems() []string {
+	var problems []string
+	for _, controller := range i.Controllers {
+		if controller.Installed && !i.Serves(controllerAPIs[controller.Name]) {
+			problems = append(problems, fmt.Sprintf("%s runs but its API %s is not served", controller.Name, controllerAPIs[controller.Name]))
+		}
+	}
+	for _, apiVersion := range readAPIVersions {
+		group, _, _ := strings.Cut(apiVersion, "/")
+		if i.Serves(group) && !i.Serves(apiVersion) {
+			problems = append(problems, fmt.Sprintf("%s is not served, FluxCLI cannot read its objects (served: %s)",
+				apiVersion, strings.Join(i.api(group).Versions, ", ")))
+		}
+	}
+	return problems
+}
+
+// Healthy reports whether Flux is installed without problems
+func (i *FluxInstall) Healthy() bool {
+	return len(i.Problems()) == 0
+}
+
+// Serves reports whether an API group, or a group/version, is served
+func (i *FluxInstall) Serves(apiVersion string) bool {
+	group, version, _ := strings.Cut(apiVersion, "/")
+	api := i.api(group)
+	if api == nil {
+		return false
+	}
+	return version == "" || slices.Contains(api.Versions, version)
+}
+
+// api returns a served API group, nil when it is not served
+func (i *FluxInstall) api(group string) *FluxAPI {
+	for j := range i.APIs {
+		if i.APIs[j].Group == group {
+			return &i.APIs[j]
+		}
+	}
+	return nil
+}
+
+// Summary describes the installation in a few words, such as
+// "Flux v2.3.0" or "Flux v2.3.0, 1 problem"
+func (i *FluxInstall) Summary() string {
+	if !i.Installed() {
+		return "Flux not installed"
+	}
+	summary := "Flux"
+	if i.Version != "" {
+		summary += " " + i.Version
+	}
+	switch problems := len(i.Problems()); problems {
+	case 0:
+		return summary
+	case 1:
+		return summary + ", 1 problem"
+	default:
+		return fmt.Sprintf("%s, %d problems", summary, problems)
+	}
+}
+
+// DiscoverFlux inspects the Flux installation in a namespace, FluxNamespace
+// when empty: the controller deployments with their images and version
+// labels, and the served Flux API groups
+func (c *Client) DiscoverFlux(ctx context.Context, namespace string) (*FluxInstall, error) {
+	if namespace == "" {
+		namespace = FluxNamespace
+	}
+	install := &FluxInstall{Namespace: namespace}
+
+	version, err := c.Discovery().ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server version: %w", err)
+	}
+	install.KubernetesVersion = version.GitVersion
+
+	groups, err := c.Discovery().ServerGroups()
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover API groups: %w", err)
+	}
+	for _, group := range groups.Groups {
+		if !strings.HasSuffix(group.Name, fluxGroupSuffix) {
+			continue
+		}
+		api := FluxAPI{Group: group.Name}
+		if group.PreferredVersion.Version != "" {
+			api.Versions = append(api.Versions, group.PreferredVersion.Version)
+		}
+		for _, version := range group.Versions {
+			if !slices.Contains(api.Versions, version.Version) {
+				api.Versions = append(api.Versions, version.Version)
+			}
+		}
+		install.APIs = append(install.APIs, api)
+	}
+	slices.SortFunc(install.APIs, func(a, b FluxAPI) int { return strings.Compare(a.Group, b.Group) })
+
+	deployments, err := c.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments in %s: %w", namespace, err)
+	}
+	for _, name := range FluxControllers {
+		status := ControllerStatus{Name: name, Optional: slices.Contains(optionalControllers, name)}
+		if deployment := controllerDeployment(deployments.Items, name); deployment != nil {
+			status = deploymentStatus(status, deployment)
+			if install.Version == "" {
+				install.Version = deployment.Labels[versionLabel]
+			}
+		}
+		install.Controllers = append(install.Controllers, status)
+	}
+
+	if install.Version == "" {
+		// Installations that do not label the deployments label the namespace
+		if ns, err := c.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{}); err == nil {
+			install.Version = ns.Labels[versionLabel]
+		}
+	}
+
+	return install, nil
+}
+
+// controllerDeployment finds the deployment of a controller by its name or
+// by the labels Flux sets
+func controllerDeployment(deployments []appsv1.Deployment, controller string) *appsv1.Deployment {
+	for i, deployment := range deployments {
+		if deployment.Name == controller ||
+			deployment.Labels["app"] == controller ||
+			deployment.Labels["app.kubernetes.io/component"] == controller {
+			return &deployments[i]
+		}
+	}
+	return nil
+}
+
+// deploymentStatus fills a controller status from its deployment
+func deploymentStatus(status ControllerStatus, deployment *appsv1.Deployment) ControllerStatus {
+	status.Installed = true
+	// Flux names the controller container manager
+	for i, container := range deployment.Spec.Template.Spec.Containers {
+		if i == 0 || container.Name == "manager" {
+			status.Image = container.Image
+		}
+	}
+	status.Version = imageTag(status.Image)
+
+	status.Replicas = 1
+	if deployment.Spec.Replicas != nil {
+		status.Replicas = *deployment.Spec.Replicas
+	}
+	status.ReadyReplicas = deployment.Status.ReadyReplicas
+
+	switch {
+	case status.Replicas == 0:
+		status.Message = "scaled to zero"
+	case deployment.Status.ObservedGeneration < deployment.Generation:
+		status.Message = "rollout in progress"
+	case deployment.Status.UpdatedReplicas < status.Replicas:
+		status.Message = fmt.Sprintf("%d of %d replicas updated", deployment.Status.UpdatedReplicas, status.Replicas)
+	case deployment.Status.AvailableReplicas < status.Replicas:
+		status.Message = fmt.Sprintf("%d of %d replicas available", deployment.Status.AvailableReplicas, status.Replicas)
+		for _, condition := range deployment.Status.Conditions {
+			if condition.Type == appsv1.DeploymentAvailable && condition.Status != corev1.ConditionTrue && condition.Message != "" {
+				status.Message += ": " + condition.Message
+			}
+		}
+	default:
+		status.Ready = true
+	}
+	return status
+}
+
+// imageTag returns the tag of an image reference without its digest
+func imageTag(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[i+1:]
+	}
+	return ""
+}
@@ -0,0 +1,102 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func controllerDeploymentObject(name, image string, replicas, available int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: FluxNamespace,
+			Labels:    map[string]string{"app.kubernetes.io/component": name, versionLabel: "v2.3.0"},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "manager", Image: image}},
+			}},
+		},
+		Status: appsv1.DeploymentStatus{
+			Replicas:          replicas,
+			UpdatedReplicas:   replicas,
+			ReadyReplicas:     available,
+			AvailableReplicas: available,
+		},
+	}
+}
+
+func fluxAPIs(groupVersions ...string) []*metav1.APIResourceList {
+	lists := make([]*metav1.APIResourceList, len(groupVersions))
+	for i, groupVersion := range groupVersions {
+		lists[i] = &metav1.APIResourceList{GroupVersion: groupVersion}
+	}
+	return lists
+}
+
+func TestDiscoverFlux(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		controllerDeploymentObject("source-controller", "ghcr.io/fluxcd/source-controller:v1.3.0", 1, 1),
+		controllerDeploymentObject("kustomize-controller", "ghcr.io/fluxcd/kustomize-controller:v1.3.0@sha256:abc", 1, 1),
+		controllerDeploymentObject("helm-controller", "ghcr.io/fluxcd/helm-controller:v1.0.1", 1, 0),
+	)
+	clientset.Resources = fluxAPIs(
+		"v1",
+		"source.toolkit.fluxcd.io/v1",
+		"source.toolkit.fluxcd.io/v1beta2",
+		"kustomize.toolkit.fluxcd.io/v1",
+		"helm.toolkit.fluxcd.io/v2",
+	)
+	clientset.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.30.2"}
+	c := &Client{Interface: clientset}
+
+	install, err := c.DiscoverFlux(context.Background(), "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "v2.3.0", install.Version)
+	assert.Equal(t, "v1.30.2", install.KubernetesVersion)
+	assert.True(t, install.Installed())
+	assert.True(t, install.Serves("source.toolkit.fluxcd.io/v1beta2"))
+	assert.False(t, install.Serves("notification.toolkit.fluxcd.io"))
+
+	require.Len(t, install.Controllers, len(FluxControllers))
+	source := install.Controllers[0]
+	assert.Equal(t, "source-controller", source.Name)
+	assert.True(t, source.Ready)
+	assert.Equal(t, "v1.3.0", source.Version)
+	assert.Equal(t, "v1.3.0", install.Controllers[1].Version)
+
+	assert.Equal(t, []string{
+		"helm-controller is not ready: 0 of 1 replicas available",
+		"notification-controller is not installed",
+		"helm.toolkit.fluxcd.io/v2beta1 is not served, FluxCLI cannot read its objects (served: v2)",
+	}, install.Problems())
+	assert.Equal(t, "Flux v2.3.0, 3 problems", install.Summary())
+}
+
+func TestDiscoverFlux_NotInstalled(t *testing.T) {
+	c := &Client{Interface: fake.NewSimpleClientset()}
+
+	install, err := c.DiscoverFlux(context.Background(), "")
+	require.NoError(t, err)
+
+	assert.False(t, install.Installed())
+	assert.Equal(t, []string{"Flux is not installed in namespace flux-system"}, install.Problems())
+	assert.Equal(t, "Flux not installed", install.Summary())
+}
+
+func TestImageTag(t *testing.T) {
+	assert.Equal(t, "v1.3.0", imageTag("ghcr.io/fluxcd/source-controller:v1.3.0"))
+	assert.Equal(t, "v1.3.0", imageTag("registry:5000/fluxcd/source-controller:v1.3.0@sha256:abc"))
+	assert.Equal(t, "", imageTag("registry:5000/fluxcd/source-controller"))
+}
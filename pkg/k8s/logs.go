@@ -0,0 +1,276 @@
+package k8s
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FluxNamespace is the namespace Flux installs its controllers in
+const FluxNamespace = "flux-system"
+
+// FluxControllers are the controllers of a Flux installation
+var FluxControllers = []string{
+	"source-controller",
+	"kustomize-controller",
+	"helm-controller",
+	"notification-controller",
+	"image-reflector-controller",
+	"image-automation-controller",
+}
+
+// ErrNoControllerPods is returned when a controller has no running pods,
+// such as the optional image controllers when they are not installed
+var ErrNoControllerPods = errors.New("no running controller pods")
+
+// controllerKinds maps the controllers to the kinds they reconcile
+var controllerKinds = map[string][]string{
+	"source-controller":           {"GitRepository", "OCIRepository", "HelmRepository", "HelmChart", "Bucket"},
+	"kustomize-controller":        {"Kustomization"},
+	"helm-controller":             {"HelmRelease"},
+	"image-reflector-controller":  {"ImageRepository", "ImagePolicy"},
+	"image-automation-controller": {"ImageUpdateAutomation"},
+	"notification-controller":     {"Alert", "Provider", "Receiver"},
+}
+
+// ControllerForKind returns the Flux controller that reconciles a kind
+func ControllerForKind(kind string) (string, error) {
+	for controller, kinds := range controllerKinds {
+		for _, k := range kinds {
+			if strings.EqualFold(k, kind) {
+				return controller, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no Flux controller reconciles %s", kind)
+}
+
+// Log levels of the Flux controllers, from the least to the most severe
+const (
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelError = "error"
+)
+
+// LogEntry is a line of a controller log. Flux controllers log JSON objects
+// with the reconciled object in the name and namespace keys and in a key
+// named after its kind; other lines only have a message.
+type LogEntry struct {
+	Time       time.Time
+	Level      string
+	Message    string
+	Error      string
+	Controller string // Controller that logged the line
+	Pod        string
+	Kind       string // Kind of the reconciled object
+	Name       string
+	Namespace  string
+	Fields     map[string]string // Other keys of the line
+	Raw        string            // The line as logged
+}
+
+// logKeys are the keys of a JSON log line that have a LogEntry field
+var logKeys = []string{"ts", "level", "msg", "error", "controllerKind", "name", "namespace"}
+
+// ParseLogLine parses a line of a controller log. Lines that are not JSON
+// become the message of the entry.
+func ParseLogLine(line string) LogEntry {
+	var raw map[string]any
+	if err := json.Unmarshal([]byte(line), &raw); err != nil {
+		return LogEntry{Message: line, Raw: line}
+	}
+
+	text := func(key string) string {
+		if s, ok := raw[key].(string); ok {
+			return s
+		}
+		return ""
+	}
+	entry := LogEntry{
+		Level:     text("level"),
+		Message:   text("msg"),
+		Error:     text("error"),
+		Kind:      text("controllerKind"),
+		Name:      text("name"),
+		Namespace: text("namespace"),
+		Fields:    make(map[string]string),
+		Raw:       line,
+	}
+	switch ts := raw["ts"].(type) {
+	case string:
+		entry.Time, _ = time.Parse(time.RFC3339Nano, ts)
+	case float64:
+		// Epoch seconds, precise to about a microsecond
+		seconds, fraction := math.Modf(ts)
+		entry.Time = time.Unix(int64(seconds), int64(math.Round(fraction*1e6))*1e3)
+	}
+
+	// Older controllers only log the object under its kind
+	if object, ok := raw[entry.Kind].(map[string]any); ok && entry.Name == "" {
+		entry.Name, _ = object["name"].(string)
+		entry.Namespace, _ = object["namespace"].(string)
+	}
+
+	for key, value := range raw {
+		if contains(logKeys, key) || key == entry.Kind {
+			continue
+		}
+		if s, ok := value.(string); ok {
+			entry.Fields[key] = s
+		} else if b, err := json.Marshal(value); err == nil {
+			entry.Fields[key] = string(b)
+		}
+	}
+	return entry
+}
+
+// contains reports whether a list holds a string
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches reports whether the entry is about the given object. Entries
+// without a kind match any kind.
+func (e LogEntry) Matches(kind, name, namespace string) bool {
+	if e.Name != name || e.Namespace != namespace {
+		return false
+	}
+	return e.Kind == "" || strings.EqualFold(e.Kind, kind)
+}
+
+// LogLevelSeverity orders log levels, higher values are more severe. Levels
+// that are not known, such as those of lines that are not JSON, rank as
+// info.
+func LogLevelSeverity(level string) int {
+	switch strings.ToLower(level) {
+	case LogLevelDebug:
+		return 0
+	case LogLevelError:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// LogOptions selects the log lines streamed by StreamControllerLogs
+type LogOptions struct {
+	Namespace  string        // Namespace of the controller, FluxNamespace when empty
+	Controller string        // Such as source-controller
+	TailLines  int64         // Lines of each pod to start with, all when zero
+	Since      time.Duration // Only lines logged within this window, all when zero
+	Follow     bool          // Keep streaming new lines
+}
+
+// StreamControllerLogs streams the log lines of the pods of a controller.
+// The channel is closed when every pod stream ended or ctx is done; a pod
+// stream that fails ends with an entry of level error.
+func (c *Client) StreamControllerLogs(ctx context.Context, opts LogOptions) (<-chan LogEntry, error) {
+	namespace := opts.Namespace
+	if namespace == "" {
+		namespace = FluxNamespace
+	}
+
+	pods, err := c.controllerPods(ctx, namespace, opts.Controller)
+	if err != nil {
+		return nil, err
+	}
+
+	logOptions := &corev1.PodLogOptions{Follow: opts.Follow}
+	if opts.TailLines > 0 {
+		logOptions.TailLines = &opts.TailLines
+	}
+	if opts.Since > 0 {
+		seconds := int64(max(opts.Since.Seconds(), 1))
+		logOptions.SinceSeconds = &seconds
+	}
+
+	entries := make(chan LogEntry, 100)
+	var wg sync.WaitGroup
+	for _, pod := range pods {
+		wg.Add(1)
+		go func(pod string) {
+			defer wg.Done()
+			if err := c.streamPodLog(ctx, namespace, pod, logOptions, func(entry LogEntry) bool {
+				entry.Controller, entry.Pod = opts.Controller, pod
+				select {
+				case entries <- entry:
+					return true
+				case <-ctx.Done():
+					return false
+				}
+			}); err != nil && ctx.Err() == nil {
+				select {
+				case entries <- LogEntry{Time: time.Now(), Level: LogLevelError, Controller: opts.Controller, Pod: pod,
+					Message: fmt.Sprintf("log stream of pod %s ended: %v", pod, err)}:
+				case <-ctx.Done():
+				}
+			}
+		}(pod)
+	}
+	go func() {
+		wg.Wait()
+		close(entries)
+	}()
+
+	return entries, nil
+}
+
+// controllerPods returns the names of the running pods of a controller.
+// Flux labels them with app, newer installations also with
+// app.kubernetes.io/component.
+func (c *Client) controllerPods(ctx context.Context, namespace, controller string) ([]string, error) {
+	for _, label := range []string{"app", "app.kubernetes.io/component"} {
+		list, err := c.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: fmt.Sprintf("%s=%s", label, controller),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s pods: %w", controller, err)
+		}
+
+		var pods []string
+		for _, pod := range list.Items {
+			if pod.Status.Phase == corev1.PodRunning {
+				pods = append(pods, pod.Name)
+			}
+		}
+		if len(pods) > 0 {
+			sort.Strings(pods)
+			return pods, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s in namespace %s", ErrNoControllerPods, controller, namespace)
+}
+
+// streamPodLog reads the log of a pod line by line until it ends or emit
+// returns false
+func (c *Client) streamPodLog(ctx context.Context, namespace, pod string, opts *corev1.PodLogOptions, emit func(LogEntry) bool) error {
+	stream, err := c.CoreV1().Pods(namespace).GetLogs(pod, opts).Stream(ctx)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" && !emit(ParseLogLine(line)) {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,123 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestControllerForKind(t *testing.T) {
+	for kind, controller := range map[string]string{
+		"GitRepository":         "source-controller",
+		"helmrepository":        "source-controller",
+		"Kustomization":         "kustomize-controller",
+		"HelmRelease":           "helm-controller",
+		"ImagePolicy":           "image-reflector-controller",
+		"ImageUpdateAutomation": "image-automation-controller",
+		"Alert":                 "notification-controller",
+	} {
+		got, err := ControllerForKind(kind)
+		require.NoError(t, err, kind)
+		assert.Equal(t, controller, got, kind)
+	}
+
+	_, err := ControllerForKind("Deployment")
+	assert.Error(t, err)
+}
+
+func TestParseLogLine(t *testing.T) {
+	t.Run("object keys", func(t *testing.T) {
+		entry := ParseLogLine(`{"level":"error","ts":"2025-06-01T10:00:00.5Z","msg":"Reconciler error","controller":"gitrepository","controllerKind":"GitRepository","GitRepository":{"name":"podinfo","namespace":"apps"},"namespace":"apps","name":"podinfo","reconcileID":"abc","error":"authentication required"}`)
+
+		assert.Equal(t, LogLevelError, entry.Level)
+		assert.Equal(t, time.Date(2025, 6, 1, 10, 0, 0, 500000000, time.UTC), entry.Time.UTC())
+		assert.Equal(t, "Reconciler error", entry.Message)
+		assert.Equal(t, "authentication required", entry.Error)
+		assert.Equal(t, "GitRepository", entry.Kind)
+		assert.Equal(t, "podinfo", entry.Name)
+		assert.Equal(t, "apps", entry.Namespace)
+		assert.Equal(t, map[string]string{"controller": "gitrepository", "reconcileID": "abc"}, entry.Fields)
+		assert.Contains(t, entry.Raw, `"reconcileID":"abc"`)
+	})
+
+	t.Run("object under its kind", func(t *testing.T) {
+		entry := ParseLogLine(`{"level":"info","ts":1717236000.25,"msg":"stored artifact","controllerKind":"HelmChart","HelmChart":{"name":"podinfo","namespace":"flux-system"}}`)
+
+		assert.Equal(t, time.Unix(1717236000, 250000000).UTC(), entry.Time.UTC())
+		assert.Equal(t, "podinfo", entry.Name)
+		assert.Equal(t, "flux-system", entry.Namespace)
+		assert.Empty(t, entry.Fields)
+	})
+
+	t.Run("plain text", func(t *testing.T) {
+		entry := ParseLogLine("I0601 10:00:00 leaderelection.go:250] attempting to acquire leader lease")
+
+		assert.Equal(t, "I0601 10:00:00 leaderelection.go:250] attempting to acquire leader lease", entry.Message)
+		assert.Empty(t, entry.Level)
+		assert.Equal(t, LogLevelSeverity(LogLevelInfo), LogLevelSeverity(entry.Level))
+	})
+}
+
+func TestLogEntryMatches(t *testing.T) {
+	entry := LogEntry{Kind: "GitRepository", Name: "podinfo", Namespace: "apps"}
+	assert.True(t, entry.Matches("GitRepository", "podinfo", "apps"))
+	assert.False(t, entry.Matches("HelmChart", "podinfo", "apps"))
+	assert.False(t, entry.Matches("GitRepository", "podinfo", "default"))
+	assert.False(t, entry.Matches("GitRepository", "flux-system", "apps"))
+
+	// Lines without a kind match objects of every kind
+	entry.Kind = ""
+	assert.True(t, entry.Matches("HelmChart", "podinfo", "apps"))
+	assert.False(t, LogEntry{Message: "starting manager"}.Matches("GitRepository", "podinfo", "apps"))
+}
+
+func controllerPod(name string, labels map[string]string, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: FluxNamespace, Labels: labels},
+		Status:     corev1.PodStatus{Phase: phase},
+	}
+}
+
+func TestStreamControllerLogs(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		controllerPod("source-controller-abc", map[string]string{"app": "source-controller"}, corev1.PodRunning),
+		controllerPod("source-controller-old", map[string]string{"app": "source-controller"}, corev1.PodFailed),
+		controllerPod("helm-controller-xyz", map[string]string{"app.kubernetes.io/component": "helm-controller"}, corev1.PodRunning),
+	)
+	c := &Client{Interface: clientset}
+
+	t.Run("pods by label", func(t *testing.T) {
+		pods, err := c.controllerPods(context.Background(), FluxNamespace, "source-controller")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"source-controller-abc"}, pods)
+
+		pods, err = c.controllerPods(context.Background(), FluxNamespace, "helm-controller")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"helm-controller-xyz"}, pods)
+
+		_, err = c.controllerPods(context.Background(), FluxNamespace, "kustomize-controller")
+		assert.ErrorIs(t, err, ErrNoControllerPods)
+		assert.ErrorContains(t, err, "kustomize-controller in namespace flux-system")
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		lines, err := c.StreamControllerLogs(context.Background(), LogOptions{Controller: "source-controller", TailLines: 10})
+		require.NoError(t, err)
+
+		var entries []LogEntry
+		for entry := range lines {
+			entries = append(entries, entry)
+		}
+		// The fake clientset serves "fake logs" for every pod
+		require.Len(t, entries, 1)
+		assert.Equal(t, "fake logs", entries[0].Message)
+		assert.Equal(t, "source-controller", entries[0].Controller)
+		assert.Equal(t, "source-controller-abc", entries[0].Pod)
+	})
+}
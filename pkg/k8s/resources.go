@@ -10,8 +10,6 @@ import (
 	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
 	sourcev1 "github.com/fluxcd/source-controller/api/v1"
 	sourcev1beta2 "github.com/fluxcd/source-controller/api/v1beta2"
-	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -26,26 +24,128 @@ const (
 	ResourceTypeHelmRelease    ResourceType = "HelmRelease"
 )
 
+// ResourceTypes lists the supported resource types in display order
+var ResourceTypes = []ResourceType{
+	ResourceTypeGitRepository,
+	ResourceTypeHelmRepository,
+	ResourceTypeKustomization,
+	ResourceTypeHelmRelease,
+}
+
+// resourceTypeAliases are the short names accepted by ParseResourceType, as
+// used by the flux CLI
+var resourceTypeAliases = map[string]ResourceType{
+	"gitrepo":  ResourceTypeGitRepository,
+	"helmrepo": ResourceTypeHelmRepository,
+	"ks":       ResourceTypeKustomization,
+	"hr":       ResourceTypeHelmRelease,
+}
+
+// ParseResourceType parses a resource type by kind, plural or short name,
+// ignoring case
+func ParseResourceType(name string) (ResourceType, error) {
+	lower := strings.ToLower(name)
+	if resourceType, ok := resourceTypeAliases[lower]; ok {
+		return resourceType, nil
+	}
+	for _, resourceType := range ResourceTypes {
+		kind := strings.ToLower(string(resourceType))
+		if lower == kind || lower == kind+"s" || (strings.HasSuffix(kind, "y") && lower == strings.TrimSuffix(kind, "y")+"ies") {
+			return resourceType, nil
+		}
+	}
+	return "", fmt.Errorf("unknown resource type %q", name)
+}
+
 // Resource represents a generic FluxCD resource
 type Resource struct {
 	Type        ResourceType  `json:"type"`
 	Name        string        `json:"name"`
 	Namespace   string        `json:"namespace"`
+	Cluster     string        `json:"cluster,omitempty"`
 	Ready       bool          `json:"ready"`
+	Health      HealthState   `json:"health"`
 	Status      string        `json:"status"`
 	Message     string        `json:"message"`
 	Age         time.Duration `json:"age"`
 	LastUpdate  time.Time     `json:"last_update"`
 	Conditions  []Condition   `json:"conditions"`
 	Suspended   bool          `json:"suspended"`
+	SuspendReason string      `json:"suspend_reason,omitempty"`
+	SuspendedBy string        `json:"suspended_by,omitempty"`
+	Interval    time.Duration `json:"interval,omitempty"`
+	Generation  int64         `json:"generation"`
+	ObservedGeneration int64  `json:"observed_generation"`
 	Source      string        `json:"source,omitempty"`
 	Path        string        `json:"path,omitempty"`
 	Revision    string        `json:"revision,omitempty"`
+	LastAttemptedRevision string `json:"last_attempted_revision,omitempty"`
+	LastReconcileAt time.Time `json:"last_reconcile_at,omitempty"`
 	URL         string        `json:"url,omitempty"`
 	Chart       string        `json:"chart,omitempty"`
 	Version     string        `json:"version,omitempty"`
 }
 
+// SuspendReasonAnnotation records why a resource was suspended. It is set
+// when suspending with a reason, and removed when suspending without one
+// and on resume.
+const SuspendReasonAnnotation = "fluxcli.io/suspend-reason"
+
+// SuspendedByAnnotation and SuspendedAtAnnotation record who suspended a
+// resource and when, if audit.annotate is set. They are removed when
+// suspending without audit.annotate and on resume.
+const (
+	SuspendedByAnnotation = "fluxcli.io/suspended-by"
+	SuspendedAtAnnotation = "fluxcli.io/suspended-at"
+)
+
+// LastTransition returns when the Ready condition last changed, or the
+// latest transition of any condition when there is no Ready condition
+func (r Resource) LastTransition() time.Time {
+	var latest time.Time
+	for _, cond := range r.Conditions {
+		if cond.Type == "Ready" {
+			return cond.LastTransitionTime
+		}
+		if cond.LastTransitionTime.After(latest) {
+			latest = cond.LastTransitionTime
+		}
+	}
+	return latest
+}
+
+// LastReconcile returns when the resource was last reconciled: the time of
+// the last handled reconcile request or the latest transition of any
+// condition, whichever is later. Controllers only update conditions when a
+// reconciliation changes the status.
+func (r Resource) LastReconcile() time.Time {
+	latest := r.LastReconcileAt
+	for _, cond := range r.Conditions {
+		if cond.LastTransitionTime.After(latest) {
+			latest = cond.LastTransitionTime
+		}
+	}
+	return latest
+}
+
+// RevisionBehind reports whether the last attempted revision differs from
+// the applied one, as when a Kustomization is stuck on an old commit
+func (r Resource) RevisionBehind() bool {
+	return r.LastAttemptedRevision != "" && r.LastAttemptedRevision != r.Revision
+}
+
+// parseReconcileRequest parses the lastHandledReconcileAt status field. It
+// holds the value of the reconcile.fluxcd.io/requestedAt annotation, which
+// the Flux CLI and fluxcli set to the request time; other values give the
+// zero time.
+func parseReconcileRequest(value string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
 // Condition represents a status condition
 type Condition struct {
 	Type               string    `json:"type"`
@@ -66,6 +166,22 @@ func (c *Client) safeList(ctx context.Context, list client.ObjectList, opts ...c
 	return c.List(ctx, list, opts...)
 }
 
+// ListResources lists the resources of the given type
+func (c *Client) ListResources(ctx context.Context, resourceType ResourceType, namespace string) ([]Resource, error) {
+	switch resourceType {
+	case ResourceTypeGitRepository:
+		return c.ListGitRepositories(ctx, namespace)
+	case ResourceTypeHelmRepository:
+		return c.ListHelmRepositories(ctx, namespace)
+	case ResourceTypeKustomization:
+		return c.ListKustomizations(ctx, namespace)
+	case ResourceTypeHelmRelease:
+		return c.ListHelmReleases(ctx, namespace)
+	default:
+		return nil, fmt.Errorf("unsupported resource type: %s", resourceType)
+	}
+}
+
 // ListGitRepositories lists all GitRepository resources
 func (c *Client) ListGitRepositories(ctx context.Context, namespace string) ([]Resource, error) {
 	var gitRepos sourcev1.GitRepositoryList
@@ -106,31 +222,18 @@ func (c *Client) ListGitRepositories(ctx context.Context, namespace string) ([]R
 			Age:        time.Since(repo.CreationTimestamp.Time),
 			LastUpdate: time.Now(),
 			Suspended:  repo.Spec.Suspend,
+			SuspendReason: repo.Annotations[SuspendReasonAnnotation],
+			SuspendedBy: repo.Annotations[SuspendedByAnnotation],
+			Interval:   repo.Spec.Interval.Duration,
 			URL:        repo.Spec.URL,
 		}
 
-		// Parse status
-		if repo.Status.Conditions != nil {
-			for _, cond := range repo.Status.Conditions {
-				resource.Conditions = append(resource.Conditions, Condition{
-					Type:               cond.Type,
-					Status:             string(cond.Status),
-					Reason:             cond.Reason,
-					Message:            cond.Message,
-					LastTransitionTime: cond.LastTransitionTime.Time,
-				})
-
-				if cond.Type == "Ready" {
-					resource.Ready = cond.Status == metav1.ConditionTrue
-					resource.Status = cond.Reason
-					resource.Message = cond.Message
-				}
-			}
-		}
+		setStatus(&resource, repo.Generation, repo.Status.ObservedGeneration, repo.Status.Conditions)
 
 		if repo.Status.Artifact != nil {
 			resource.Revision = repo.Status.Artifact.Revision
 		}
+		resource.LastReconcileAt = parseReconcileRequest(repo.Status.LastHandledReconcileAt)
 
 		resources = append(resources, resource)
 	}
@@ -212,28 +315,18 @@ func (c *Client) ListHelmRepositories(ctx context.Context, namespace string) ([]
 					Age:        time.Since(repo.CreationTimestamp.Time),
 					LastUpdate: time.Now(),
 					Suspended:  repo.Spec.Suspend,
+					SuspendReason: repo.Annotations[SuspendReasonAnnotation],
+					SuspendedBy: repo.Annotations[SuspendedByAnnotation],
+					Interval:   repo.Spec.Interval.Duration,
 					URL:        repo.Spec.URL,
 				}
 
-				// Parse status (v1 format)
-				if repo.Status.Conditions != nil {
-					for _, cond := range repo.Status.Conditions {
-						resource.Conditions = append(resource.Conditions, Condition{
-							Type:               cond.Type,
-							Status:             string(cond.Status),
-							Reason:             cond.Reason,
-							Message:            cond.Message,
-							LastTransitionTime: cond.LastTransitionTime.Time,
-						})
-					}
-				}
+				setStatus(&resource, repo.Generation, repo.Status.ObservedGeneration, repo.Status.Conditions)
 
-				if len(repo.Status.Conditions) > 0 {
-					lastCond := repo.Status.Conditions[len(repo.Status.Conditions)-1]
-					resource.Status = string(lastCond.Status)
-					resource.Message = lastCond.Message
-					resource.Ready = lastCond.Status == metav1.ConditionTrue
+				if repo.Status.Artifact != nil {
+					resource.Revision = repo.Status.Artifact.Revision
 				}
+				resource.LastReconcileAt = parseReconcileRequest(repo.Status.LastHandledReconcileAt)
 
 				resources = append(resources, resource)
 			}
@@ -254,28 +347,18 @@ func (c *Client) ListHelmRepositories(ctx context.Context, namespace string) ([]
 			Age:        time.Since(repo.CreationTimestamp.Time),
 			LastUpdate: time.Now(),
 			Suspended:  repo.Spec.Suspend,
+			SuspendReason: repo.Annotations[SuspendReasonAnnotation],
+			SuspendedBy: repo.Annotations[SuspendedByAnnotation],
+			Interval:   repo.Spec.Interval.Duration,
 			URL:        repo.Spec.URL,
 		}
 
-		// Parse status (v1beta2 format)
-		if repo.Status.Conditions != nil {
-			for _, cond := range repo.Status.Conditions {
-				resource.Conditions = append(resource.Conditions, Condition{
-					Type:               cond.Type,
-					Status:             string(cond.Status),
-					Reason:             cond.Reason,
-					Message:            cond.Message,
-					LastTransitionTime: cond.LastTransitionTime.Time,
-				})
-			}
-		}
+		setStatus(&resource, repo.Generation, repo.Status.ObservedGeneration, repo.Status.Conditions)
 
-		if len(repo.Status.Conditions) > 0 {
-			lastCond := repo.Status.Conditions[len(repo.Status.Conditions)-1]
-			resource.Status = string(lastCond.Status)
-			resource.Message = lastCond.Message
-			resource.Ready = lastCond.Status == metav1.ConditionTrue
+		if repo.Status.Artifact != nil {
+			resource.Revision = repo.Status.Artifact.Revision
 		}
+		resource.LastReconcileAt = parseReconcileRequest(repo.Status.LastHandledReconcileAt)
 
 		resources = append(resources, resource)
 	}
@@ -323,6 +406,9 @@ func (c *Client) ListKustomizations(ctx context.Context, namespace string) ([]Re
 			Age:        time.Since(ks.CreationTimestamp.Time),
 			LastUpdate: time.Now(),
 			Suspended:  ks.Spec.Suspend,
+			SuspendReason: ks.Annotations[SuspendReasonAnnotation],
+			SuspendedBy: ks.Annotations[SuspendedByAnnotation],
+			Interval:   ks.Spec.Interval.Duration,
 			Path:       ks.Spec.Path,
 		}
 
@@ -330,28 +416,13 @@ func (c *Client) ListKustomizations(ctx context.Context, namespace string) ([]Re
 			resource.Source = ks.Spec.SourceRef.Name
 		}
 
-		// Parse status
-		if ks.Status.Conditions != nil {
-			for _, cond := range ks.Status.Conditions {
-				resource.Conditions = append(resource.Conditions, Condition{
-					Type:               cond.Type,
-					Status:             string(cond.Status),
-					Reason:             cond.Reason,
-					Message:            cond.Message,
-					LastTransitionTime: cond.LastTransitionTime.Time,
-				})
-
-				if cond.Type == "Ready" {
-					resource.Ready = cond.Status == metav1.ConditionTrue
-					resource.Status = cond.Reason
-					resource.Message = cond.Message
-				}
-			}
-		}
+		setStatus(&resource, ks.Generation, ks.Status.ObservedGeneration, ks.Status.Conditions)
 
 		if ks.Status.LastAppliedRevision != "" {
 			resource.Revision = ks.Status.LastAppliedRevision
 		}
+		resource.LastAttemptedRevision = ks.Status.LastAttemptedRevision
+		resource.LastReconcileAt = parseReconcileRequest(ks.Status.LastHandledReconcileAt)
 
 		resources = append(resources, resource)
 	}
@@ -399,6 +470,9 @@ func (c *Client) ListHelmReleases(ctx context.Context, namespace string) ([]Reso
 			Age:        time.Since(hr.CreationTimestamp.Time),
 			LastUpdate: time.Now(),
 			Suspended:  hr.Spec.Suspend,
+			SuspendReason: hr.Annotations[SuspendReasonAnnotation],
+			SuspendedBy: hr.Annotations[SuspendedByAnnotation],
+			Interval:   hr.Spec.Interval.Duration,
 			Chart:      hr.Spec.Chart.Spec.Chart,
 			Version:    hr.Spec.Chart.Spec.Version,
 		}
@@ -407,28 +481,13 @@ func (c *Client) ListHelmReleases(ctx context.Context, namespace string) ([]Reso
 			resource.Source = hr.Spec.Chart.Spec.SourceRef.Name
 		}
 
-		// Parse status
-		if hr.Status.Conditions != nil {
-			for _, cond := range hr.Status.Conditions {
-				resource.Conditions = append(resource.Conditions, Condition{
-					Type:               cond.Type,
-					Status:             string(cond.Status),
-					Reason:             cond.Reason,
-					Message:            cond.Message,
-					LastTransitionTime: cond.LastTransitionTime.Time,
-				})
-
-				if cond.Type == "Ready" {
-					resource.Ready = cond.Status == metav1.ConditionTrue
-					resource.Status = cond.Reason
-					resource.Message = cond.Message
-				}
-			}
-		}
+		setStatus(&resource, hr.Generation, hr.Status.ObservedGeneration, hr.Status.Conditions)
 
 		if hr.Status.LastAppliedRevision != "" {
 			resource.Revision = hr.Status.LastAppliedRevision
 		}
+		resource.LastAttemptedRevision = hr.Status.LastAttemptedRevision
+		resource.LastReconcileAt = parseReconcileRequest(hr.Status.LastHandledReconcileAt)
 
 		resources = append(resources, resource)
 	}
@@ -436,18 +495,20 @@ func (c *Client) ListHelmReleases(ctx context.Context, namespace string) ([]Reso
 	return resources, nil
 }
 
-// SuspendResource suspends a FluxCD resource
-func (c *Client) SuspendResource(ctx context.Context, resourceType ResourceType, name, namespace string) error {
-	return c.updateSuspendStatus(ctx, resourceType, name, namespace, true)
+// SuspendResource suspends a FluxCD resource. A non-empty reason is recorded
+// in the SuspendReasonAnnotation and a non-empty by in the
+// SuspendedByAnnotation, along with the time in the SuspendedAtAnnotation.
+func (c *Client) SuspendResource(ctx context.Context, resourceType ResourceType, name, namespace, reason, by string) error {
+	return c.updateSuspendStatus(ctx, resourceType, name, namespace, true, reason, by)
 }
 
 // ResumeResource resumes a FluxCD resource
 func (c *Client) ResumeResource(ctx context.Context, resourceType ResourceType, name, namespace string) error {
-	return c.updateSuspendStatus(ctx, resourceType, name, namespace, false)
+	return c.updateSuspendStatus(ctx, resourceType, name, namespace, false, "", "")
 }
 
 // updateSuspendStatus updates the suspend status of a resource
-func (c *Client) updateSuspendStatus(ctx context.Context, resourceType ResourceType, name, namespace string, suspend bool) error {
+func (c *Client) updateSuspendStatus(ctx context.Context, resourceType ResourceType, name, namespace string, suspend bool, reason, by string) error {
 	var obj client.Object
 
 	switch resourceType {
@@ -484,6 +545,26 @@ func (c *Client) updateSuspendStatus(ctx context.Context, resourceType ResourceT
 		hr.Spec.Suspend = suspend
 	}
 
+	// Record why and by whom the resource was suspended, and forget it on resume
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	if suspend && reason != "" {
+		annotations[SuspendReasonAnnotation] = reason
+	} else {
+		// A suspension without a reason must not show the previous one
+		delete(annotations, SuspendReasonAnnotation)
+	}
+	if suspend && by != "" {
+		annotations[SuspendedByAnnotation] = by
+		annotations[SuspendedAtAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	} else {
+		delete(annotations, SuspendedByAnnotation)
+		delete(annotations, SuspendedAtAnnotation)
+	}
+	obj.SetAnnotations(annotations)
+
 	if err := c.Update(ctx, obj); err != nil {
 		return fmt.Errorf("failed to update %s/%s: %w", resourceType, name, err)
 	}
@@ -518,7 +599,7 @@ func (c *Client) ReconcileResource(ctx context.Context, resourceType ResourceTyp
 	if annotations == nil {
 		annotations = make(map[string]string)
 	}
-	annotations["reconcile.fluxcd.io/requestedAt"] = time.Now().UTC().Format(time.RFC3339)
+	annotations["reconcile.fluxcd.io/requestedAt"] = time.Now().UTC().Format(time.RFC3339Nano)
 	obj.SetAnnotations(annotations)
 
 	if err := c.Update(ctx, obj); err != nil {
@@ -527,37 +608,3 @@ func (c *Client) ReconcileResource(ctx context.Context, resourceType ResourceTyp
 
 	return nil
 }
-
-// GetEvents returns Kubernetes events related to FluxCD resources
-func (c *Client) GetEvents(ctx context.Context, namespace string) ([]corev1.Event, error) {
-	// Get all events first, then filter in-memory since Kubernetes field selectors
-	// don't support OR conditions for the same field or complex time comparisons
-	oneHourAgo := time.Now().Add(-1 * time.Hour)
-	eventList, err := c.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
-		// Remove all field selectors to avoid API errors - do filtering in-memory instead
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to list events: %w", err)
-	}
-
-	// Filter events to only include FluxCD-related resources from the last hour
-	fluxEvents := make([]corev1.Event, 0)
-	for _, event := range eventList.Items {
-		// Time-based filtering - only include events from the last hour
-		if event.FirstTimestamp.Time.Before(oneHourAgo) && event.LastTimestamp.Time.Before(oneHourAgo) {
-			continue
-		}
-
-		apiVersion := event.InvolvedObject.APIVersion
-		// Check if event is related to FluxCD resources
-		if apiVersion == "source.toolkit.fluxcd.io/v1" ||
-			apiVersion == "source.toolkit.fluxcd.io/v1beta2" ||
-			apiVersion == "kustomize.toolkit.fluxcd.io/v1" ||
-			apiVersion == "helm.toolkit.fluxcd.io/v2beta1" ||
-			apiVersion == "helm.toolkit.fluxcd.io/v2" {
-			fluxEvents = append(fluxEvents, event)
-		}
-	}
-
-	return fluxEvents, nil
-}
@@ -0,0 +1,108 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestParseResourceType(t *testing.T) {
+	for name, want := range map[string]ResourceType{
+		"GitRepository":    ResourceTypeGitRepository,
+		"gitrepositories":  ResourceTypeGitRepository,
+		"gitrepo":          ResourceTypeGitRepository,
+		"helmrepositories": ResourceTypeHelmRepository,
+		"ks":               ResourceTypeKustomization,
+		"Kustomizations":   ResourceTypeKustomization,
+		"hr":               ResourceTypeHelmRelease,
+		"helmrelease":      ResourceTypeHelmRelease,
+	} {
+		got, err := ParseResourceType(name)
+		assert.NoError(t, err, name)
+		assert.Equal(t, want, got, name)
+	}
+
+	_, err := ParseResourceType("ocirepository")
+	assert.EqualError(t, err, `unknown resource type "ocirepository"`)
+}
+
+func TestLastReconcile(t *testing.T) {
+	requested := parseReconcileRequest("2026-10-18T10:00:00.123456789Z")
+	assert.Equal(t, time.Date(2026, 10, 18, 10, 0, 0, 123456789, time.UTC), requested)
+	assert.True(t, parseReconcileRequest("not a time").IsZero())
+	assert.True(t, parseReconcileRequest("").IsZero())
+
+	transition := requested.Add(-time.Hour)
+	resource := Resource{Conditions: []Condition{{Type: "Ready", LastTransitionTime: transition}}}
+	assert.Equal(t, transition, resource.LastReconcile())
+
+	resource.LastReconcileAt = requested
+	assert.Equal(t, requested, resource.LastReconcile())
+}
+
+func TestRevisionBehind(t *testing.T) {
+	assert.False(t, Resource{Revision: "main@sha1:abc"}.RevisionBehind())
+	assert.False(t, Resource{Revision: "main@sha1:abc", LastAttemptedRevision: "main@sha1:abc"}.RevisionBehind())
+	assert.True(t, Resource{Revision: "main@sha1:abc", LastAttemptedRevision: "main@sha1:def"}.RevisionBehind())
+	assert.True(t, Resource{LastAttemptedRevision: "main@sha1:def"}.RevisionBehind())
+}
+
+func TestSuspendReason(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, kustomizev1.AddToScheme(scheme))
+	c := &Client{Client: ctrlfake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&kustomizev1.Kustomization{ObjectMeta: metav1.ObjectMeta{Name: "apps", Namespace: "flux-system"}},
+	).Build()}
+	ctx := context.Background()
+	reason := func() (string, bool) {
+		var ks kustomizev1.Kustomization
+		require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "apps", Namespace: "flux-system"}, &ks))
+		value, ok := ks.Annotations[SuspendReasonAnnotation]
+		return value, ok
+	}
+
+	require.NoError(t, c.SuspendResource(ctx, ResourceTypeKustomization, "apps", "flux-system", "incident 42", ""))
+	got, _ := reason()
+	assert.Equal(t, "incident 42", got)
+
+	// Suspending again without a reason drops the previous one
+	require.NoError(t, c.SuspendResource(ctx, ResourceTypeKustomization, "apps", "flux-system", "", ""))
+	_, ok := reason()
+	assert.False(t, ok)
+
+	require.NoError(t, c.SuspendResource(ctx, ResourceTypeKustomization, "apps", "flux-system", "maintenance", ""))
+	require.NoError(t, c.ResumeResource(ctx, ResourceTypeKustomization, "apps", "flux-system"))
+	_, ok = reason()
+	assert.False(t, ok)
+}
+
+func TestSuspendedBy(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, kustomizev1.AddToScheme(scheme))
+	c := &Client{Client: ctrlfake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&kustomizev1.Kustomization{ObjectMeta: metav1.ObjectMeta{Name: "apps", Namespace: "flux-system"}},
+	).Build()}
+	ctx := context.Background()
+	annotations := func() map[string]string {
+		var ks kustomizev1.Kustomization
+		require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "apps", Namespace: "flux-system"}, &ks))
+		return ks.Annotations
+	}
+
+	require.NoError(t, c.SuspendResource(ctx, ResourceTypeKustomization, "apps", "flux-system", "", "alice"))
+	assert.Equal(t, "alice", annotations()[SuspendedByAnnotation])
+	assert.NotEmpty(t, annotations()[SuspendedAtAnnotation])
+
+	// A suspension that is not annotated does not keep the previous suspender
+	require.NoError(t, c.SuspendResource(ctx, ResourceTypeKustomization, "apps", "flux-system", "", ""))
+	assert.NotContains(t, annotations(), SuspendedByAnnotation)
+	assert.NotContains(t, annotations(), SuspendedAtAnnotation)
+}
@@ -1,12 +1,16 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 	"github.com/malagant/fluxcli/internal/config"
 	"github.com/malagant/fluxcli/pkg/core"
 	"github.com/malagant/fluxcli/pkg/k8s"
@@ -18,10 +22,31 @@ type AppModel struct {
 	manager         *core.Manager
 	state           AppState
 	currentView     ViewType
+	focus           Pane // Focused pane of the resources view
+	zoomed          bool // The focused pane fills the window
+	eventRows       int  // Rows of the events pane, resized at runtime
+	eventsLinked    bool // The events pane follows the selected resource
 	resourceView    *ResourceView
 	eventView       *EventView
+	logView         *LogView
+	diagnosticsView *DiagnosticsView
+	clusterView     *ClusterView
+	clusterChosen   bool
+	keys            KeyMap
+	theme           Theme
+	watcher         *config.Watcher
+	configError     string
 	commandMode     bool
 	commandInput    string
+	searchMode      bool
+	searchInput     string
+	searchPrevious  string // Search restored when the input is cancelled
+	confirmMode     bool   // Confirming an operation with y or the cluster name
+	confirmInput    string
+	pending         pendingOperation // Operation waiting for the confirmation
+	logStream       int    // Number of the current log stream
+	stopLogStream   context.CancelFunc
+	diagnosticsRun  int // Number of the latest diagnosis
 	statusMessage   string
 	errorMessage    string
 	width           int
@@ -43,18 +68,25 @@ type AppState struct {
 type ViewType int
 
 const (
-	ViewResources ViewType = iota
-	ViewEvents
+	ViewResources ViewType = iota // Resource and events panes
 	ViewDetails
+	ViewClusters
+	ViewLogs        // Controller log of a resource
+	ViewDiagnostics // Diagnosis of the current cluster
 )
 
 // Event represents a Kubernetes event for display
 type Event struct {
+	UID       string
 	Type      string
 	Reason    string
-	Object    string
+	Kind      string // Kind of the involved object
+	Name      string // Name of the involved object
+	Namespace string
+	Object    string // Kind/name of the involved object
 	Message   string
-	Timestamp string
+	FirstSeen time.Time
+	LastSeen  time.Time
 	Count     int
 }
 
@@ -66,16 +98,27 @@ func NewApp(cfg *config.Config) *AppModel {
 		config:      cfg,
 		manager:     manager,
 		currentView: ViewResources,
+		eventRows:   cfg.UI.PaneEventsHeight,
+		eventsLinked: true,
+		keys:        NewKeyMap(cfg),
+		theme:       NewTheme(cfg),
 		state: AppState{
 			Resources:       make(map[string]map[k8s.ResourceType][]k8s.Resource),
 			Events:          make(map[string][]Event),
-			CurrentCluster:  cfg.CurrentContext,
+			CurrentCluster:  cfg.CurrentCluster,
 			CurrentResource: k8s.ResourceTypeGitRepository,
 		},
 	}
 
 	app.resourceView = NewResourceView(cfg)
 	app.eventView = NewEventView(cfg)
+	app.logView = NewLogView(cfg)
+	app.diagnosticsView = NewDiagnosticsView(cfg)
+	app.clusterView = NewClusterView(cfg)
+	app.resourceView.SetPermission(func(resource k8s.Resource) k8s.Permission {
+		return manager.OperationPermission(resource.Cluster, resource.Type, resource.Namespace)
+	})
+	app.focusPane(PaneResources)
 
 	return app
 }
@@ -87,6 +130,14 @@ func (m *AppModel) Run() error {
 	}
 	defer m.manager.Stop()
 
+	// Apply edits to the config file without a restart
+	if watcher, err := config.NewWatcher(m.config); err != nil {
+		m.configError = fmt.Sprintf("config reload disabled: %v", err)
+	} else {
+		m.watcher = watcher
+		defer watcher.Close()
+	}
+
 	program := tea.NewProgram(m, tea.WithAltScreen())
 	
 	// Start background update handlers
@@ -102,12 +153,14 @@ func (m *AppModel) Init() tea.Cmd {
 		tea.EnterAltScreen,
 		m.resourceView.Init(),
 		m.eventView.Init(),
+		m.logView.Init(),
+		m.diagnosticsView.Init(),
+		m.clusterView.Init(),
 	)
 }
 
 // Update handles messages and updates the model
 func (m *AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	var cmd tea.Cmd
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
@@ -115,16 +168,24 @@ func (m *AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 		m.ready = true
-		
-		// Update child views
-		m.resourceView.SetSize(m.width, m.height-4) // Reserve space for header/footer
-		m.eventView.SetSize(m.width, m.height/3)
+		m.layout()
 		
 	case tea.KeyMsg:
-		if m.commandMode {
-			return m.handleCommandMode(msg)
+		var model tea.Model
+		var cmd tea.Cmd
+		switch {
+		case m.commandMode:
+			model, cmd = m.handleCommandMode(msg)
+		case m.searchMode:
+			model, cmd = m.handleSearchMode(msg)
+		case m.confirmMode:
+			model, cmd = m.handleConfirmMode(msg)
+		default:
+			model, cmd = m.handleNormalMode(msg)
 		}
-		return m.handleNormalMode(msg)
+		// The selection or resource type may have changed
+		m.syncEvents()
+		return model, cmd
 		
 	case ResourceUpdateMsg:
 		m.handleResourceUpdate(msg)
@@ -132,25 +193,63 @@ func (m *AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case EventUpdateMsg:
 		m.handleEventUpdate(msg)
 		
+	case LogLinesMsg:
+		return m, m.handleLogLines(msg)
+		
+	case DiagnosisMsg:
+		m.handleDiagnosis(msg)
+		
 	case ErrorUpdateMsg:
 		m.errorMessage = msg.Error
 		
+	case ConnectionUpdateMsg:
+		m.clusterView.SetConnections(m.manager.GetConnectionStates(), m.state.CurrentCluster)
+		if cmd := m.handleConnectionUpdate(msg); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+		
+	case ConfigReloadMsg:
+		return m, m.handleConfigReload(msg)
+		
+	case ConfigAppliedMsg:
+		if msg.Err != nil {
+			m.errorMessage = msg.Err.Error()
+		}
+		m.clusterView.SetConnections(m.manager.GetConnectionStates(), m.state.CurrentCluster)
+		
+	case SwitchClusterMsg:
+		m.clusterChosen = true
+		return m, m.switchCluster(msg.Cluster)
+		
 	case ClearStatusMsg:
 		m.statusMessage = ""
 		m.errorMessage = ""
 	}
 
 	// Update current view
-	switch m.currentView {
-	case ViewResources:
-		m.resourceView, cmd = m.resourceView.Update(msg)
-		cmds = append(cmds, cmd)
-	case ViewEvents:
+	cmds = append(cmds, m.updateCurrentView(msg))
+
+	return m, tea.Batch(cmds...)
+}
+
+// updateCurrentView forwards a message to the active view
+func (m *AppModel) updateCurrentView(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+
+	switch {
+	case m.currentView == ViewResources && m.focus == PaneEvents:
 		m.eventView, cmd = m.eventView.Update(msg)
-		cmds = append(cmds, cmd)
+	case m.currentView == ViewResources:
+		m.resourceView, cmd = m.resourceView.Update(msg)
+	case m.currentView == ViewClusters:
+		m.clusterView, cmd = m.clusterView.Update(msg)
+	case m.currentView == ViewLogs:
+		m.logView, cmd = m.logView.Update(msg)
+	case m.currentView == ViewDiagnostics:
+		m.diagnosticsView, cmd = m.diagnosticsView.Update(msg)
 	}
 
-	return m, tea.Batch(cmds...)
+	return cmd
 }
 
 // View renders the application
@@ -168,9 +267,13 @@ func (m *AppModel) View() string {
 	// Main content
 	switch m.currentView {
 	case ViewResources:
-		view.WriteString(m.resourceView.View())
-	case ViewEvents:
-		view.WriteString(m.eventView.View())
+		view.WriteString(m.renderPanes())
+	case ViewClusters:
+		view.WriteString(m.clusterView.View())
+	case ViewLogs:
+		view.WriteString(m.logView.View())
+	case ViewDiagnostics:
+		view.WriteString(m.diagnosticsView.View())
 	}
 	
 	// Footer
@@ -184,51 +287,89 @@ func (m *AppModel) View() string {
 func (m *AppModel) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 	
-	switch msg.String() {
-	case "ctrl+c", "q":
+	switch {
+	case key.Matches(msg, m.keys.Quit):
 		return m, tea.Quit
 		
-	case ":":
+	case key.Matches(msg, m.keys.Command):
 		m.commandMode = true
 		m.commandInput = ""
 		return m, nil
 		
-	case "?":
+	case key.Matches(msg, m.keys.Help):
 		m.state.ShowHelp = !m.state.ShowHelp
 		return m, nil
 		
-	case "/":
-		// TODO: Implement search/filter mode
-		m.statusMessage = "Search mode not yet implemented"
-		return m, tea.Tick(2000, func(time.Time) tea.Msg { return ClearStatusMsg{} })
-		
-	case "tab":
-		// Switch between views
-		switch m.currentView {
-		case ViewResources:
-			m.currentView = ViewEvents
-		case ViewEvents:
-			m.currentView = ViewResources
+	case key.Matches(msg, m.keys.Filter):
+		if m.currentView != ViewLogs {
+			m.statusMessage = fmt.Sprintf("Search is available in the log view, open it with %s", firstKey(m.keys.Logs))
+			return m, tea.Tick(2*time.Second, func(time.Time) tea.Msg { return ClearStatusMsg{} })
+		}
+		m.searchMode = true
+		m.searchPrevious = m.logView.Search()
+		m.searchInput = m.searchPrevious
+		return m, nil
+		
+	case key.Matches(msg, m.keys.Back) && m.currentView == ViewLogs && !m.logView.ShowingDetail(),
+		key.Matches(msg, m.keys.Back) && m.currentView == ViewDiagnostics:
+		m.focusPane(m.focus)
+		return m, nil
+		
+	case key.Matches(msg, m.keys.Diagnostics):
+		return m, m.openDiagnostics()
+		
+	case key.Matches(msg, m.keys.Refresh) && m.currentView == ViewDiagnostics:
+		return m, m.runDiagnostics()
+		
+	case key.Matches(msg, m.keys.Logs) && m.currentView == ViewResources:
+		// Show the log of the selected resource, or of the selected event's object
+		if m.focus == PaneEvents {
+			if selected := m.eventView.GetSelectedEvent(); selected != nil {
+				return m, m.openLogs(selected.Kind, selected.Name, selected.Namespace)
+			}
+		} else if selected := m.resourceView.GetSelectedResource(); selected != nil {
+			return m, m.openLogs(string(selected.Type), selected.Name, selected.Namespace)
+		}
+		return m, nil
+		
+	case key.Matches(msg, m.keys.SwitchView):
+		// Switch between the panes, or back to them from the cluster list
+		switch {
+		case m.currentView != ViewResources:
+			m.focusPane(m.focus)
+		case m.focus == PaneResources:
+			m.focusPane(PaneEvents)
+		default:
+			m.focusPane(PaneResources)
 		}
 		return m, nil
 		
-	case "1":
+	case key.Matches(msg, m.keys.Zoom) && m.currentView == ViewResources:
+		m.toggleZoom()
+		
+	case key.Matches(msg, m.keys.GrowEvents) && m.currentView == ViewResources:
+		m.resizeEvents(1)
+		
+	case key.Matches(msg, m.keys.ShrinkEvents) && m.currentView == ViewResources:
+		m.resizeEvents(-1)
+		
+	case key.Matches(msg, m.keys.GitRepositories):
 		m.state.CurrentResource = k8s.ResourceTypeGitRepository
 		m.resourceView.SetResourceType(k8s.ResourceTypeGitRepository)
 		
-	case "2":
+	case key.Matches(msg, m.keys.HelmRepositories):
 		m.state.CurrentResource = k8s.ResourceTypeHelmRepository
 		m.resourceView.SetResourceType(k8s.ResourceTypeHelmRepository)
 		
-	case "3":
+	case key.Matches(msg, m.keys.Kustomizations):
 		m.state.CurrentResource = k8s.ResourceTypeKustomization
 		m.resourceView.SetResourceType(k8s.ResourceTypeKustomization)
 		
-	case "4":
+	case key.Matches(msg, m.keys.HelmReleases):
 		m.state.CurrentResource = k8s.ResourceTypeHelmRelease
 		m.resourceView.SetResourceType(k8s.ResourceTypeHelmRelease)
 		
-	case "ctrl+k":
+	case key.Matches(msg, m.keys.PreviousCluster):
 		// Previous cluster
 		clusters := m.manager.GetClusters()
 		if len(clusters) > 1 {
@@ -236,6 +377,7 @@ func (m *AppModel) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			for i, cluster := range clusters {
 				if cluster == current {
 					prev := (i - 1 + len(clusters)) % len(clusters)
+					m.clusterChosen = true
 					m.state.CurrentCluster = clusters[prev]
 					m.manager.SetCurrentCluster(clusters[prev])
 					break
@@ -243,7 +385,7 @@ func (m *AppModel) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 		
-	case "ctrl+j":
+	case key.Matches(msg, m.keys.NextCluster):
 		// Next cluster
 		clusters := m.manager.GetClusters()
 		if len(clusters) > 1 {
@@ -251,6 +393,7 @@ func (m *AppModel) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			for i, cluster := range clusters {
 				if cluster == current {
 					next := (i + 1) % len(clusters)
+					m.clusterChosen = true
 					m.state.CurrentCluster = clusters[next]
 					m.manager.SetCurrentCluster(clusters[next])
 					break
@@ -258,10 +401,56 @@ func (m *AppModel) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 		
-	case "r":
+	case key.Matches(msg, m.keys.Refresh):
 		// Manual refresh
+		m.manager.Refresh()
 		m.statusMessage = "Refreshing resources..."
-		cmds = append(cmds, tea.Tick(2000, func(time.Time) tea.Msg { return ClearStatusMsg{} }))
+		cmds = append(cmds, tea.Tick(2*time.Second, func(time.Time) tea.Msg { return ClearStatusMsg{} }))
+		
+	case key.Matches(msg, m.keys.ObjectEvents) && m.currentView == ViewResources && m.focus == PaneResources:
+		// Link the events pane to the selected resource again and focus it
+		m.eventsLinked = true
+		m.syncEvents()
+		m.focusPane(PaneEvents)
+		
+	case key.Matches(msg, m.keys.ObjectEvents) && m.currentView == ViewResources:
+		// The events pane toggles its object filter, which unlinks it
+		m.eventsLinked = false
+		cmds = append(cmds, m.updateCurrentView(msg))
+		
+	case key.Matches(msg, m.keys.Reconcile, m.keys.Suspend, m.keys.Resume):
+		// Operations apply to the resource selected in the resource view
+		if m.currentView != ViewResources || m.focus != PaneResources {
+			return m, nil
+		}
+		selected := m.resourceView.GetSelectedResource()
+		if selected == nil {
+			return m, nil
+		}
+		if m.resourceView.OperationPermission(*selected) == k8s.PermissionDenied {
+			// Explain instead of failing with the API's error
+			operation := "resume"
+			switch {
+			case key.Matches(msg, m.keys.Reconcile):
+				operation = "reconcile"
+			case key.Matches(msg, m.keys.Suspend):
+				operation = "suspend"
+			}
+			m.errorMessage = m.operationDenied(operation, *selected)
+			return m, tea.Tick(3*time.Second, func(time.Time) tea.Msg { return ClearStatusMsg{} })
+		}
+		switch {
+		case key.Matches(msg, m.keys.Reconcile):
+			cmds = append(cmds, m.keyOperation("reconcile", selected.Name))
+		case key.Matches(msg, m.keys.Suspend):
+			cmds = append(cmds, m.keyOperation("suspend", selected.Name))
+		default:
+			cmds = append(cmds, m.keyOperation("resume", selected.Name))
+		}
+		
+	default:
+		// Navigation keys are handled by the active view
+		cmds = append(cmds, m.updateCurrentView(msg))
 	}
 
 	return m, tea.Batch(cmds...)
@@ -311,70 +500,225 @@ func (m *AppModel) executeCommand(command string) tea.Cmd {
 		
 	case "suspend", "s":
 		if len(args) > 0 {
-			resourceName := args[0]
-			if err := m.manager.SuspendResource(m.state.CurrentResource, resourceName); err != nil {
-				m.errorMessage = fmt.Sprintf("Failed to suspend %s: %v", resourceName, err)
-			} else {
-				m.statusMessage = fmt.Sprintf("Suspended %s", resourceName)
-			}
+			return m.resourceOperation("suspend", args[0], strings.Join(args[1:], " "))
 		}
 		
 	case "resume", "r":
 		if len(args) > 0 {
-			resourceName := args[0]
-			if err := m.manager.ResumeResource(m.state.CurrentResource, resourceName); err != nil {
-				m.errorMessage = fmt.Sprintf("Failed to resume %s: %v", resourceName, err)
-			} else {
-				m.statusMessage = fmt.Sprintf("Resumed %s", resourceName)
-			}
+			return m.resourceOperation("resume", args[0], "")
+		}
+		
+	case "clusters":
+		m.stopLogs()
+		m.clusterView.SetConnections(m.manager.GetConnectionStates(), m.state.CurrentCluster)
+		m.currentView = ViewClusters
+		return nil
+		
+	case "cluster":
+		if len(args) > 0 {
+			m.clusterChosen = true
+			return m.switchCluster(args[0])
 		}
 		
 	case "reconcile", "rec":
 		if len(args) > 0 {
-			resourceName := args[0]
-			if err := m.manager.ReconcileResource(m.state.CurrentResource, resourceName); err != nil {
-				m.errorMessage = fmt.Sprintf("Failed to reconcile %s: %v", resourceName, err)
-			} else {
-				m.statusMessage = fmt.Sprintf("Triggered reconciliation for %s", resourceName)
-			}
+			return m.resourceOperation("reconcile", args[0], "")
+		}
+		
+	case "sort":
+		if len(args) == 0 || !slices.Contains(sortColumns, args[0]) {
+			m.errorMessage = fmt.Sprintf("Usage: sort %s [asc|desc]", strings.Join(sortColumns, "|"))
+			break
+		}
+		desc := len(args) > 1 && args[1] == "desc"
+		m.resourceView.SetSort(args[0], desc)
+		
+	case "wide":
+		m.resourceView.SetWide(!m.resourceView.Wide())
+		
+	case "columns", "cols":
+		return m.setColumns(args)
+		
+	case "events", "ev":
+		filter, err := ParseEventFilter(args)
+		if err != nil {
+			m.errorMessage = err.Error()
+			break
+		}
+		m.eventsLinked = false
+		m.eventView.SetFilter(filter)
+		m.focusPane(PaneEvents)
+		if filter.IsZero() {
+			m.statusMessage = "Showing all events"
+		} else {
+			m.statusMessage = fmt.Sprintf("Showing events with %s", filter)
+		}
+		
+	case "logs", "log":
+		return m.logsCommand(args)
+		
+	case "doctor", "diagnostics", "diag":
+		return m.openDiagnostics()
+		
+	case "follow":
+		if m.currentView == ViewLogs {
+			m.logView.SetFollow(!m.logView.Following())
+			break
+		}
+		m.eventView.SetFollow(!m.eventView.Following())
+		m.focusPane(PaneEvents)
+		if m.eventView.Following() {
+			m.statusMessage = "Following new events"
+		} else {
+			m.statusMessage = "Stopped following events"
+		}
+		
+	case "health":
+		states, err := parseHealthStates(args)
+		if err != nil {
+			m.errorMessage = err.Error()
+			break
+		}
+		m.resourceView.SetHealthFilter(states...)
+		if len(states) == 0 {
+			m.statusMessage = "Showing resources in every health state"
+		} else {
+			m.statusMessage = fmt.Sprintf("Showing %s resources", joinHealth(states))
 		}
 		
 	default:
 		m.errorMessage = fmt.Sprintf("Unknown command: %s", cmd)
 	}
 	
-	return tea.Tick(3000, func(time.Time) tea.Msg { return ClearStatusMsg{} })
+	return tea.Tick(3*time.Second, func(time.Time) tea.Msg { return ClearStatusMsg{} })
+}
+
+// setColumns sets the columns of the current resource kind and saves them
+// to the configuration file; without arguments the defaults are restored
+func (m *AppModel) setColumns(args []string) tea.Cmd {
+	columns, err := parseColumns(args)
+	if err != nil {
+		m.errorMessage = err.Error()
+		return tea.Tick(3*time.Second, func(time.Time) tea.Msg { return ClearStatusMsg{} })
+	}
+
+	kind := string(m.resourceView.ResourceType())
+	m.config.SetResourceColumns(kind, columns)
+	m.resourceView.SetConfig(m.config)
+
+	if err := m.config.Save(); err != nil {
+		m.errorMessage = fmt.Sprintf("Columns changed but not saved: %v", err)
+	} else if len(columns) == 0 {
+		m.statusMessage = fmt.Sprintf("Restored the default %s columns", kind)
+	} else {
+		m.statusMessage = fmt.Sprintf("Saved the %s columns", kind)
+	}
+	return tea.Tick(3*time.Second, func(time.Time) tea.Msg { return ClearStatusMsg{} })
+}
+
+// parseHealthStates parses health states given as separate or comma
+// separated arguments
+func parseHealthStates(args []string) ([]k8s.HealthState, error) {
+	var states []k8s.HealthState
+	for _, arg := range args {
+		for _, name := range strings.Split(arg, ",") {
+			if name == "" {
+				continue
+			}
+			state, err := k8s.ParseHealthState(name)
+			if err != nil {
+				return nil, err
+			}
+			states = append(states, state)
+		}
+	}
+	return states, nil
+}
+
+// resourceOperation suspends, resumes or reconciles a resource of the
+// current type and reports the outcome in the footer. reason is recorded
+// when suspending.
+func (m *AppModel) resourceOperation(operation, name, reason string) tea.Cmd {
+	if m.manager.IsReadOnly(m.state.CurrentCluster) {
+		m.errorMessage = (&core.ReadOnlyError{Cluster: m.state.CurrentCluster, Operation: operation}).Error()
+		return tea.Tick(3*time.Second, func(time.Time) tea.Msg { return ClearStatusMsg{} })
+	}
+	if resource := m.findResource(name); resource != nil {
+		if denied := m.operationDenied(operation, *resource); denied != "" {
+			m.errorMessage = denied
+			return tea.Tick(3*time.Second, func(time.Time) tea.Msg { return ClearStatusMsg{} })
+		}
+	}
+	if m.manager.IsProtected(m.state.CurrentCluster) {
+		m.confirmMode = true
+		m.confirmInput = ""
+		m.pending = pendingOperation{operation: operation, name: name, reason: reason}
+		return nil
+	}
+
+	return m.runOperation(operation, name, reason)
+}
+
+// runOperation performs an operation on the named resource of the current
+// type and reports the outcome
+func (m *AppModel) runOperation(operation, name, reason string) tea.Cmd {
+	var err error
+	var done string
+	switch operation {
+	case "suspend":
+		err = m.manager.SuspendResource(m.state.CurrentResource, name, reason)
+		done = fmt.Sprintf("Suspended %s", name)
+	case "resume":
+		err = m.manager.ResumeResource(m.state.CurrentResource, name)
+		done = fmt.Sprintf("Resumed %s", name)
+	case "reconcile":
+		err = m.manager.ReconcileResource(m.state.CurrentResource, name)
+		done = fmt.Sprintf("Triggered reconciliation for %s", name)
+	}
+
+	if err != nil {
+		m.errorMessage = fmt.Sprintf("Failed to %s %s: %v", operation, name, err)
+	} else {
+		m.statusMessage = done
+	}
+	return tea.Tick(3*time.Second, func(time.Time) tea.Msg { return ClearStatusMsg{} })
 }
 
 // renderHeader renders the application header
 func (m *AppModel) renderHeader() string {
-	title := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("205")).
-		Render("FluxCLI")
-		
-	cluster := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("86")).
-		Render(fmt.Sprintf("Cluster: %s", m.state.CurrentCluster))
+	title := m.theme.Title.Render("FluxCLI")
+	
+	clusterConfig, _ := m.config.GetCluster(m.state.CurrentCluster)
+	cluster := fmt.Sprintf("%s %s",
+		m.theme.ClusterStyle(clusterConfig).Render(fmt.Sprintf("Cluster: %s", m.state.CurrentCluster)),
+		m.connectionStatus())
+	if flux := m.fluxStatus(); flux != "" {
+		cluster = fmt.Sprintf("%s %s", cluster, flux)
+	}
+	if mode := m.clusterMode(); mode != "" {
+		cluster = fmt.Sprintf("%s %s", cluster, mode)
+	}
 		
-	resource := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("81")).
-		Render(fmt.Sprintf("Resource: %s", m.state.CurrentResource))
+	resource := m.theme.Resource.Render(fmt.Sprintf("Resource: %s", m.state.CurrentResource))
 		
-	namespace := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("226")).
-		Render(fmt.Sprintf("Namespace: %s", m.manager.GetCurrentNamespace()))
+	namespace := m.theme.Namespace.Render(fmt.Sprintf("Namespace: %s", m.manager.GetCurrentNamespace()))
+	
+	if unavailable := m.renderUnavailableSummary(); unavailable != "" {
+		namespace = fmt.Sprintf("%s | %s", namespace, unavailable)
+	}
 	
 	if m.commandMode {
-		commandPrompt := lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("196")).
-			Render(fmt.Sprintf(":%s", m.commandInput))
+		commandPrompt := m.theme.Prompt.Render(fmt.Sprintf(":%s", m.commandInput))
 		return fmt.Sprintf("%s | %s | %s | %s | %s", title, cluster, resource, namespace, commandPrompt)
 	}
+	if m.confirmMode {
+		confirmPrompt := m.theme.Prompt.Render(m.confirmPrompt())
+		return fmt.Sprintf("%s | %s | %s | %s | %s", title, cluster, resource, namespace, confirmPrompt)
+	}
+	if m.searchMode {
+		searchPrompt := m.theme.Prompt.Render(fmt.Sprintf("/%s", m.searchInput))
+		return fmt.Sprintf("%s | %s | %s | %s | %s", title, cluster, resource, namespace, searchPrompt)
+	}
 	
 	return fmt.Sprintf("%s | %s | %s | %s", title, cluster, resource, namespace)
 }
@@ -384,22 +728,21 @@ func (m *AppModel) renderFooter() string {
 	var footer strings.Builder
 	
 	if m.errorMessage != "" {
-		error := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("196")).
-			Render(fmt.Sprintf("Error: %s", m.errorMessage))
+		error := m.theme.Error.Render(fmt.Sprintf("Error: %s", m.errorMessage))
 		footer.WriteString(error)
 	} else if m.statusMessage != "" {
-		status := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("86")).
-			Render(m.statusMessage)
+		status := m.theme.Success.Render(m.statusMessage)
 		footer.WriteString(status)
+	} else if m.configError != "" {
+		configError := m.theme.Warning.Render(fmt.Sprintf("Config: %s", m.configError))
+		footer.WriteString(configError)
 	} else if m.state.ShowHelp {
 		help := m.renderHelp()
 		footer.WriteString(help)
+	} else if readOnly := m.readOnlyHint(); readOnly != "" {
+		footer.WriteString(m.theme.Warning.Render(readOnly))
 	} else {
-		shortcuts := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("240")).
-			Render("? help | ↑↓←→/jk navigation | 1-4 resource types | tab switch views | : command mode | ctrl+k/j clusters | q quit")
+		shortcuts := m.theme.Hint.Render(m.keys.ShortHelp())
 		footer.WriteString(shortcuts)
 	}
 	
@@ -408,41 +751,7 @@ func (m *AppModel) renderFooter() string {
 
 // renderHelp renders the help text
 func (m *AppModel) renderHelp() string {
-	helpText := `
-Navigation:
-  j/k or ↓/↑       Navigate up/down
-  ←/→              Horizontal navigation (in tables)
-  ctrl+d/u         Page down/up (half screen)
-  PageUp/PageDown  Page navigation
-  Home/End         Go to first/last item
-  g/G              Go to top/bottom
-  H/M/L            Top/Middle/Bottom of view
-  enter/space      View details
-  tab              Switch between views
-  
-Resource Types:
-  1                GitRepositories
-  2                HelmRepositories  
-  3                Kustomizations
-  4                HelmReleases
-  
-Clusters:
-  ctrl+k/j         Previous/Next cluster
-  
-Commands (: to enter command mode):
-  suspend <n>      Suspend resource
-  resume <n>       Resume resource
-  reconcile <n>    Trigger reconciliation
-  
-Other:
-  /                Search/Filter (coming soon)
-  r                Manual refresh
-  ?                Toggle this help
-  q                Quit
-`
-	return lipgloss.NewStyle().
-		Foreground(lipgloss.Color("244")).
-		Render(strings.TrimSpace(helpText))
+	return m.theme.Muted.Render(m.keys.FullHelp())
 }
 
 // Message types for updates
@@ -461,6 +770,22 @@ type ErrorUpdateMsg struct {
 	Error string
 }
 
+type ConnectionUpdateMsg struct {
+	Cluster string
+	State   core.ConnectionState
+}
+
+// ConfigReloadMsg carries the result of reloading the changed config file
+type ConfigReloadMsg struct {
+	Config *config.Config
+	Err    error
+}
+
+// ConfigAppliedMsg reports that the manager switched to a reloaded config
+type ConfigAppliedMsg struct {
+	Err error
+}
+
 type ClearStatusMsg struct{}
 
 // handleUpdates handles background updates from the manager
@@ -477,24 +802,29 @@ func (m *AppModel) handleUpdates(program *tea.Program) {
 		case update := <-m.manager.GetEventUpdates():
 			events := make([]Event, len(update.Events))
 			for i, event := range update.Events {
-				events[i] = Event{
-					Type:      event.Type,
-					Reason:    event.Reason,
-					Object:    fmt.Sprintf("%s/%s", event.InvolvedObject.Kind, event.InvolvedObject.Name),
-					Message:   event.Message,
-					Timestamp: event.FirstTimestamp.Format("15:04:05"),
-					Count:     int(event.Count),
-				}
+				events[i] = NewEvent(event)
 			}
 			program.Send(EventUpdateMsg{
 				Cluster: update.Cluster,
 				Events:  events,
 			})
 			
+		case update := <-m.manager.GetConnectionUpdates():
+			program.Send(ConnectionUpdateMsg{
+				Cluster: update.Cluster,
+				State:   update.State,
+			})
+			
 		case update := <-m.manager.GetErrorUpdates():
 			program.Send(ErrorUpdateMsg{
 				Error: fmt.Sprintf("[%s] %v", update.Cluster, update.Error),
 			})
+			
+		case reload := <-m.configReloads():
+			program.Send(ConfigReloadMsg{
+				Config: reload.Config,
+				Err:    reload.Err,
+			})
 		}
 	}
 }
@@ -509,6 +839,7 @@ func (m *AppModel) handleResourceUpdate(msg ResourceUpdateMsg) {
 	// Update resource view if it matches current view
 	if msg.Cluster == m.state.CurrentCluster && msg.Type == m.state.CurrentResource {
 		m.resourceView.SetResources(msg.Resources)
+		m.syncEvents()
 	}
 }
 
@@ -521,3 +852,202 @@ func (m *AppModel) handleEventUpdate(msg EventUpdateMsg) {
 		m.eventView.SetEvents(msg.Events)
 	}
 }
+
+// switchCluster makes the given cluster the current one
+func (m *AppModel) switchCluster(cluster string) tea.Cmd {
+	if err := m.manager.SetCurrentCluster(cluster); err != nil {
+		m.errorMessage = fmt.Sprintf("Failed to switch cluster: %v", err)
+	} else {
+		m.stopLogs()
+		m.state.CurrentCluster = cluster
+		m.currentView = ViewResources
+		m.resourceView.SetResources(m.state.Resources[cluster][m.state.CurrentResource])
+		m.eventView.SetEvents(m.state.Events[cluster])
+		m.syncEvents()
+		m.statusMessage = fmt.Sprintf("Switched to cluster %s", cluster)
+	}
+	return tea.Tick(3*time.Second, func(time.Time) tea.Msg { return ClearStatusMsg{} })
+}
+
+// connectionStatus returns the connection indicator of the current cluster
+func (m *AppModel) connectionStatus() string {
+	conn, ok := m.manager.GetConnectionState(m.state.CurrentCluster)
+	if !ok {
+		conn.State = core.ConnectionConnecting
+	}
+	style := m.theme.ConnectionStyle(conn.State)
+	if conn.State == core.ConnectionConnected {
+		return style.Render(connectionIndicator(conn.State))
+	}
+	return style.Render(fmt.Sprintf("%s %s", connectionIndicator(conn.State), conn.State))
+}
+
+// fluxStatus returns the Flux version of the current cluster, highlighted
+// when the installation has problems, or nothing before it was inspected
+func (m *AppModel) fluxStatus() string {
+	install, ok := m.manager.GetFluxInstall(m.state.CurrentCluster)
+	if !ok {
+		return ""
+	}
+	if install.Healthy() {
+		return m.theme.Muted.Render(fmt.Sprintf("(%s)", install.Summary()))
+	}
+	return m.theme.Warning.Render(fmt.Sprintf("(%s)", install.Summary()))
+}
+
+// handleClusterRemoved drops the cached data of a cluster that was removed
+// from the configuration and moves away from it if it was the current one
+func (m *AppModel) handleClusterRemoved(cluster string) tea.Cmd {
+	delete(m.state.Resources, cluster)
+	delete(m.state.Events, cluster)
+
+	if cluster != m.state.CurrentCluster {
+		return nil
+	}
+	if _, configured := m.manager.GetConnectionState(cluster); configured {
+		// Re-added with a changed kubeconfig or context
+		return nil
+	}
+	if clusters := m.manager.GetClusters(); len(clusters) > 0 {
+		return m.switchCluster(clusters[0])
+	}
+	return nil
+}
+
+// handleConfigReload applies a reloaded configuration. Invalid edits are
+// reported in the footer and leave the running configuration untouched.
+func (m *AppModel) handleConfigReload(msg ConfigReloadMsg) tea.Cmd {
+	if msg.Err != nil {
+		m.configError = configErrorSummary(msg.Err)
+		return nil
+	}
+
+	cfg := msg.Config
+	if cfg.UI.PaneEventsHeight != m.config.UI.PaneEventsHeight {
+		m.eventRows = cfg.UI.PaneEventsHeight
+	}
+	m.config = cfg
+	m.configError = ""
+	m.keys = NewKeyMap(cfg)
+	m.theme = NewTheme(cfg)
+	m.resourceView.SetConfig(cfg)
+	m.eventView.SetConfig(cfg)
+	m.logView.SetConfig(cfg)
+	m.diagnosticsView.SetConfig(cfg)
+	m.clusterView.SetConfig(cfg)
+	m.layout()
+	m.statusMessage = "Configuration reloaded"
+
+	// Connecting and disconnecting clusters publishes connection updates,
+	// which must not block the UI loop
+	manager := m.manager
+	return tea.Batch(
+		func() tea.Msg { return ConfigAppliedMsg{Err: manager.ApplyConfig(cfg)} },
+		tea.Tick(3*time.Second, func(time.Time) tea.Msg { return ClearStatusMsg{} }),
+	)
+}
+
+// configReloads returns the channel of config reloads, or nil when the
+// config file is not watched
+func (m *AppModel) configReloads() <-chan config.Reload {
+	if m.watcher == nil {
+		return nil
+	}
+	return m.watcher.Reloads()
+}
+
+// configErrorSummary condenses a config reload error into a single line
+func configErrorSummary(err error) string {
+	var validationErr *config.ValidationError
+	if !errors.As(err, &validationErr) {
+		return strings.SplitN(err.Error(), "\n", 2)[0]
+	}
+
+	var first *config.ValidationIssue
+	count := 0
+	for i, issue := range validationErr.Issues {
+		if issue.Severity != config.SeverityError {
+			continue
+		}
+		if first == nil {
+			first = &validationErr.Issues[i]
+		}
+		count++
+	}
+	if first == nil {
+		return validationErr.Error()
+	}
+
+	summary := fmt.Sprintf("%s:%s (not applied)", filepath.Base(validationErr.File), first)
+	if count > 1 {
+		summary = fmt.Sprintf("%s, %d more error(s)", summary, count-1)
+	}
+	return summary
+}
+
+// handleConnectionUpdate reacts to connection changes. When the current
+// cluster could not be reached and the user has not picked a cluster yet,
+// the first cluster that connects becomes the current one.
+func (m *AppModel) handleConnectionUpdate(msg ConnectionUpdateMsg) tea.Cmd {
+	if msg.State == core.ConnectionRemoved {
+		return m.handleClusterRemoved(msg.Cluster)
+	}
+	if m.clusterChosen || msg.State != core.ConnectionConnected || msg.Cluster == m.state.CurrentCluster {
+		return nil
+	}
+
+	current, ok := m.manager.GetConnectionState(m.state.CurrentCluster)
+	if ok && (current.State == core.ConnectionConnected ||
+		current.State == core.ConnectionConnecting ||
+		current.State == core.ConnectionDegraded) {
+		return nil
+	}
+
+	return m.switchCluster(msg.Cluster)
+}
+
+// renderClusterUnavailable renders a notice when the current cluster is not
+// connected and there is no cached data to show
+func (m *AppModel) renderClusterUnavailable() string {
+	conn, ok := m.manager.GetConnectionState(m.state.CurrentCluster)
+	if !ok || conn.State == core.ConnectionConnected || conn.State == core.ConnectionDegraded {
+		return ""
+	}
+	if len(m.state.Resources[m.state.CurrentCluster][m.state.CurrentResource]) > 0 {
+		return ""
+	}
+
+	var notice strings.Builder
+	notice.WriteString(fmt.Sprintf("%s Cluster %s is %s", connectionIndicator(conn.State), conn.Cluster, conn.State))
+	if conn.Error != nil {
+		notice.WriteString(fmt.Sprintf("\n\n%v", conn.Error))
+	}
+	if wait := time.Until(conn.NextRetry); wait > 0 {
+		notice.WriteString(fmt.Sprintf("\n\nRetrying in %s (attempt %d)", wait.Round(time.Second), conn.Attempt))
+	}
+	notice.WriteString(fmt.Sprintf("\n\nUse :clusters or %s to switch to another cluster",
+		pairLabel(firstKey(m.keys.PreviousCluster), firstKey(m.keys.NextCluster))))
+
+	return m.theme.Muted.
+		Width(m.width).
+		Render(notice.String())
+}
+
+// renderUnavailableSummary renders how many clusters are currently unavailable
+func (m *AppModel) renderUnavailableSummary() string {
+	states := m.manager.GetConnectionStates()
+	unavailable := 0
+	for _, conn := range states {
+		if conn.State == core.ConnectionUnreachable || conn.State == core.ConnectionAuthExpired ||
+			conn.State == core.ConnectionContextMissing {
+			unavailable++
+		}
+	}
+	if unavailable == 0 {
+		return ""
+	}
+
+	return m.theme.Error.
+		Bold(true).
+		Render(fmt.Sprintf("%s %d/%d clusters unavailable", connectionIndicator(core.ConnectionUnreachable), unavailable, len(states)))
+}
@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApp_HealthCommand(t *testing.T) {
+	app := newTestApp(t)
+
+	cmd := app.executeCommand("health failed,stalled")
+	assert.Equal(t, "Showing failed or stalled resources", app.statusMessage)
+	assertMessageStays(t, cmd)
+	app.Update(ClearStatusMsg{})
+	assert.Empty(t, app.statusMessage)
+
+	cmd = app.executeCommand("health broken")
+	assert.Equal(t, `unknown health state "broken", expected one of stalled, failed, unknown, progressing, reconciling, suspended, ready`, app.errorMessage)
+	assertMessageStays(t, cmd)
+	app.Update(ClearStatusMsg{})
+	assert.Empty(t, app.errorMessage)
+}
+
+func TestApp_SortCommand(t *testing.T) {
+	app := newTestApp(t)
+
+	for _, command := range []string{"sort", "sort size"} {
+		cmd := app.executeCommand(command)
+		assert.Equal(t, "Usage: sort name|status|age|transition|cluster [asc|desc]", app.errorMessage)
+		assertMessageStays(t, cmd)
+		app.Update(ClearStatusMsg{})
+		assert.Empty(t, app.errorMessage)
+	}
+}
+
+func TestApp_EventsAndFollowCommands(t *testing.T) {
+	app := newTestApp(t)
+
+	cmd := app.executeCommand("events type=Warning namespace=apps")
+	assert.Equal(t, "Showing events with type=Warning namespace=apps", app.statusMessage)
+	assertMessageStays(t, cmd)
+	app.Update(ClearStatusMsg{})
+	assert.Empty(t, app.statusMessage)
+
+	cmd = app.executeCommand("events severity=high")
+	assert.Equal(t, `unknown event filter "severity", expected type, reason, object or namespace`, app.errorMessage)
+	assertMessageStays(t, cmd)
+	app.Update(ClearStatusMsg{})
+
+	cmd = app.executeCommand("follow")
+	assert.Equal(t, "Following new events", app.statusMessage)
+	assertMessageStays(t, cmd)
+}
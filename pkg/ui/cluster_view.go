@@ -0,0 +1,210 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/malagant/fluxcli/internal/config"
+	"github.com/malagant/fluxcli/pkg/core"
+)
+
+// ClusterView displays the configured clusters and their connection state
+type ClusterView struct {
+	config      *config.Config
+	table       styledTable
+	connections []core.ConnectionUpdate
+	current     string
+	keys        KeyMap
+	theme       Theme
+	width       int
+	height      int
+}
+
+// SwitchClusterMsg requests switching to another cluster
+type SwitchClusterMsg struct {
+	Cluster string
+}
+
+// NewClusterView creates a new cluster view
+func NewClusterView(cfg *config.Config) *ClusterView {
+	t := newStyledTable(
+		table.WithColumns(clusterColumns(0)),
+		table.WithFocused(true),
+		table.WithHeight(10),
+	)
+
+	theme := NewTheme(cfg)
+	t.SetStyles(theme.Table)
+
+	return &ClusterView{
+		config: cfg,
+		table:  t,
+		keys:   NewKeyMap(cfg),
+		theme:  theme,
+	}
+}
+
+// Init initializes the cluster view
+func (v *ClusterView) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages for the cluster view
+func (v *ClusterView) Update(msg tea.Msg) (*ClusterView, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if handled, navCmd := v.keys.navigate(&v.table.Model, msg, len(v.connections)); handled {
+			cmd = navCmd
+		} else if key.Matches(msg, v.keys.Select) {
+			if selected := v.GetSelectedCluster(); selected != nil {
+				cluster := selected.Cluster
+				return v, func() tea.Msg { return SwitchClusterMsg{Cluster: cluster} }
+			}
+		}
+	}
+
+	return v, cmd
+}
+
+// View renders the cluster view
+func (v *ClusterView) View() string {
+	if len(v.connections) == 0 {
+		return v.theme.Muted.Render("No clusters configured")
+	}
+
+	return v.table.View()
+}
+
+// SetConnections sets the cluster connection states to display
+func (v *ClusterView) SetConnections(connections []core.ConnectionUpdate, current string) {
+	v.connections = connections
+	v.current = current
+	v.updateTable()
+}
+
+// SetConfig applies a reloaded configuration
+func (v *ClusterView) SetConfig(cfg *config.Config) {
+	v.config = cfg
+	v.keys = NewKeyMap(cfg)
+	v.theme = NewTheme(cfg)
+	v.table.SetStyles(v.theme.Table)
+}
+
+// SetSize sets the view dimensions
+func (v *ClusterView) SetSize(width, height int) {
+	v.width = width
+	v.height = height
+	v.table.SetHeight(height - 2) // Reserve space for borders
+	v.table.SetColumns(clusterColumns(width))
+}
+
+// GetSelectedCluster returns the currently selected cluster
+func (v *ClusterView) GetSelectedCluster() *core.ConnectionUpdate {
+	cursor := v.table.Cursor()
+	if cursor >= 0 && cursor < len(v.connections) {
+		return &v.connections[cursor]
+	}
+	return nil
+}
+
+// updateTable updates the table with current connection states
+func (v *ClusterView) updateTable() {
+	rows := make([]StyledRow, 0, len(v.connections))
+
+	for _, conn := range v.connections {
+		name := conn.Cluster
+		if name == v.current {
+			name = "* " + name
+		}
+
+		retry := ""
+		if !conn.NextRetry.IsZero() && conn.State != core.ConnectionConnected {
+			if wait := time.Until(conn.NextRetry); wait > 0 {
+				retry = fmt.Sprintf("in %s (#%d)", wait.Round(time.Second), conn.Attempt)
+			} else {
+				retry = fmt.Sprintf("retrying (#%d)", conn.Attempt)
+			}
+		}
+
+		message := ""
+		if conn.Error != nil {
+			message = conn.Error.Error()
+		}
+
+		flux := ""
+		var fluxProblems []string
+		if conn.Flux != nil {
+			flux = conn.Flux.Summary()
+			fluxProblems = conn.Flux.Problems()
+		}
+		if message == "" && len(fluxProblems) > 0 {
+			message = strings.Join(fluxProblems, "; ")
+		}
+
+		row := plainRow(
+			name,
+			fmt.Sprintf("%s %s", connectionIndicator(conn.State), conn.State),
+			formatAge(time.Since(conn.Since)),
+			retry,
+			flux,
+			message,
+		)
+		row.Cells[1].Style = v.theme.ConnectionStyle(conn.State)
+		if len(fluxProblems) > 0 {
+			row.Cells[4].Style = v.theme.Warning
+		}
+		if conn.State != core.ConnectionConnected {
+			row.Cells[5].Style = v.theme.ConnectionStyle(conn.State)
+		} else if len(fluxProblems) > 0 {
+			row.Cells[5].Style = v.theme.Warning
+		}
+		rows = append(rows, row)
+	}
+
+	v.table.SetStyledRows(rows)
+}
+
+// clusterColumns returns the cluster table columns for the given width
+func clusterColumns(width int) []table.Column {
+	columns := []table.Column{
+		{Title: "Cluster", Width: 25},
+		{Title: "State", Width: 16},
+		{Title: "Since", Width: 6},
+		{Title: "Retry", Width: 16},
+		{Title: "Flux", Width: 24},
+		{Title: "Message", Width: 50},
+	}
+
+	if width > 0 {
+		fixedWidth := 25 + 16 + 6 + 16 + 24 + 12 // Other columns + padding
+		if messageWidth := width - fixedWidth; messageWidth > 20 {
+			columns[5].Width = messageWidth
+		}
+	}
+
+	return columns
+}
+
+// connectionIndicator returns the status indicator for a connection state
+func connectionIndicator(state core.ConnectionState) string {
+	switch state {
+	case core.ConnectionConnected:
+		return "✓"
+	case core.ConnectionConnecting:
+		return "⟳"
+	case core.ConnectionDegraded:
+		return "⚠"
+	case core.ConnectionAuthExpired:
+		return "⚿"
+	case core.ConnectionContextMissing:
+		return "?"
+	default:
+		return "✗"
+	}
+}
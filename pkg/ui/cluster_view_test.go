@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/malagant/fluxcli/internal/config"
+	"github.com/malagant/fluxcli/pkg/core"
+	"github.com/malagant/fluxcli/pkg/k8s"
+)
+
+func TestClusterView_FluxInstall(t *testing.T) {
+	cfg, err := config.Load("", "", "", "")
+	require.NoError(t, err)
+
+	healthy := &k8s.FluxInstall{
+		Version: "v2.3.0",
+		APIs:    []k8s.FluxAPI{{Group: "source.toolkit.fluxcd.io", Versions: []string{"v1"}}},
+	}
+	missing := &k8s.FluxInstall{Namespace: "flux-system"}
+
+	v := NewClusterView(cfg)
+	v.SetSize(200, 10)
+	v.SetConnections([]core.ConnectionUpdate{
+		{Cluster: "prod", State: core.ConnectionConnected, Flux: healthy},
+		{Cluster: "dev", State: core.ConnectionConnected, Flux: missing},
+		{Cluster: "new", State: core.ConnectionConnecting},
+	}, "prod")
+
+	view := ansi.Strip(v.View())
+	assert.Contains(t, view, "Flux v2.3.0")
+	assert.Contains(t, view, "Flux not installed")
+	assert.Contains(t, view, "Flux is not installed in namespace flux-system")
+}
@@ -0,0 +1,310 @@
+package ui
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/malagant/fluxcli/internal/config"
+	"github.com/malagant/fluxcli/pkg/k8s"
+)
+
+// Column priorities, as in the responsive design of the UI spec. When the
+// terminal is too narrow, columns are dropped from the lowest priority up.
+const (
+	priorityHigh   = iota // Always shown
+	priorityMedium        // Shown if space is available
+	priorityLow           // Shown in wide terminals
+)
+
+// resourceColumn describes a column of the resource table
+type resourceColumn struct {
+	name     string // Name in ui.columns
+	title    string
+	width    int  // Preferred width
+	flex     bool // Takes a share of the remaining width
+	priority int
+	value    func(v *ResourceView, r k8s.Resource) string
+}
+
+// resourceColumns are the available columns by name
+var resourceColumns = map[string]resourceColumn{
+	"name": {title: "Name", priority: priorityHigh, value: func(v *ResourceView, r k8s.Resource) string {
+		if v.nsPrefix && r.Namespace != "" {
+			return fmt.Sprintf("%s/%s", r.Namespace, r.Name)
+		}
+		return r.Name
+	}},
+	"namespace": {title: "Namespace", width: 16, priority: priorityMedium, value: func(v *ResourceView, r k8s.Resource) string {
+		return r.Namespace
+	}},
+	"cluster": {title: "Cluster", width: 16, priority: priorityLow, value: func(v *ResourceView, r k8s.Resource) string {
+		return r.Cluster
+	}},
+	"health": {title: "Health", width: 13, priority: priorityHigh, value: func(v *ResourceView, r k8s.Resource) string {
+		return healthLabel(r.Health)
+	}},
+	"status": {title: "Status", priority: priorityLow, value: func(v *ResourceView, r k8s.Resource) string {
+		if r.Suspended {
+			return "Suspended"
+		}
+		if r.Status == "" {
+			return "Unknown"
+		}
+		return r.Status
+	}},
+	"age": {title: "Age", width: 6, priority: priorityHigh, value: func(v *ResourceView, r k8s.Resource) string {
+		return formatAge(r.Age)
+	}},
+	"transition": {title: "Changed", width: 8, priority: priorityLow, value: func(v *ResourceView, r k8s.Resource) string {
+		return formatSince(r.LastTransition())
+	}},
+	"message": {title: "Message", width: 35, flex: true, priority: priorityMedium, value: func(v *ResourceView, r k8s.Resource) string {
+		return r.Message
+	}},
+	"url": {title: "URL", width: 40, flex: true, priority: priorityLow, value: func(v *ResourceView, r k8s.Resource) string {
+		return r.URL
+	}},
+	"source": {title: "Source/Path", width: 30, flex: true, priority: priorityLow, value: func(v *ResourceView, r k8s.Resource) string {
+		if r.Path != "" {
+			return fmt.Sprintf("%s/%s", r.Source, r.Path)
+		}
+		return r.Source
+	}},
+	"chart": {title: "Chart", width: 25, priority: priorityLow, value: func(v *ResourceView, r k8s.Resource) string {
+		if r.Version != "" {
+			return fmt.Sprintf("%s:%s", r.Chart, r.Version)
+		}
+		return r.Chart
+	}},
+	"revision": {title: "Revision", width: 20, flex: true, priority: priorityLow, value: func(v *ResourceView, r k8s.Resource) string {
+		if r.RevisionBehind() {
+			return fmt.Sprintf("%s → %s", shortRevision(r.Revision), shortRevision(r.LastAttemptedRevision))
+		}
+		return shortRevision(r.Revision)
+	}},
+	"interval": {title: "Interval", width: 8, priority: priorityLow, value: func(v *ResourceView, r k8s.Resource) string {
+		if r.Interval == 0 {
+			return ""
+		}
+		return r.Interval.String()
+	}},
+	"last_reconcile": {title: "Reconciled", width: 10, priority: priorityLow, value: func(v *ResourceView, r k8s.Resource) string {
+		return formatSince(r.LastReconcile())
+	}},
+	"suspend_reason": {title: "Suspend Reason", width: 20, flex: true, priority: priorityLow, value: func(v *ResourceView, r k8s.Resource) string {
+		return r.SuspendReason
+	}},
+	"suspended_by": {title: "Suspended By", width: 20, priority: priorityLow, value: func(v *ResourceView, r k8s.Resource) string {
+		return r.SuspendedBy
+	}},
+}
+
+// defaultColumns are shown for a kind that has no columns configured
+var defaultColumns = map[k8s.ResourceType][]string{
+	k8s.ResourceTypeGitRepository:  {"name", "health", "status", "age", "revision", "message", "url"},
+	k8s.ResourceTypeHelmRepository: {"name", "health", "status", "age", "message", "url"},
+	k8s.ResourceTypeKustomization:  {"name", "health", "status", "age", "revision", "message", "source"},
+	k8s.ResourceTypeHelmRelease:    {"name", "health", "status", "age", "revision", "message", "chart"},
+}
+
+// wideColumns are added in wide mode when they are not shown already
+var wideColumns = []string{"revision", "interval", "last_reconcile", "suspend_reason", "suspended_by"}
+
+// cellPadding is the horizontal padding of a table cell
+const cellPadding = 2
+
+// sortColumns are the columns rows can be sorted by
+var sortColumns = []string{"name", "status", "age", "transition", "cluster"}
+
+// columnNames returns the columns of the current resource kind: the
+// configured or default ones, without age and message when ui.show_age or
+// ui.show_message is off, plus the wide columns in wide mode
+func (v *ResourceView) columnNames() []string {
+	names := v.config.ResourceColumns(string(v.resourceType))
+	if len(names) == 0 {
+		names = defaultColumns[v.resourceType]
+		if names == nil {
+			names = []string{"name", "health", "status", "age", "message"}
+		}
+	}
+
+	columns := make([]string, 0, len(names)+len(wideColumns))
+	for _, name := range names {
+		_, known := resourceColumns[name]
+		switch {
+		case !known, slices.Contains(columns, name):
+		case name == "age" && !v.config.UI.ShowAge:
+		case name == "message" && !v.config.UI.ShowMessage:
+		default:
+			columns = append(columns, name)
+		}
+	}
+	if v.wide {
+		for _, name := range wideColumns {
+			if !slices.Contains(columns, name) {
+				columns = append(columns, name)
+			}
+		}
+	}
+	return columns
+}
+
+// layoutColumns fits the columns into width. Columns are dropped from the
+// lowest priority and from the right until the preferred widths fit, then
+// flexible columns share the remaining space. A width of zero keeps the
+// preferred widths.
+func (v *ResourceView) layoutColumns(names []string, width int) []resourceColumn {
+	columns := make([]resourceColumn, 0, len(names))
+	for _, name := range names {
+		column := resourceColumns[name]
+		column.name = name
+		switch name {
+		case "name":
+			column.width = v.config.UI.ColumnsName
+		case "status":
+			column.width = v.config.UI.ColumnsStatus
+		}
+		columns = append(columns, column)
+	}
+	if width <= 0 {
+		return columns
+	}
+
+	used := func() int {
+		total := 0
+		for _, column := range columns {
+			total += column.width + cellPadding
+		}
+		return total
+	}
+	for used() > width {
+		drop := -1
+		for i, column := range columns {
+			if column.priority > priorityHigh && (drop < 0 || column.priority >= columns[drop].priority) {
+				drop = i
+			}
+		}
+		if drop < 0 {
+			break
+		}
+		columns = slices.Delete(columns, drop, drop+1)
+	}
+
+	flex := 0
+	for _, column := range columns {
+		if column.flex {
+			flex++
+		}
+	}
+	if extra := width - used(); flex > 0 && extra > 0 {
+		for i := range columns {
+			if columns[i].flex {
+				share := extra / flex
+				flex--
+				columns[i].width += share
+				extra -= share
+			}
+		}
+	}
+	return columns
+}
+
+// tableColumns returns the table columns for the current layout, marking
+// the sort column with the sort direction
+func (v *ResourceView) tableColumns() []table.Column {
+	columns := make([]table.Column, 0, len(v.columns))
+	for _, column := range v.columns {
+		title := column.title
+		if column.name == v.sortColumn && v.sortDesc {
+			title += " ▼"
+		} else if column.name == v.sortColumn {
+			title += " ▲"
+		}
+		columns = append(columns, table.Column{Title: title, Width: column.width})
+	}
+	return columns
+}
+
+// sortResources sorts resources by the sort column. Without a sort column
+// the rows stay in API order.
+func (v *ResourceView) sortResources(resources []k8s.Resource) {
+	if v.sortColumn == "" {
+		return
+	}
+
+	compare := func(a, b k8s.Resource) int {
+		switch v.sortColumn {
+		case "status":
+			return cmp.Or(
+				cmp.Compare(a.Health.Severity(), b.Health.Severity()),
+				strings.Compare(a.Status, b.Status),
+			)
+		case "age":
+			return cmp.Compare(a.Age, b.Age)
+		case "transition":
+			// Most recent first, like age
+			return b.LastTransition().Compare(a.LastTransition())
+		case "cluster":
+			return strings.Compare(a.Cluster, b.Cluster)
+		default:
+			return 0
+		}
+	}
+
+	slices.SortStableFunc(resources, func(a, b k8s.Resource) int {
+		c := cmp.Or(
+			compare(a, b),
+			strings.Compare(a.Namespace, b.Namespace),
+			strings.Compare(a.Name, b.Name),
+		)
+		if v.sortColumn == "name" {
+			c = cmp.Or(strings.Compare(a.Name, b.Name), strings.Compare(a.Namespace, b.Namespace))
+		}
+		if v.sortDesc {
+			return -c
+		}
+		return c
+	})
+}
+
+// shortRevision shortens the digest of a source revision, such as
+// main@sha1:<40 hex digits>, to 7 characters like git does. Other revisions,
+// such as chart versions, are returned unchanged.
+func shortRevision(revision string) string {
+	for _, algorithm := range []string{"sha1:", "sha256:"} {
+		if i := strings.LastIndex(revision, algorithm); i >= 0 {
+			end := i + len(algorithm) + 7
+			return revision[:min(end, len(revision))]
+		}
+	}
+	return revision
+}
+
+// formatSince formats the time since t as an age, empty for the zero time
+func formatSince(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return formatAge(time.Since(t))
+}
+
+// parseColumns parses a comma or space separated list of column names
+func parseColumns(args []string) ([]string, error) {
+	var columns []string
+	for _, arg := range args {
+		for _, name := range strings.Split(arg, ",") {
+			if name == "" {
+				continue
+			}
+			if !slices.Contains(config.TableColumns, name) {
+				return nil, fmt.Errorf("unknown column %q, expected one of %s", name, strings.Join(config.TableColumns, ", "))
+			}
+			columns = append(columns, name)
+		}
+	}
+	return columns, nil
+}
@@ -0,0 +1,163 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/malagant/fluxcli/internal/config"
+	"github.com/malagant/fluxcli/pkg/k8s"
+)
+
+// headerTitles returns the column titles of a resource view
+func headerTitles(rv *ResourceView) []string {
+	return strings.Fields(strings.Split(ansi.Strip(rv.View()), "\n")[0])
+}
+
+func TestResourceView_ColumnPriority(t *testing.T) {
+	cfg, err := config.Load("", "", "", "")
+	require.NoError(t, err)
+
+	rv := NewResourceView(cfg)
+	rv.SetResourceType(k8s.ResourceTypeKustomization)
+	rv.SetResources([]k8s.Resource{{Name: "apps", Health: k8s.HealthReady}})
+
+	rv.SetSize(200, 10)
+	assert.Equal(t, []string{"Name", "Health", "Status", "Age", "Revision", "Message", "Source/Path"}, headerTitles(rv))
+
+	// Low priority columns go first, then medium ones
+	rv.SetSize(120, 10)
+	assert.Equal(t, []string{"Name", "Health", "Status", "Age", "Message"}, headerTitles(rv))
+	rv.SetSize(100, 10)
+	assert.Equal(t, []string{"Name", "Health", "Age", "Message"}, headerTitles(rv))
+	rv.SetSize(60, 10)
+	assert.Equal(t, []string{"Name", "Health", "Age"}, headerTitles(rv))
+
+	// Flexible columns take the remaining width
+	rv.SetSize(200, 10)
+	lines := strings.Split(rv.View(), "\n")
+	assert.Equal(t, 200, ansi.StringWidth(lines[len(lines)-1]))
+}
+
+func TestResourceView_WideAndConfiguredColumns(t *testing.T) {
+	cfg, err := config.Load("", "", "", "")
+	require.NoError(t, err)
+	cfg.UI.ShowMessage = false
+	cfg.SetResourceColumns("HelmRelease", []string{"namespace", "name", "health", "message", "cluster"})
+
+	rv := NewResourceView(cfg)
+	rv.SetResourceType(k8s.ResourceTypeHelmRelease)
+	rv.SetResources([]k8s.Resource{{
+		Name: "podinfo", Namespace: "apps", Cluster: "prod", Health: k8s.HealthSuspended,
+		Revision: "6.5.0", Interval: 10 * time.Minute, SuspendReason: "maintenance",
+	}})
+	rv.SetSize(300, 10)
+
+	// The namespace has a column of its own, so names are not prefixed
+	assert.Equal(t, []string{"Namespace", "Name", "Health", "Cluster"}, headerTitles(rv))
+	assert.Contains(t, ansi.Strip(rv.View()), "apps              podinfo ")
+
+	rv, _ = rv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'W'}})
+	assert.True(t, rv.Wide())
+	view := ansi.Strip(rv.View())
+	assert.Contains(t, view, "Revision")
+	assert.Contains(t, view, "Suspend Reason")
+	assert.Contains(t, view, "10m0s")
+	assert.Contains(t, view, "maintenance")
+
+	cfg.SetResourceColumns("helmrelease", nil)
+	rv.SetConfig(cfg)
+	rv.SetWide(false)
+	assert.Equal(t, []string{"Name", "Health", "Status", "Age", "Revision", "Chart"}, headerTitles(rv))
+}
+
+func TestResourceView_SortKeys(t *testing.T) {
+	cfg, err := config.Load("", "", "", "")
+	require.NoError(t, err)
+	cfg.UI.ShowNamespace = false
+
+	rv := NewResourceView(cfg)
+	rv.SetSize(160, 10)
+	rv.SetResources([]k8s.Resource{
+		{Name: "bravo", Age: time.Hour, Health: k8s.HealthReady, Cluster: "b"},
+		{Name: "alpha", Age: 3 * time.Hour, Health: k8s.HealthFailed, Cluster: "c"},
+		{Name: "charlie", Age: time.Minute, Health: k8s.HealthProgressing, Cluster: "a"},
+	})
+
+	names := func() []string {
+		names := make([]string, len(rv.visible))
+		for i, resource := range rv.visible {
+			names[i] = resource.Name
+		}
+		return names
+	}
+	press := func(r rune) {
+		rv, _ = rv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+
+	// API order until a sort key is pressed
+	assert.Equal(t, []string{"bravo", "alpha", "charlie"}, names())
+
+	press('N')
+	assert.Equal(t, []string{"alpha", "bravo", "charlie"}, names())
+	assert.Equal(t, "Name", headerTitles(rv)[0])
+	assert.Equal(t, "▲", headerTitles(rv)[1])
+
+	// Pressing the key again reverses the order
+	press('N')
+	assert.Equal(t, []string{"charlie", "bravo", "alpha"}, names())
+	assert.Equal(t, "▼", headerTitles(rv)[1])
+
+	press('S')
+	assert.Equal(t, []string{"alpha", "charlie", "bravo"}, names())
+	press('A')
+	assert.Equal(t, []string{"charlie", "bravo", "alpha"}, names())
+	press('C')
+	assert.Equal(t, []string{"charlie", "bravo", "alpha"}, names())
+	press('C')
+	assert.Equal(t, []string{"alpha", "bravo", "charlie"}, names())
+
+	// The cursor follows the sorted rows
+	assert.Equal(t, "alpha", rv.GetSelectedResource().Name)
+}
+
+func TestResourceView_RevisionColumn(t *testing.T) {
+	withColors(t)
+	cfg, err := config.Load("", "", "", "")
+	require.NoError(t, err)
+	cfg.SetResourceColumns("kustomization", []string{"name", "revision"})
+
+	rv := NewResourceView(cfg)
+	rv.SetResourceType(k8s.ResourceTypeKustomization)
+	rv.SetResources([]k8s.Resource{
+		{Name: "apps", Revision: "main@sha1:0123456789abcdef0123456789abcdef01234567"},
+		{Name: "infra", Revision: "main@sha1:0123456789abcdef0123456789abcdef01234567",
+			LastAttemptedRevision: "main@sha1:fedcba9876543210fedcba9876543210fedcba98"},
+	})
+	rv.SetSize(120, 10)
+
+	lines := strings.Split(rv.View(), "\n")
+	apps, infra := lines[len(lines)-2], lines[len(lines)-1]
+	assert.Contains(t, ansi.Strip(apps), "main@sha1:0123456 ")
+	assert.NotContains(t, ansi.Strip(apps), "→")
+	assert.Contains(t, ansi.Strip(infra), "main@sha1:0123456 → main@sha1:fedcba9")
+	assert.Contains(t, infra, rv.theme.Warning.Render("main@sha1:0123456 → main@sha1:fedcba9"))
+}
+
+func TestShortRevision(t *testing.T) {
+	for revision, want := range map[string]string{
+		"main@sha1:0123456789abcdef0123456789abcdef01234567": "main@sha1:0123456",
+		"sha256:0123456789abcdef":                            "sha256:0123456",
+		"6.5.0@sha256:0123456789abcdef":                      "6.5.0@sha256:0123456",
+		"6.5.0":                                              "6.5.0",
+		"main@sha1:0123":                                     "main@sha1:0123",
+		"":                                                   "",
+	} {
+		assert.Equal(t, want, shortRevision(revision), revision)
+	}
+}
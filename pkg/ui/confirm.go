@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// pendingOperation is an operation that waits for a confirmation: the
+// cluster name on a protected cluster, otherwise y for an operation key
+type pendingOperation struct {
+	operation string
+	name      string
+	reason    string
+	ask       bool // Confirmed with y rather than the cluster name
+}
+
+// keyOperation asks before an operation key changes the selected resource,
+// as a stray key press must not change the cluster. Protected clusters ask
+// for the cluster name as for commands.
+func (m *AppModel) keyOperation(operation, name string) tea.Cmd {
+	if m.manager.IsReadOnly(m.state.CurrentCluster) || m.manager.IsProtected(m.state.CurrentCluster) {
+		return m.resourceOperation(operation, name, "")
+	}
+
+	m.confirmMode = true
+	m.confirmInput = ""
+	m.pending = pendingOperation{operation: operation, name: name, ask: true}
+	return nil
+}
+
+// confirmPrompt renders the question of the pending operation
+func (m *AppModel) confirmPrompt() string {
+	if m.pending.ask {
+		return fmt.Sprintf("%s%s %s on %s? (y/n)", strings.ToUpper(m.pending.operation[:1]), m.pending.operation[1:],
+			m.pending.name, m.state.CurrentCluster)
+	}
+	return fmt.Sprintf("Type %s to %s %s: %s", m.state.CurrentCluster, m.pending.operation, m.pending.name, m.confirmInput)
+}
+
+// handleConfirmMode handles keyboard input while an operation waits for y
+// or for the cluster name of a protected cluster. The manager checks the
+// name again before the operation runs.
+func (m *AppModel) handleConfirmMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.pending.ask {
+		switch msg.String() {
+		case "y", "Y":
+			m.confirmMode = false
+			pending := m.pending
+			m.pending = pendingOperation{}
+			return m, m.runOperation(pending.operation, pending.name, pending.reason)
+		case "n", "N", "esc":
+			return m, m.cancelOperation()
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "enter":
+		m.confirmMode = false
+		pending := m.pending
+		m.pending = pendingOperation{}
+		if err := m.manager.ConfirmChange(m.state.CurrentCluster, m.confirmInput); err != nil {
+			m.errorMessage = "Not confirmed: " + err.Error()
+			return m, tea.Tick(3*time.Second, func(time.Time) tea.Msg { return ClearStatusMsg{} })
+		}
+		return m, m.runOperation(pending.operation, pending.name, pending.reason)
+
+	case "esc":
+		return m, m.cancelOperation()
+
+	case "backspace":
+		if len(m.confirmInput) > 0 {
+			m.confirmInput = m.confirmInput[:len(m.confirmInput)-1]
+		}
+
+	default:
+		if len(msg.String()) == 1 {
+			m.confirmInput += msg.String()
+		}
+	}
+
+	return m, nil
+}
+
+// cancelOperation drops the pending operation
+func (m *AppModel) cancelOperation() tea.Cmd {
+	m.confirmMode = false
+	m.pending = pendingOperation{}
+	m.statusMessage = "Cancelled"
+	return tea.Tick(2*time.Second, func(time.Time) tea.Msg { return ClearStatusMsg{} })
+}
+
+// clusterMode renders a badge for a read-only or protected current cluster
+func (m *AppModel) clusterMode() string {
+	switch {
+	case m.manager.IsReadOnly(m.state.CurrentCluster):
+		return m.theme.Warning.Bold(true).Render("[READ-ONLY]")
+	case m.manager.IsProtected(m.state.CurrentCluster):
+		return m.theme.Warning.Bold(true).Render("[PROTECTED]")
+	}
+	return ""
+}
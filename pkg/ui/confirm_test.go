@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/malagant/fluxcli/internal/config"
+)
+
+func TestApp_ProtectedClusterConfirmation(t *testing.T) {
+	app := newTestApp(t)
+	cluster := app.state.CurrentCluster
+	app.config.Clusters = append(app.config.Clusters, config.ClusterConfig{Name: cluster, Context: cluster, Protected: true})
+
+	assert.Contains(t, ansi.Strip(app.View()), "[PROTECTED]")
+
+	// A wrong name does not run the operation
+	press(app, "P")
+	assert.True(t, app.confirmMode)
+	assert.Contains(t, ansi.Strip(app.View()), "Type "+cluster+" to suspend flux-system:")
+	press(app, "x")
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assertMessageStays(t, cmd)
+	assert.False(t, app.confirmMode)
+	assert.Contains(t, app.errorMessage, "Not confirmed")
+
+	// The cluster name lets the operation through to the manager
+	press(app, "P")
+	press(app, strings.Split(cluster, "")...)
+	press(app, "enter")
+	assert.Contains(t, app.errorMessage, "Failed to suspend flux-system")
+	assert.NotContains(t, app.errorMessage, "protected")
+
+	// Esc cancels
+	press(app, "f")
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assertMessageStays(t, cmd)
+	assert.False(t, app.confirmMode)
+	assert.Equal(t, "Cancelled", app.statusMessage)
+}
+
+func TestApp_ReadOnly(t *testing.T) {
+	app := newTestApp(t)
+	app.config.SetReadOnly()
+
+	view := ansi.Strip(app.View())
+	assert.Contains(t, view, "[READ-ONLY]")
+	assert.Contains(t, view, "does not allow changes, f/P/U are disabled")
+
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("U")})
+	assertMessageStays(t, cmd)
+	assert.False(t, app.confirmMode)
+	assert.Contains(t, app.errorMessage, "is read-only, resume is blocked")
+}
+
+func TestApp_OperationKeysAsk(t *testing.T) {
+	app := newTestApp(t)
+
+	// n cancels
+	press(app, "P")
+	assert.True(t, app.confirmMode)
+	assert.Contains(t, ansi.Strip(app.View()), "Suspend flux-system on "+app.state.CurrentCluster+"? (y/n)")
+	press(app, "x")
+	assert.True(t, app.confirmMode, "other keys are ignored")
+	press(app, "n")
+	assert.False(t, app.confirmMode)
+	assert.Equal(t, "Cancelled", app.statusMessage)
+
+	// y lets the operation through to the manager
+	press(app, "U", "y")
+	assert.False(t, app.confirmMode)
+	assert.Contains(t, app.errorMessage, "Failed to resume flux-system")
+
+	// Refresh does not change anything and needs no confirmation
+	app.errorMessage = ""
+	press(app, "r")
+	assert.False(t, app.confirmMode)
+	assert.Empty(t, app.errorMessage)
+}
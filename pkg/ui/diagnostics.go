@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/malagant/fluxcli/pkg/k8s"
+)
+
+// DiagnosisMsg delivers the diagnosis of a cluster. Diagnoses of an earlier
+// run are ignored.
+type DiagnosisMsg struct {
+	Run       int
+	Diagnosis *k8s.Diagnosis
+	Err       error
+}
+
+// openDiagnostics shows the diagnostics view and diagnoses the current
+// cluster in the background
+func (m *AppModel) openDiagnostics() tea.Cmd {
+	m.stopLogs()
+	m.currentView = ViewDiagnostics
+	return m.runDiagnostics()
+}
+
+// runDiagnostics diagnoses the current cluster again
+func (m *AppModel) runDiagnostics() tea.Cmd {
+	m.diagnosticsRun++
+	run := m.diagnosticsRun
+	cluster := m.state.CurrentCluster
+	m.diagnosticsView.Start(cluster, m.manager.GetCurrentNamespace())
+
+	manager := m.manager
+	return func() tea.Msg {
+		diagnosis, err := manager.Diagnose(context.Background(), cluster)
+		return DiagnosisMsg{Run: run, Diagnosis: diagnosis, Err: err}
+	}
+}
+
+// handleDiagnosis shows the diagnosis of the latest run
+func (m *AppModel) handleDiagnosis(msg DiagnosisMsg) {
+	if msg.Run != m.diagnosticsRun {
+		return
+	}
+	m.diagnosticsView.SetDiagnosis(msg.Diagnosis, msg.Err)
+}
@@ -0,0 +1,223 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/malagant/fluxcli/internal/config"
+	"github.com/malagant/fluxcli/pkg/k8s"
+)
+
+// DiagnosticsView displays the diagnosis of a cluster: one row per check
+// and the hint of the selected check below the table
+type DiagnosticsView struct {
+	config    *config.Config
+	table     styledTable
+	cluster   string
+	namespace string
+	diagnosis *k8s.Diagnosis
+	err       error
+	running   bool
+	keys      KeyMap
+	theme     Theme
+	width     int
+	height    int
+}
+
+// diagnosticsViewChrome is the height of the diagnostics view besides its
+// rows: the title, the table header and two lines of hint
+const diagnosticsViewChrome = 5
+
+// NewDiagnosticsView creates a new diagnostics view
+func NewDiagnosticsView(cfg *config.Config) *DiagnosticsView {
+	t := newStyledTable(
+		table.WithColumns(diagnosticsColumns(0)),
+		table.WithFocused(true),
+	)
+
+	theme := NewTheme(cfg)
+	t.SetStyles(theme.Table)
+
+	return &DiagnosticsView{
+		config: cfg,
+		table:  t,
+		keys:   NewKeyMap(cfg),
+		theme:  theme,
+	}
+}
+
+// Init initializes the diagnostics view
+func (v *DiagnosticsView) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages for the diagnostics view
+func (v *DiagnosticsView) Update(msg tea.Msg) (*DiagnosticsView, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		rows := 0
+		if v.diagnosis != nil {
+			rows = len(v.diagnosis.Checks)
+		}
+		_, cmd := v.keys.navigate(&v.table.Model, msg, rows)
+		return v, cmd
+	}
+	return v, nil
+}
+
+// Start shows that a cluster is being diagnosed and drops the previous
+// diagnosis
+func (v *DiagnosticsView) Start(cluster, namespace string) {
+	v.cluster, v.namespace = cluster, namespace
+	v.diagnosis = nil
+	v.err = nil
+	v.running = true
+	v.updateTable()
+}
+
+// SetDiagnosis shows the diagnosis of the cluster, or the error that
+// prevented it
+func (v *DiagnosticsView) SetDiagnosis(diagnosis *k8s.Diagnosis, err error) {
+	v.diagnosis = diagnosis
+	v.err = err
+	v.running = false
+	v.updateTable()
+}
+
+// Cluster returns the cluster shown
+func (v *DiagnosticsView) Cluster() string {
+	return v.cluster
+}
+
+// Running reports whether the diagnosis is still running
+func (v *DiagnosticsView) Running() bool {
+	return v.running
+}
+
+// GetSelectedCheck returns the currently selected check
+func (v *DiagnosticsView) GetSelectedCheck() *k8s.Check {
+	if v.diagnosis == nil {
+		return nil
+	}
+	cursor := v.table.Cursor()
+	if cursor >= 0 && cursor < len(v.diagnosis.Checks) {
+		return &v.diagnosis.Checks[cursor]
+	}
+	return nil
+}
+
+// View renders the diagnostics view
+func (v *DiagnosticsView) View() string {
+	title := v.titleView()
+
+	var message string
+	switch {
+	case v.running:
+		message = v.theme.Muted.Render("Running diagnostics...")
+	case v.err != nil:
+		message = v.theme.Error.Render(v.err.Error())
+	case v.diagnosis == nil || len(v.diagnosis.Checks) == 0:
+		message = v.theme.Muted.Render("No diagnosis")
+	}
+	if message != "" {
+		box := lipgloss.NewStyle().
+			Border(lipgloss.NormalBorder()).
+			BorderForeground(v.theme.Border).
+			Height(max(v.height-diagnosticsViewChrome, 1)).
+			Padding(1, 2).
+			Render(message)
+		return lipgloss.JoinVertical(lipgloss.Left, title, box)
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, title, v.table.View(), v.hintView())
+}
+
+// titleView renders the cluster, the namespace and the summary
+func (v *DiagnosticsView) titleView() string {
+	scope := "all namespaces"
+	if v.namespace != "" {
+		scope = "namespace " + v.namespace
+	}
+	title := fmt.Sprintf("Diagnostics of %s in %s", v.cluster, scope)
+	if v.diagnosis != nil && !v.running {
+		title += " · " + v.diagnosis.Summary()
+	}
+	return v.theme.Title.Render(title)
+}
+
+// hintView renders what to do about the selected check, wrapped to two
+// lines
+func (v *DiagnosticsView) hintView() string {
+	text := fmt.Sprintf("%s run again", firstKey(v.keys.Refresh))
+	if check := v.GetSelectedCheck(); check != nil && check.Hint != "" && check.Status != k8s.CheckPassed {
+		text = "→ " + check.Hint
+	}
+	return v.theme.Hint.Width(max(v.width, 20)).MaxHeight(2).Render(text)
+}
+
+// checkStyle returns the style of a check status
+func (v *DiagnosticsView) checkStyle(status k8s.CheckStatus) lipgloss.Style {
+	switch status {
+	case k8s.CheckPassed:
+		return v.theme.Success
+	case k8s.CheckWarning:
+		return v.theme.Warning
+	default:
+		return v.theme.Error
+	}
+}
+
+// SetConfig applies a reloaded configuration
+func (v *DiagnosticsView) SetConfig(cfg *config.Config) {
+	v.config = cfg
+	v.keys = NewKeyMap(cfg)
+	v.theme = NewTheme(cfg)
+	v.table.SetStyles(v.theme.Table)
+	v.updateTable()
+}
+
+// SetSize sets the view dimensions
+func (v *DiagnosticsView) SetSize(width, height int) {
+	v.width = width
+	v.height = height
+	v.table.SetHeight(height - diagnosticsViewChrome)
+	v.table.SetColumns(diagnosticsColumns(width))
+}
+
+// updateTable shows the checks of the diagnosis, keeping the cursor
+func (v *DiagnosticsView) updateTable() {
+	var rows []StyledRow
+	if v.diagnosis != nil {
+		for _, check := range v.diagnosis.Checks {
+			row := plainRow(check.Status.Icon(), check.Name, strings.ReplaceAll(check.Message, "\n", " "))
+			row.Cells[0].Style = v.checkStyle(check.Status)
+			if check.Status == k8s.CheckFailed {
+				row.Cells[2].Style = v.theme.Error
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	v.table.SetStyledRows(rows)
+	if cursor := v.table.Cursor(); cursor >= len(rows) || cursor < 0 {
+		v.table.SetCursor(0)
+	}
+}
+
+// diagnosticsColumns returns the columns of the diagnostics table, the
+// result taking the width left by the others
+func diagnosticsColumns(width int) []table.Column {
+	columns := []table.Column{
+		{Title: " ", Width: 1},
+		{Title: "Check", Width: 34},
+		{Title: "Result", Width: 60},
+	}
+	// Each cell is padded by one character on both sides
+	fixedWidth := 1 + 34 + 3*2
+	if resultWidth := width - fixedWidth; resultWidth > 20 {
+		columns[2].Width = resultWidth
+	}
+	return columns
+}
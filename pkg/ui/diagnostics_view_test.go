@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/malagant/fluxcli/pkg/k8s"
+)
+
+func TestApp_Diagnostics(t *testing.T) {
+	app := newTestApp(t)
+
+	press(app, "D")
+	assert.Equal(t, ViewDiagnostics, app.currentView)
+	assert.True(t, app.diagnosticsView.Running())
+	assert.Contains(t, ansi.Strip(app.View()), "Running diagnostics...")
+
+	diagnosis := &k8s.Diagnosis{Namespace: "apps", Checks: []k8s.Check{
+		{Name: "Connectivity", Status: k8s.CheckPassed, Message: "API server reachable"},
+		{Name: "Access Kustomization", Status: k8s.CheckFailed, Message: "list denied in namespace apps",
+			Hint: "ask for a role granting list on kustomizations.kustomize.toolkit.fluxcd.io in namespace apps"},
+	}}
+
+	// A diagnosis of an earlier run is ignored
+	app.Update(DiagnosisMsg{Run: app.diagnosticsRun - 1, Diagnosis: diagnosis})
+	assert.True(t, app.diagnosticsView.Running())
+
+	app.Update(DiagnosisMsg{Run: app.diagnosticsRun, Diagnosis: diagnosis})
+	view := ansi.Strip(app.View())
+	assert.Contains(t, view, "1 failed, 0 warning(s)")
+	assert.Contains(t, view, "API server reachable")
+	assert.NotContains(t, view, "ask for a role")
+
+	// The hint of the selected check is shown below the table
+	press(app, "j")
+	assert.Contains(t, ansi.Strip(app.View()), "→ ask for a role granting list")
+
+	press(app, "esc")
+	assert.Equal(t, ViewResources, app.currentView)
+}
@@ -1,55 +1,156 @@
 package ui
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/malagant/fluxcli/internal/config"
+	"github.com/malagant/fluxcli/pkg/k8s"
+	corev1 "k8s.io/api/core/v1"
 )
 
-// EventView displays Kubernetes events in a table
+// EventView displays Kubernetes events in a table, most recently seen first
 type EventView struct {
-	config *config.Config
-	table  table.Model
-	events []Event
-	width  int
-	height int
+	config  *config.Config
+	table   styledTable
+	events  []Event
+	visible []Event // Events passing the filter, sorted and limited
+	filter  EventFilter
+	follow  bool // Keep the newest event selected
+	detail  bool // Show the selected event in full
+	keys    KeyMap
+	theme   Theme
+	width   int
+	height  int
+}
+
+// minListedEvents is the number of events listed in a small pane
+const minListedEvents = 100
+
+// EventFilter selects the events shown. Empty fields match every event.
+type EventFilter struct {
+	Type      string // Normal or Warning
+	Reason    string
+	Object    string // Kind/name or name of the involved object
+	Namespace string
+}
+
+// Matches reports whether an event passes the filter. Fields are compared
+// ignoring case.
+func (f EventFilter) Matches(event Event) bool {
+	if f.Type != "" && !strings.EqualFold(f.Type, event.Type) {
+		return false
+	}
+	if f.Reason != "" && !strings.EqualFold(f.Reason, event.Reason) {
+		return false
+	}
+	if f.Namespace != "" && f.Namespace != event.Namespace {
+		return false
+	}
+	if f.Object != "" && !strings.EqualFold(f.Object, event.Object) && !strings.EqualFold(f.Object, event.Name) {
+		return false
+	}
+	return true
+}
+
+// IsZero reports whether the filter matches every event
+func (f EventFilter) IsZero() bool {
+	return f == EventFilter{}
+}
+
+// String describes the filter as key=value pairs, as taken by ParseEventFilter
+func (f EventFilter) String() string {
+	var parts []string
+	for _, field := range []struct{ key, value string }{
+		{"type", f.Type},
+		{"reason", f.Reason},
+		{"object", f.Object},
+		{"namespace", f.Namespace},
+	} {
+		if field.value != "" {
+			parts = append(parts, fmt.Sprintf("%s=%s", field.key, field.value))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// ParseEventFilter parses key=value arguments such as type=Warning,
+// reason=ReconciliationFailed, object=Kustomization/apps or namespace=apps
+func ParseEventFilter(args []string) (EventFilter, error) {
+	var filter EventFilter
+	for _, arg := range args {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok || value == "" {
+			return EventFilter{}, fmt.Errorf("invalid event filter %q, expected key=value", arg)
+		}
+		switch strings.ToLower(name) {
+		case "type":
+			if !strings.EqualFold(value, corev1.EventTypeNormal) && !strings.EqualFold(value, corev1.EventTypeWarning) {
+				return EventFilter{}, fmt.Errorf("unknown event type %q, expected Normal or Warning", value)
+			}
+			filter.Type = value
+		case "reason":
+			filter.Reason = value
+		case "object":
+			filter.Object = value
+		case "namespace", "ns":
+			filter.Namespace = value
+		default:
+			return EventFilter{}, fmt.Errorf("unknown event filter %q, expected type, reason, object or namespace", name)
+		}
+	}
+	return filter, nil
+}
+
+// NewEvent converts a Kubernetes event for display
+func NewEvent(event corev1.Event) Event {
+	return Event{
+		UID:       string(event.UID),
+		Type:      event.Type,
+		Reason:    event.Reason,
+		Kind:      event.InvolvedObject.Kind,
+		Name:      event.InvolvedObject.Name,
+		Namespace: event.InvolvedObject.Namespace,
+		Object:    fmt.Sprintf("%s/%s", event.InvolvedObject.Kind, event.InvolvedObject.Name),
+		Message:   event.Message,
+		FirstSeen: k8s.EventFirstSeen(event),
+		LastSeen:  k8s.EventLastSeen(event),
+		Count:     k8s.EventCount(event),
+	}
 }
 
 // NewEventView creates a new event view
 func NewEventView(cfg *config.Config) *EventView {
 	columns := []table.Column{
-		{Title: "Type", Width: 6},
+		{Title: "Type", Width: 7},
 		{Title: "Reason", Width: 12},
 		{Title: "Object", Width: 22},
 		{Title: "Message", Width: 60},
-		{Title: "Time", Width: 8},
+		{Title: "Last Seen", Width: 9},
 		{Title: "Count", Width: 5},
 	}
 
-	t := table.New(
+	t := newStyledTable(
 		table.WithColumns(columns),
 		table.WithFocused(false), // Events view is not focused by default
 		table.WithHeight(cfg.UI.PaneEventsHeight),
 	)
 
-	s := table.DefaultStyles()
-	s.Header = s.Header.
-		BorderStyle(lipgloss.NormalBorder()).
-		BorderForeground(lipgloss.Color("240")).
-		BorderBottom(true).
-		Bold(false)
-	s.Selected = s.Selected.
-		Foreground(lipgloss.Color("229")).
-		Background(lipgloss.Color("57")).
-		Bold(false)
-	t.SetStyles(s)
+	theme := NewTheme(cfg)
+	t.SetStyles(theme.Table)
 
 	return &EventView{
 		config: cfg,
 		table:  t,
+		keys:   NewKeyMap(cfg),
+		theme:  theme,
 	}
 }
 
@@ -61,104 +162,144 @@ func (v *EventView) Init() tea.Cmd {
 // Update handles messages for the event view
 func (v *EventView) Update(msg tea.Msg) (*EventView, tea.Cmd) {
 	var cmd tea.Cmd
-	
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		// Handle arrow keys by checking Type directly
-		switch msg.Type {
-		case tea.KeyDown:
-			v.table, cmd = v.table.Update(msg)
-		case tea.KeyUp:
-			v.table, cmd = v.table.Update(msg)
-		case tea.KeyLeft:
-			v.table, cmd = v.table.Update(msg)
-		case tea.KeyRight:
-			v.table, cmd = v.table.Update(msg)
-		case tea.KeyPgDown:
-			v.table, cmd = v.table.Update(msg)
-		case tea.KeyPgUp:
-			v.table, cmd = v.table.Update(msg)
-		case tea.KeyHome:
-			if len(v.events) > 0 {
-				v.table.GotoTop()
-			}
-		case tea.KeyEnd:
-			if len(v.events) > 0 {
-				v.table.GotoBottom()
+		if v.detail {
+			// The detail popup is closed by select or back
+			if key.Matches(msg, v.keys.Select, v.keys.Back) {
+				v.detail = false
 			}
-		case tea.KeyEnter, tea.KeySpace:
-			// TODO: Show event details
 			return v, nil
-		default:
-			// Handle string-based keys
-			switch msg.String() {
-			// Vertical navigation - j/k for vim users
-			case "j":
-				v.table, cmd = v.table.Update(tea.KeyMsg{Type: tea.KeyDown})
-			case "k":
-				v.table, cmd = v.table.Update(tea.KeyMsg{Type: tea.KeyUp})
-			
-			// Page navigation with vim-style shortcuts
-			case "ctrl+d":
-				v.table, cmd = v.table.Update(tea.KeyMsg{Type: tea.KeyPgDown})
-			case "ctrl+u":
-				v.table, cmd = v.table.Update(tea.KeyMsg{Type: tea.KeyPgUp})
-			
-			// Vim-style navigation
-			case "g":
-				// Go to top
-				if len(v.events) > 0 {
-					v.table.GotoTop()
-				}
-			case "G":
-				// Go to bottom
-				if len(v.events) > 0 {
-					v.table.GotoBottom()
-				}
-			case "H":
-				// Go to top of visible area
-				if len(v.events) > 0 {
-					v.table.GotoTop()
-				}
-			case "M":
-				// Go to middle of visible area
-				if len(v.events) > 0 {
-					middle := len(v.events) / 2
-					for i := 0; i < middle; i++ {
-						v.table, _ = v.table.Update(tea.KeyMsg{Type: tea.KeyDown})
-					}
-				}
-			case "L":
-				// Go to bottom of visible area
-				if len(v.events) > 0 {
-					v.table.GotoBottom()
-				}
-			}
+		}
+
+		if handled, navCmd := v.keys.navigate(&v.table.Model, msg, len(v.visible)); handled {
+			cmd = navCmd
+			// Moving away from the newest event stops following
+			v.follow = v.follow && v.table.Cursor() == 0
+		} else if key.Matches(msg, v.keys.Select) {
+			v.detail = v.GetSelectedEvent() != nil
+		} else if key.Matches(msg, v.keys.EventType) {
+			v.filter.Type = nextEventType(v.filter.Type)
+			v.updateTable()
+		} else if key.Matches(msg, v.keys.ObjectEvents) {
+			v.toggleObjectFilter()
+		} else if key.Matches(msg, v.keys.Follow) {
+			v.SetFollow(!v.follow)
 		}
 	}
-	
+
 	return v, cmd
 }
 
+// nextEventType cycles the type filter from all events to warnings, normal
+// events and back
+func nextEventType(eventType string) string {
+	switch {
+	case eventType == "":
+		return corev1.EventTypeWarning
+	case strings.EqualFold(eventType, corev1.EventTypeWarning):
+		return corev1.EventTypeNormal
+	default:
+		return ""
+	}
+}
+
+// toggleObjectFilter shows only the events of the selected event's object,
+// or the events of every object when they are already filtered by object
+func (v *EventView) toggleObjectFilter() {
+	if v.filter.Object != "" {
+		v.filter.Object, v.filter.Namespace = "", ""
+	} else if selected := v.GetSelectedEvent(); selected != nil {
+		v.filter.Object, v.filter.Namespace = selected.Object, selected.Namespace
+	}
+	v.updateTable()
+}
+
 // View renders the event view
 func (v *EventView) View() string {
-	if len(v.events) == 0 {
-		emptyMsg := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("244")).
-			Render("No events found")
-		
+	if v.detail {
+		if selected := v.GetSelectedEvent(); selected != nil {
+			return v.detailView(*selected)
+		}
+	}
+
+	title := v.titleView()
+	if len(v.visible) == 0 {
+		message := "No events found"
+		if len(v.events) > 0 {
+			message = fmt.Sprintf("No events match %s", v.filter)
+		}
+		emptyMsg := v.theme.Muted.Render(message)
+
 		// Create a bordered box for consistency
 		box := lipgloss.NewStyle().
 			Border(lipgloss.NormalBorder()).
-			BorderForeground(lipgloss.Color("240")).
-			Height(v.config.UI.PaneEventsHeight).
+			BorderForeground(v.theme.Border).
+			Height(max(v.height-eventsPaneChrome, 1)).
 			Padding(1, 2).
 			Render(emptyMsg)
-		
-		return box
+
+		return lipgloss.JoinVertical(lipgloss.Left, title, box)
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, title, v.table.View())
+}
+
+// titleView renders the number of events, the filter and the follow state
+func (v *EventView) titleView() string {
+	title := fmt.Sprintf("Events (%d)", len(v.visible))
+	if !v.filter.IsZero() {
+		title = fmt.Sprintf("Events (%d of %d) %s", len(v.visible), len(v.events), v.filter)
 	}
-	
-	return v.table.View()
+	if v.follow {
+		title += " · following"
+	}
+	if v.table.Focused() {
+		return v.theme.Title.Render(title)
+	}
+	return v.theme.Muted.Render(title)
+}
+
+// detailView renders an event with its full message
+func (v *EventView) detailView(event Event) string {
+	typeStyle := v.theme.Muted
+	if event.Type == corev1.EventTypeWarning {
+		typeStyle = v.theme.Warning.Bold(true)
+	}
+
+	label := lipgloss.NewStyle().Bold(true)
+	var fields strings.Builder
+	for _, field := range []struct{ name, value string }{
+		{"Type", typeStyle.Render(event.Type)},
+		{"Reason", event.Reason},
+		{"Object", event.Object},
+		{"Namespace", event.Namespace},
+		{"First seen", formatEventTime(event.FirstSeen)},
+		{"Last seen", formatEventTime(event.LastSeen)},
+		{"Count", fmt.Sprintf("%d", max(event.Count, 1))},
+	} {
+		fmt.Fprintf(&fields, "%s %s\n", label.Render(fmt.Sprintf("%-11s", field.name+":")), field.value)
+	}
+
+	width := max(v.width-4, 20)
+	message := lipgloss.NewStyle().Width(width - 2).Render(event.Message)
+	hint := v.theme.Hint.Render(fmt.Sprintf("%s close", firstKey(v.keys.Back)))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(v.theme.Border).
+		Padding(0, 1).
+		Width(width).
+		Render(fields.String() + "\n" + message + "\n\n" + hint)
+}
+
+// formatEventTime formats an event time with its age
+func formatEventTime(t time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+	return fmt.Sprintf("%s (%s ago)", t.Local().Format("2006-01-02 15:04:05"), formatSince(t))
 }
 
 // SetEvents sets the events to display
@@ -167,15 +308,64 @@ func (v *EventView) SetEvents(events []Event) {
 	v.updateTable()
 }
 
+// SetFilter shows only the events matching filter
+func (v *EventView) SetFilter(filter EventFilter) {
+	v.filter = filter
+	v.updateTable()
+}
+
+// Filter returns the filter of the shown events
+func (v *EventView) Filter() EventFilter {
+	return v.filter
+}
+
+// SetFollow keeps the newest event selected as events arrive
+func (v *EventView) SetFollow(follow bool) {
+	v.follow = follow
+	if follow {
+		v.table.GotoTop()
+	}
+}
+
+// Following reports whether follow mode is on
+func (v *EventView) Following() bool {
+	return v.follow
+}
+
+// ShowingDetail reports whether the detail popup is open
+func (v *EventView) ShowingDetail() bool {
+	return v.detail
+}
+
+// GetSelectedEvent returns the currently selected event
+func (v *EventView) GetSelectedEvent() *Event {
+	cursor := v.table.Cursor()
+	if cursor >= 0 && cursor < len(v.visible) {
+		return &v.visible[cursor]
+	}
+	return nil
+}
+
+// SetConfig applies a reloaded configuration
+func (v *EventView) SetConfig(cfg *config.Config) {
+	v.config = cfg
+	v.keys = NewKeyMap(cfg)
+	v.theme = NewTheme(cfg)
+	v.table.SetStyles(v.theme.Table)
+	v.updateTableColumns()
+	v.updateTable()
+}
+
 // SetSize sets the view dimensions
 func (v *EventView) SetSize(width, height int) {
 	v.width = width
 	v.height = height
-	v.table.SetHeight(height - 2) // Reserve space for borders
+	v.table.SetHeight(height - 3) // Reserve space for the title and borders
 	v.updateTableColumns()
 }
 
-// SetFocused sets whether the event view is focused
+// SetFocused sets whether the event view is focused. Only a focused view
+// highlights the selected row and its title.
 func (v *EventView) SetFocused(focused bool) {
 	v.table.Focus()
 	if !focused {
@@ -183,94 +373,86 @@ func (v *EventView) SetFocused(focused bool) {
 	}
 }
 
-// updateTable updates the table with current events
+// updateTable filters and sorts the events, most recently seen first. The
+// selection stays on the same event unless following, which selects the
+// newest one.
 func (v *EventView) updateTable() {
-	rows := make([]table.Row, 0, len(v.events))
-	
-	// Sort events by timestamp (most recent first) and limit to recent events
-	maxEvents := v.config.UI.PaneEventsHeight * 5 // Show more events than visible
-	if maxEvents > len(v.events) {
-		maxEvents = len(v.events)
-	}
-	
-	for i := 0; i < maxEvents; i++ {
-		event := v.events[i]
-		row := v.createTableRow(event)
-		rows = append(rows, row)
+	var selected string
+	if event := v.GetSelectedEvent(); event != nil {
+		selected = event.UID
 	}
-	
-	v.table.SetRows(rows)
-}
 
-// createTableRow creates a table row for an event
-func (v *EventView) createTableRow(event Event) table.Row {
-	// Use plain text without color styling to avoid display corruption
-	eventType := event.Type
-	if len(eventType) > 6 {
-		eventType = eventType[:6]
-	}
-	
-	// Format reason
-	reason := event.Reason
-	if len(reason) > 10 {
-		reason = reason[:9] + "…"
+	v.visible = make([]Event, 0, len(v.events))
+	for _, event := range v.events {
+		if v.filter.Matches(event) {
+			v.visible = append(v.visible, event)
+		}
 	}
-	
-	// Format object
-	object := event.Object
-	if len(object) > 20 {
-		object = object[:19] + "…"
+	slices.SortStableFunc(v.visible, func(a, b Event) int {
+		return cmp.Or(b.LastSeen.Compare(a.LastSeen), strings.Compare(a.Object, b.Object))
+	})
+
+	// Show more events than visible
+	maxEvents := max(v.height*5, minListedEvents)
+	if len(v.visible) > maxEvents {
+		v.visible = v.visible[:maxEvents]
 	}
-	
-	// Format message (truncate if too long)
-	message := event.Message
-	maxMessageLength := 55
-	if v.width > 0 {
-		// Adjust message length based on available width
-		usedWidth := 6 + 12 + 22 + 8 + 5 + 10 // Other columns + padding
-		maxMessageLength = v.width - usedWidth
-		if maxMessageLength < 20 {
-			maxMessageLength = 20
+
+	rows := make([]StyledRow, 0, len(v.visible))
+	cursor := 0
+	for i, event := range v.visible {
+		// The open detail popup keeps its event even when following
+		if (!v.follow || v.detail) && selected != "" && event.UID == selected {
+			cursor = i
 		}
+		row := v.createTableRow(event)
+		rows = append(rows, row)
 	}
-	
-	if len(message) > maxMessageLength {
-		message = message[:maxMessageLength-3] + "…"
-	}
-	
-	// Format timestamp
-	timeFormatted := event.Timestamp
-	
+
+	v.table.SetStyledRows(rows)
+	v.table.SetCursor(cursor)
+}
+
+// createTableRow creates a table row for an event. Warning events are
+// highlighted; the table truncates the cells.
+func (v *EventView) createTableRow(event Event) StyledRow {
 	// Format count
 	countText := ""
 	if event.Count > 1 {
 		countText = fmt.Sprintf("%d", event.Count)
 	}
-	
-	return table.Row{
-		eventType,
-		reason,
-		object,
-		message,
-		timeFormatted,
+
+	row := plainRow(
+		event.Type,
+		event.Reason,
+		event.Object,
+		strings.ReplaceAll(event.Message, "\n", " "),
+		formatSince(event.LastSeen),
 		countText,
+	)
+	if event.Type == corev1.EventTypeWarning {
+		row.Style = v.theme.Warning
+		row.Cells[0].Style = v.theme.Warning.Bold(true)
+	} else {
+		row.Cells[0].Style = v.theme.Muted
 	}
+	return row
 }
 
 // updateTableColumns updates table columns based on width
 func (v *EventView) updateTableColumns() {
 	baseColumns := []table.Column{
-		{Title: "Type", Width: 6},
+		{Title: "Type", Width: 7},
 		{Title: "Reason", Width: 12},
 		{Title: "Object", Width: 22},
 		{Title: "Message", Width: 60},
-		{Title: "Time", Width: 8},
+		{Title: "Last Seen", Width: 9},
 		{Title: "Count", Width: 5},
 	}
 
 	// Adjust message column width based on available space
 	if v.width > 0 {
-		fixedWidth := 6 + 12 + 22 + 8 + 5 + 10 // Other columns + padding
+		fixedWidth := 7 + 12 + 22 + 9 + 5 + 12 // Other columns + padding
 		messageWidth := v.width - fixedWidth
 		if messageWidth > 20 {
 			baseColumns[3].Width = messageWidth
@@ -0,0 +1,173 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/malagant/fluxcli/internal/config"
+)
+
+func TestNewEvent(t *testing.T) {
+	first := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	last := first.Add(time.Hour)
+
+	event := NewEvent(corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{UID: "1"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Kustomization", Name: "apps", Namespace: "flux-system"},
+		Type:           "Warning",
+		Reason:         "ReconciliationFailed",
+		FirstTimestamp: metav1.NewTime(first),
+		LastTimestamp:  metav1.NewTime(last),
+		Count:          4,
+	})
+	assert.Equal(t, "Kustomization/apps", event.Object)
+	assert.Equal(t, first, event.FirstSeen)
+	assert.Equal(t, last, event.LastSeen)
+	assert.Equal(t, 4, event.Count)
+
+	// Events of the events.k8s.io API have an event time and a series
+	event = NewEvent(corev1.Event{
+		EventTime: metav1.NewMicroTime(first),
+		Series:    &corev1.EventSeries{Count: 7, LastObservedTime: metav1.NewMicroTime(last)},
+	})
+	assert.Equal(t, first, event.FirstSeen)
+	assert.Equal(t, last, event.LastSeen)
+	assert.Equal(t, 7, event.Count)
+}
+
+func TestParseEventFilter(t *testing.T) {
+	filter, err := ParseEventFilter([]string{"type=warning", "reason=ReconciliationFailed", "object=Kustomization/apps", "ns=flux-system"})
+	require.NoError(t, err)
+	assert.Equal(t, EventFilter{Type: "warning", Reason: "ReconciliationFailed", Object: "Kustomization/apps", Namespace: "flux-system"}, filter)
+	assert.Equal(t, "type=warning reason=ReconciliationFailed object=Kustomization/apps namespace=flux-system", filter.String())
+
+	assert.True(t, filter.Matches(Event{Type: "Warning", Reason: "reconciliationfailed", Object: "Kustomization/apps", Name: "apps", Namespace: "flux-system"}))
+	assert.False(t, filter.Matches(Event{Type: "Normal", Reason: "ReconciliationFailed", Object: "Kustomization/apps", Name: "apps", Namespace: "flux-system"}))
+	assert.True(t, EventFilter{Object: "apps"}.Matches(Event{Object: "HelmRelease/apps", Name: "apps"}))
+
+	filter, err = ParseEventFilter(nil)
+	require.NoError(t, err)
+	assert.True(t, filter.IsZero())
+
+	_, err = ParseEventFilter([]string{"type=Error"})
+	assert.EqualError(t, err, `unknown event type "Error", expected Normal or Warning`)
+	_, err = ParseEventFilter([]string{"kind=Kustomization"})
+	assert.EqualError(t, err, `unknown event filter "kind", expected type, reason, object or namespace`)
+	_, err = ParseEventFilter([]string{"Warning"})
+	assert.EqualError(t, err, `invalid event filter "Warning", expected key=value`)
+}
+
+// testEvents returns events last seen 3, 1 and 2 minutes ago
+func testEvents() []Event {
+	now := time.Now()
+	return []Event{
+		{UID: "a", Type: "Normal", Reason: "Progressing", Object: "Kustomization/apps", Name: "apps", Message: "applied", LastSeen: now.Add(-3 * time.Minute)},
+		{UID: "b", Type: "Warning", Reason: "ReconciliationFailed", Object: "Kustomization/infra", Name: "infra", Message: "kustomize build failed:\nmissing file", LastSeen: now.Add(-time.Minute), Count: 3},
+		{UID: "c", Type: "Normal", Reason: "ArtifactUpToDate", Object: "GitRepository/flux-system", Name: "flux-system", Message: "stored artifact", LastSeen: now.Add(-2 * time.Minute)},
+	}
+}
+
+func newTestEventView(t *testing.T) *EventView {
+	cfg, err := config.Load("", "", "", "")
+	require.NoError(t, err)
+	ev := NewEventView(cfg)
+	ev.SetSize(120, 20)
+	ev.SetEvents(testEvents())
+	return ev
+}
+
+func visibleUIDs(ev *EventView) string {
+	var uids strings.Builder
+	for _, event := range ev.visible {
+		uids.WriteString(event.UID)
+	}
+	return uids.String()
+}
+
+func TestEventView_SortAndFilter(t *testing.T) {
+	ev := newTestEventView(t)
+	press := func(r rune) {
+		ev, _ = ev.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+
+	// Most recently seen first
+	assert.Equal(t, "bca", visibleUIDs(ev))
+	view := ansi.Strip(ev.View())
+	assert.Contains(t, view, "Events (3)")
+	assert.Contains(t, view, "kustomize build failed: missing file")
+	assert.Contains(t, view, "1m ")
+
+	press('w')
+	assert.Equal(t, "b", visibleUIDs(ev))
+	press('w')
+	assert.Equal(t, "ca", visibleUIDs(ev))
+	assert.Contains(t, ansi.Strip(ev.View()), "Events (2 of 3) type=Normal")
+	press('w')
+	assert.Equal(t, "bca", visibleUIDs(ev))
+
+	// The selection stayed on the first Normal event; filter by its object
+	// and back
+	assert.Equal(t, "c", ev.GetSelectedEvent().UID)
+	press('e')
+	assert.Equal(t, "c", visibleUIDs(ev))
+	assert.Equal(t, "GitRepository/flux-system", ev.Filter().Object)
+	press('e')
+	assert.Equal(t, "bca", visibleUIDs(ev))
+
+	ev.SetFilter(EventFilter{Reason: "ArtifactUpToDate", Type: "Warning"})
+	assert.Contains(t, ansi.Strip(ev.View()), "No events match type=Warning reason=ArtifactUpToDate")
+}
+
+func TestEventView_DetailAndFollow(t *testing.T) {
+	ev := newTestEventView(t)
+	press := func(msg tea.KeyMsg) {
+		ev, _ = ev.Update(msg)
+	}
+	down := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}}
+
+	// The selection stays on the same event as events arrive
+	press(down)
+	assert.Equal(t, "c", ev.GetSelectedEvent().UID)
+	newer := Event{UID: "d", Type: "Normal", Object: "HelmRelease/podinfo", LastSeen: time.Now()}
+	ev.SetEvents(append(testEvents(), newer))
+	assert.Equal(t, "dbca", visibleUIDs(ev))
+	assert.Equal(t, "c", ev.GetSelectedEvent().UID)
+
+	// Following selects the newest event
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'F'}})
+	assert.True(t, ev.Following())
+	assert.Equal(t, "d", ev.GetSelectedEvent().UID)
+	assert.Contains(t, ansi.Strip(ev.View()), "following")
+	newest := Event{UID: "e", Type: "Normal", Object: "HelmRelease/podinfo", LastSeen: time.Now().Add(time.Second)}
+	ev.SetEvents(append(testEvents(), newer, newest))
+	assert.Equal(t, "e", ev.GetSelectedEvent().UID)
+
+	// Moving down stops following
+	press(down)
+	assert.False(t, ev.Following())
+
+	// The detail popup shows the full message
+	press(down)
+	assert.Equal(t, "b", ev.GetSelectedEvent().UID)
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	require.True(t, ev.ShowingDetail())
+	view := ansi.Strip(ev.View())
+	assert.Contains(t, view, "ReconciliationFailed")
+	assert.Contains(t, view, "kustomize build failed:")
+	assert.Contains(t, view, "missing file")
+	assert.Contains(t, view, "Count:      3")
+
+	// Navigation keys do not move the selection behind the popup
+	press(down)
+	assert.Equal(t, "b", ev.GetSelectedEvent().UID)
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.False(t, ev.ShowingDetail())
+}
@@ -0,0 +1,260 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/malagant/fluxcli/internal/config"
+)
+
+// KeyMap holds the key bindings of the UI actions, built from the
+// keybindings section of the configuration
+type KeyMap struct {
+	Up               key.Binding
+	Down             key.Binding
+	PageUp           key.Binding
+	PageDown         key.Binding
+	Top              key.Binding
+	Bottom           key.Binding
+	ViewTop          key.Binding
+	ViewMiddle       key.Binding
+	ViewBottom       key.Binding
+	Select           key.Binding
+	Back             key.Binding
+	SwitchView       key.Binding
+	Zoom             key.Binding
+	GrowEvents       key.Binding
+	ShrinkEvents     key.Binding
+	GitRepositories  key.Binding
+	HelmRepositories key.Binding
+	Kustomizations   key.Binding
+	HelmReleases     key.Binding
+	Diagnostics      key.Binding
+	SortName         key.Binding
+	SortStatus       key.Binding
+	SortAge          key.Binding
+	SortTransition   key.Binding
+	SortCluster      key.Binding
+	Wide             key.Binding
+	ObjectEvents     key.Binding
+	EventType        key.Binding
+	Follow           key.Binding
+	Logs             key.Binding
+	LogLevel         key.Binding
+	NextCluster      key.Binding
+	PreviousCluster  key.Binding
+	Reconcile        key.Binding
+	Suspend          key.Binding
+	Resume           key.Binding
+	Refresh          key.Binding
+	Filter           key.Binding
+	Command          key.Binding
+	Help             key.Binding
+	Quit             key.Binding
+
+	actions map[string]key.Binding // Bindings by action name, for the help screen
+}
+
+// NewKeyMap creates the key bindings configured in cfg
+func NewKeyMap(cfg *config.Config) KeyMap {
+	actions := make(map[string]key.Binding, len(config.KeyActions))
+	for _, action := range config.KeyActions {
+		keys := cfg.KeyBindings.Keys(action.Name)
+		actions[action.Name] = key.NewBinding(
+			key.WithKeys(keys...),
+			key.WithHelp(keyLabels(keys), action.Description),
+		)
+	}
+
+	return KeyMap{
+		Up:               actions["up"],
+		Down:             actions["down"],
+		PageUp:           actions["page_up"],
+		PageDown:         actions["page_down"],
+		Top:              actions["top"],
+		Bottom:           actions["bottom"],
+		ViewTop:          actions["view_top"],
+		ViewMiddle:       actions["view_middle"],
+		ViewBottom:       actions["view_bottom"],
+		Select:           actions["select"],
+		Back:             actions["back"],
+		SwitchView:       actions["switch_view"],
+		Zoom:             actions["zoom"],
+		GrowEvents:       actions["grow_events"],
+		ShrinkEvents:     actions["shrink_events"],
+		GitRepositories:  actions["git_repositories"],
+		HelmRepositories: actions["helm_repositories"],
+		Kustomizations:   actions["kustomizations"],
+		HelmReleases:     actions["helm_releases"],
+		Diagnostics:      actions["diagnostics"],
+		SortName:         actions["sort_name"],
+		SortStatus:       actions["sort_status"],
+		SortAge:          actions["sort_age"],
+		SortTransition:   actions["sort_transition"],
+		SortCluster:      actions["sort_cluster"],
+		Wide:             actions["wide"],
+		ObjectEvents:     actions["object_events"],
+		EventType:        actions["event_type"],
+		Follow:           actions["follow"],
+		Logs:             actions["logs"],
+		LogLevel:         actions["log_level"],
+		NextCluster:      actions["next_cluster"],
+		PreviousCluster:  actions["previous_cluster"],
+		Reconcile:        actions["reconcile"],
+		Suspend:          actions["suspend"],
+		Resume:           actions["resume"],
+		Refresh:          actions["refresh"],
+		Filter:           actions["filter"],
+		Command:          actions["command"],
+		Help:             actions["help"],
+		Quit:             actions["quit"],
+		actions:          actions,
+	}
+}
+
+// ShortHelp renders the footer hints
+func (k KeyMap) ShortHelp() string {
+	hints := []string{
+		fmt.Sprintf("%s help", firstKey(k.Help)),
+		fmt.Sprintf("%s navigation", pairLabel(firstKey(k.Up), firstKey(k.Down))),
+		fmt.Sprintf("%s resource types", rangeLabel(k.GitRepositories, k.HelmRepositories, k.Kustomizations, k.HelmReleases)),
+		fmt.Sprintf("%s switch panes", firstKey(k.SwitchView)),
+		fmt.Sprintf("%s command mode", firstKey(k.Command)),
+		fmt.Sprintf("%s clusters", pairLabel(firstKey(k.PreviousCluster), firstKey(k.NextCluster))),
+		fmt.Sprintf("%s quit", firstKey(k.Quit)),
+	}
+	return strings.Join(hints, " | ")
+}
+
+// FullHelp renders the help screen, grouped like config.KeyActions
+func (k KeyMap) FullHelp() string {
+	var help strings.Builder
+	group := ""
+	for _, action := range config.KeyActions {
+		if action.Group != group {
+			if group != "" {
+				help.WriteString("\n")
+			}
+			group = action.Group
+			fmt.Fprintf(&help, "%s:\n", group)
+		}
+		binding := k.actions[action.Name]
+		fmt.Fprintf(&help, "  %-16s %s\n", binding.Help().Key, binding.Help().Desc)
+	}
+
+	help.WriteString(`
+Commands (` + firstKey(k.Command) + ` to enter command mode):
+  clusters                 Show clusters and connection state
+  cluster <name>           Switch to cluster
+  suspend <n> [reason]     Suspend resource, recording an optional reason
+  resume <n>               Resume resource
+  reconcile <n>            Trigger reconciliation
+  health [state]           Show only failed, stalled, ... resources, all without a state
+  sort <col> [asc|desc]    Sort by name, status, age, transition or cluster
+  wide                     Toggle the revision, interval and suspend reason columns
+  columns [names]          Set the columns of this resource kind, defaults without names
+  events [key=value...]    Filter events by type, reason, object or namespace, all without filters
+  follow                   Toggle following new events or log lines
+  logs [name]              Show the controller log of the selected or named resource
+  doctor                   Diagnose connectivity, Flux APIs and RBAC of the current cluster`)
+
+	return help.String()
+}
+
+// navigate moves the cursor of a table for the navigation actions. It
+// reports whether msg was one of them; rows is the number of table rows.
+func (k KeyMap) navigate(t *table.Model, msg tea.KeyMsg, rows int) (bool, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch {
+	// The cursor is moved directly, as tables that are not focused ignore
+	// key messages
+	case key.Matches(msg, k.Up):
+		t.MoveUp(1)
+	case key.Matches(msg, k.Down):
+		t.MoveDown(1)
+	case key.Matches(msg, k.PageUp):
+		t.MoveUp(t.Height())
+	case key.Matches(msg, k.PageDown):
+		t.MoveDown(t.Height())
+	case key.Matches(msg, k.Top), key.Matches(msg, k.ViewTop):
+		if rows > 0 {
+			t.GotoTop()
+		}
+	case key.Matches(msg, k.Bottom), key.Matches(msg, k.ViewBottom):
+		if rows > 0 {
+			t.GotoBottom()
+		}
+	case key.Matches(msg, k.ViewMiddle):
+		if rows > 0 {
+			t.SetCursor(rows / 2)
+		}
+	default:
+		return false, nil
+	}
+
+	return true, cmd
+}
+
+// keyArrows are shown instead of the names of the arrow keys
+var keyArrows = map[string]string{
+	"up":    "↑",
+	"down":  "↓",
+	"left":  "←",
+	"right": "→",
+}
+
+// keyLabel renders a key for the footer and help screen
+func keyLabel(k string) string {
+	if arrow, ok := keyArrows[k]; ok {
+		return arrow
+	}
+	return config.KeyLabel(k)
+}
+
+// keyLabels renders all keys of a binding, e.g. "k/↑"
+func keyLabels(keys []string) string {
+	labels := make([]string, len(keys))
+	for i, k := range keys {
+		labels[i] = keyLabel(k)
+	}
+	return strings.Join(labels, "/")
+}
+
+// firstKey renders the primary key of a binding
+func firstKey(binding key.Binding) string {
+	if keys := binding.Keys(); len(keys) > 0 {
+		return keyLabel(keys[0])
+	}
+	return ""
+}
+
+// pairLabel renders two related keys, sharing their modifiers as in
+// "ctrl+k/j"
+func pairLabel(a, b string) string {
+	i := strings.LastIndex(a, "+")
+	if i > 0 && strings.HasPrefix(b, a[:i+1]) {
+		return a + "/" + b[i+1:]
+	}
+	return a + "/" + b
+}
+
+// rangeLabel renders the keys of consecutive actions, as "1-4" when they
+// are consecutive digits
+func rangeLabel(bindings ...key.Binding) string {
+	labels := make([]string, len(bindings))
+	consecutive := true
+	for i, binding := range bindings {
+		labels[i] = firstKey(binding)
+		if len(labels[i]) != 1 || (i > 0 && labels[i][0] != labels[i-1][0]+1) {
+			consecutive = false
+		}
+	}
+	if consecutive && len(labels) > 1 {
+		return labels[0] + "-" + labels[len(labels)-1]
+	}
+	return strings.Join(labels, "/")
+}
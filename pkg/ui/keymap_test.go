@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/malagant/fluxcli/internal/config"
+	"github.com/malagant/fluxcli/pkg/k8s"
+)
+
+func TestKeyMapDefaults(t *testing.T) {
+	cfg, err := config.Load("", "", "", "")
+	require.NoError(t, err)
+	cfg.KeyBindings = config.KeyBindingsConfig{}
+
+	keys := NewKeyMap(cfg)
+
+	assert.Equal(t, "? help | k/j navigation | 1-4 resource types | tab switch panes | : command mode | ctrl+k/j clusters | q quit", keys.ShortHelp())
+	assert.Contains(t, keys.FullHelp(), "  k/↑              Move up\n")
+	assert.Contains(t, keys.FullHelp(), "  enter/space      View details, switch to the selected cluster\n")
+	assert.True(t, key.Matches(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}, keys.Select))
+}
+
+func TestKeyMapCustomBindings(t *testing.T) {
+	cfg, err := config.Load("", "", "", "")
+	require.NoError(t, err)
+	cfg.KeyBindings = config.KeyBindingsConfig{
+		Up:   []string{"e"},
+		Down: []string{"n"},
+		Help: []string{"F1"},
+	}
+
+	keys := NewKeyMap(cfg)
+	assert.Equal(t, "f1 help", keys.ShortHelp()[:len("f1 help")])
+	assert.Contains(t, keys.ShortHelp(), "e/n navigation")
+	assert.Contains(t, keys.FullHelp(), "  e                Move up\n")
+
+	rv := NewResourceView(cfg)
+	rv.SetSize(120, 20)
+	rv.SetResources([]k8s.Resource{{Name: "first"}, {Name: "second"}})
+
+	rv, _ = rv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	assert.Equal(t, "second", rv.GetSelectedResource().Name)
+
+	// The default keys no longer move the cursor
+	rv, _ = rv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}})
+	assert.Equal(t, "second", rv.GetSelectedResource().Name)
+
+	rv, _ = rv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	assert.Equal(t, "first", rv.GetSelectedResource().Name)
+}
@@ -0,0 +1,144 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/malagant/fluxcli/pkg/k8s"
+)
+
+// logBatchSize is the largest number of log lines delivered in one message,
+// so that the initial tail of a log does not cause a redraw per line
+const logBatchSize = 500
+
+// LogLinesMsg delivers log lines of a stream. Lines of a stream that was
+// stopped are ignored.
+type LogLinesMsg struct {
+	Stream  int
+	Entries []k8s.LogEntry
+	Done    bool // The stream was closed
+
+	lines <-chan k8s.LogEntry
+}
+
+// openLogs streams the log of the controller that reconciles an object into
+// the log view
+func (m *AppModel) openLogs(kind, name, namespace string) tea.Cmd {
+	m.stopLogs()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lines, err := m.manager.StreamControllerLogs(ctx, kind)
+	if err != nil {
+		cancel()
+		m.errorMessage = fmt.Sprintf("Failed to show logs of %s: %v", name, err)
+		return tea.Tick(3*time.Second, func(time.Time) tea.Msg { return ClearStatusMsg{} })
+	}
+
+	m.stopLogStream = cancel
+	m.logView.Open(kind, name, namespace)
+	m.currentView = ViewLogs
+	return waitForLogLines(m.logStream, lines)
+}
+
+// stopLogs ends the current log stream. Lines it already sent are ignored.
+func (m *AppModel) stopLogs() {
+	if m.stopLogStream != nil {
+		m.stopLogStream()
+		m.stopLogStream = nil
+	}
+	m.logStream++
+}
+
+// waitForLogLines waits for the next lines of a log stream. Lines that
+// arrived meanwhile are delivered together.
+func waitForLogLines(stream int, lines <-chan k8s.LogEntry) tea.Cmd {
+	return func() tea.Msg {
+		entry, ok := <-lines
+		if !ok {
+			return LogLinesMsg{Stream: stream, Done: true}
+		}
+
+		msg := LogLinesMsg{Stream: stream, Entries: []k8s.LogEntry{entry}, lines: lines}
+		for len(msg.Entries) < logBatchSize {
+			select {
+			case entry, ok := <-lines:
+				if !ok {
+					msg.Done = true
+					return msg
+				}
+				msg.Entries = append(msg.Entries, entry)
+			default:
+				return msg
+			}
+		}
+		return msg
+	}
+}
+
+// handleLogLines adds the lines of the current log stream to the log view
+// and waits for more
+func (m *AppModel) handleLogLines(msg LogLinesMsg) tea.Cmd {
+	if msg.Stream != m.logStream {
+		return nil
+	}
+	m.logView.AddEntries(msg.Entries)
+	if msg.Done {
+		m.logView.SetEnded()
+		m.stopLogStream = nil
+		return nil
+	}
+	return waitForLogLines(msg.Stream, msg.lines)
+}
+
+// logsCommand shows the log of the named resource of the current type, or
+// of the selected one
+func (m *AppModel) logsCommand(args []string) tea.Cmd {
+	selected := m.resourceView.GetSelectedResource()
+	if len(args) > 0 {
+		selected = nil
+		for _, resource := range m.state.Resources[m.state.CurrentCluster][m.state.CurrentResource] {
+			if resource.Name == args[0] {
+				selected = &resource
+				break
+			}
+		}
+		if selected == nil {
+			m.errorMessage = fmt.Sprintf("%s %s not found", m.state.CurrentResource, args[0])
+			return tea.Tick(3*time.Second, func(time.Time) tea.Msg { return ClearStatusMsg{} })
+		}
+	}
+	if selected == nil {
+		m.errorMessage = "Usage: logs [name]"
+		return tea.Tick(3*time.Second, func(time.Time) tea.Msg { return ClearStatusMsg{} })
+	}
+	return m.openLogs(string(selected.Type), selected.Name, selected.Namespace)
+}
+
+// handleSearchMode handles keyboard input while searching the log view. The
+// search is applied while typing; cancelling restores the previous one.
+func (m *AppModel) handleSearchMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		m.searchMode = false
+
+	case "esc":
+		m.searchMode = false
+		m.logView.SetSearch(m.searchPrevious)
+
+	case "backspace":
+		if len(m.searchInput) > 0 {
+			m.searchInput = m.searchInput[:len(m.searchInput)-1]
+			m.logView.SetSearch(m.searchInput)
+		}
+
+	default:
+		if len(msg.String()) == 1 {
+			m.searchInput += msg.String()
+			m.logView.SetSearch(m.searchInput)
+		}
+	}
+
+	return m, nil
+}
@@ -0,0 +1,441 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/malagant/fluxcli/internal/config"
+	"github.com/malagant/fluxcli/pkg/k8s"
+)
+
+// LogView displays the log of the Flux controller that reconciles an
+// object, oldest line first. By default only the lines about the object are
+// shown.
+type LogView struct {
+	config     *config.Config
+	table      styledTable
+	lines      []logLine // Received lines, oldest first
+	visible    []logLine // Lines passing the filters
+	nextSeq    int
+	kind       string // Object whose lines are shown
+	name       string
+	namespace  string
+	controller string
+	allObjects bool   // Show the lines of every object
+	level      string // Lowest level shown, all levels when empty
+	search     string
+	follow     bool // Keep the newest line selected
+	detail     bool // Show the selected line in full
+	ended      bool // The log stream was closed
+	keys       KeyMap
+	theme      Theme
+	width      int
+	height     int
+}
+
+// logLine is a received log entry with its arrival number, which keeps the
+// selection on the same line as lines arrive and old ones are dropped
+type logLine struct {
+	seq int
+	k8s.LogEntry
+}
+
+// maxLogLines is the number of lines kept, older lines are dropped
+const maxLogLines = 5000
+
+// logViewChrome is the height of the log view besides its rows: the title
+// and the table header
+const logViewChrome = 3
+
+// NewLogView creates a new log view
+func NewLogView(cfg *config.Config) *LogView {
+	t := newStyledTable(
+		table.WithColumns(logColumns(0)),
+		table.WithFocused(true),
+	)
+
+	theme := NewTheme(cfg)
+	t.SetStyles(theme.Table)
+
+	return &LogView{
+		config: cfg,
+		table:  t,
+		follow: true,
+		keys:   NewKeyMap(cfg),
+		theme:  theme,
+	}
+}
+
+// Init initializes the log view
+func (v *LogView) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages for the log view
+func (v *LogView) Update(msg tea.Msg) (*LogView, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if v.detail {
+			// The detail popup is closed by select or back
+			if key.Matches(msg, v.keys.Select, v.keys.Back) {
+				v.detail = false
+			}
+			return v, nil
+		}
+
+		if handled, navCmd := v.keys.navigate(&v.table.Model, msg, len(v.visible)); handled {
+			cmd = navCmd
+			// Moving away from the newest line stops following
+			v.follow = v.follow && v.table.Cursor() == len(v.visible)-1
+		} else if key.Matches(msg, v.keys.Select) {
+			v.detail = v.GetSelectedEntry() != nil
+		} else if key.Matches(msg, v.keys.LogLevel) {
+			v.SetLevel(nextLogLevel(v.level))
+		} else if key.Matches(msg, v.keys.ObjectEvents) {
+			v.allObjects = !v.allObjects
+			v.updateTable()
+		} else if key.Matches(msg, v.keys.Follow) {
+			v.SetFollow(!v.follow)
+		}
+	}
+
+	return v, cmd
+}
+
+// nextLogLevel cycles the level filter from all lines to info and errors,
+// errors only and back
+func nextLogLevel(level string) string {
+	switch level {
+	case "":
+		return k8s.LogLevelInfo
+	case k8s.LogLevelInfo:
+		return k8s.LogLevelError
+	default:
+		return ""
+	}
+}
+
+// Open shows the log of the controller that reconciles an object and drops
+// the lines of the previous one. The filters other than the search are
+// reset.
+func (v *LogView) Open(kind, name, namespace string) {
+	v.kind, v.name, v.namespace = kind, name, namespace
+	v.controller, _ = k8s.ControllerForKind(kind)
+	v.lines = nil
+	v.allObjects = false
+	v.level = ""
+	v.follow = true
+	v.detail = false
+	v.ended = false
+	v.updateTable()
+}
+
+// Object returns the kind, name and namespace of the object shown
+func (v *LogView) Object() (kind, name, namespace string) {
+	return v.kind, v.name, v.namespace
+}
+
+// AddEntries appends received log entries, dropping the oldest lines
+// beyond maxLogLines
+func (v *LogView) AddEntries(entries []k8s.LogEntry) {
+	for _, entry := range entries {
+		v.lines = append(v.lines, logLine{seq: v.nextSeq, LogEntry: entry})
+		v.nextSeq++
+	}
+	if len(v.lines) > maxLogLines {
+		v.lines = slices.Clone(v.lines[len(v.lines)-maxLogLines:])
+	}
+	v.updateTable()
+}
+
+// SetEnded records that no more lines will arrive
+func (v *LogView) SetEnded() {
+	v.ended = true
+}
+
+// SetLevel shows only lines of level or above, all lines when empty
+func (v *LogView) SetLevel(level string) {
+	v.level = level
+	v.updateTable()
+}
+
+// Level returns the lowest level shown
+func (v *LogView) Level() string {
+	return v.level
+}
+
+// SetSearch shows only lines containing text, ignoring case
+func (v *LogView) SetSearch(text string) {
+	v.search = text
+	v.updateTable()
+}
+
+// Search returns the text searched for
+func (v *LogView) Search() string {
+	return v.search
+}
+
+// SetFollow keeps the newest line selected as lines arrive
+func (v *LogView) SetFollow(follow bool) {
+	v.follow = follow
+	if follow && len(v.visible) > 0 {
+		v.table.GotoBottom()
+	}
+}
+
+// Following reports whether follow mode is on
+func (v *LogView) Following() bool {
+	return v.follow
+}
+
+// ShowingDetail reports whether the detail popup is open
+func (v *LogView) ShowingDetail() bool {
+	return v.detail
+}
+
+// GetSelectedEntry returns the currently selected log entry
+func (v *LogView) GetSelectedEntry() *k8s.LogEntry {
+	cursor := v.table.Cursor()
+	if cursor >= 0 && cursor < len(v.visible) {
+		return &v.visible[cursor].LogEntry
+	}
+	return nil
+}
+
+// matches reports whether a line passes the object, level and search
+// filters
+func (v *LogView) matches(line logLine) bool {
+	if !v.allObjects && !line.Matches(v.kind, v.name, v.namespace) {
+		return false
+	}
+	if v.level != "" && k8s.LogLevelSeverity(line.Level) < k8s.LogLevelSeverity(v.level) {
+		return false
+	}
+	if v.search == "" {
+		return true
+	}
+	search := strings.ToLower(v.search)
+	if strings.Contains(strings.ToLower(line.Message), search) || strings.Contains(strings.ToLower(line.Error), search) {
+		return true
+	}
+	for _, value := range line.Fields {
+		if strings.Contains(strings.ToLower(value), search) {
+			return true
+		}
+	}
+	return false
+}
+
+// View renders the log view
+func (v *LogView) View() string {
+	if v.detail {
+		if selected := v.GetSelectedEntry(); selected != nil {
+			return v.detailView(*selected)
+		}
+	}
+
+	title := v.titleView()
+	if len(v.visible) == 0 {
+		message := fmt.Sprintf("Waiting for %s log lines", v.controller)
+		if len(v.lines) > 0 || v.ended {
+			message = "No log lines match"
+		}
+		emptyMsg := v.theme.Muted.Render(message)
+
+		box := lipgloss.NewStyle().
+			Border(lipgloss.NormalBorder()).
+			BorderForeground(v.theme.Border).
+			Height(max(v.height-logViewChrome, 1)).
+			Padding(1, 2).
+			Render(emptyMsg)
+
+		return lipgloss.JoinVertical(lipgloss.Left, title, box)
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, title, v.table.View())
+}
+
+// titleView renders the controller, the object and the filters
+func (v *LogView) titleView() string {
+	object := fmt.Sprintf("%s %s/%s", v.kind, v.namespace, v.name)
+	if v.allObjects {
+		object = "all objects"
+	}
+	title := fmt.Sprintf("Logs of %s for %s (%d of %d)", v.controller, object, len(v.visible), len(v.lines))
+
+	var filters []string
+	if v.level != "" {
+		filters = append(filters, fmt.Sprintf("level>=%s", v.level))
+	}
+	if v.search != "" {
+		filters = append(filters, fmt.Sprintf("search=%q", v.search))
+	}
+	if len(filters) > 0 {
+		title += " " + strings.Join(filters, " ")
+	}
+
+	switch {
+	case v.ended:
+		title += " · ended"
+	case v.follow:
+		title += " · following"
+	}
+	return v.theme.Title.Render(title)
+}
+
+// detailView renders a log line with its error and other fields
+func (v *LogView) detailView(entry k8s.LogEntry) string {
+	label := lipgloss.NewStyle().Bold(true)
+	var fields strings.Builder
+	for _, field := range []struct{ name, value string }{
+		{"Time", formatEventTime(entry.Time)},
+		{"Level", v.levelStyle(entry.Level).Render(entry.Level)},
+		{"Controller", entry.Controller},
+		{"Pod", entry.Pod},
+		{"Object", logObject(entry)},
+	} {
+		fmt.Fprintf(&fields, "%s %s\n", label.Render(fmt.Sprintf("%-11s", field.name+":")), field.value)
+	}
+
+	keys := make([]string, 0, len(entry.Fields))
+	for name := range entry.Fields {
+		keys = append(keys, name)
+	}
+	slices.Sort(keys)
+	for _, name := range keys {
+		fmt.Fprintf(&fields, "%s %s\n", label.Render(fmt.Sprintf("%-11s", name+":")), entry.Fields[name])
+	}
+
+	width := max(v.width-4, 20)
+	text := entry.Message
+	if entry.Error != "" {
+		text += "\n\n" + v.theme.Error.Render(entry.Error)
+	}
+	message := lipgloss.NewStyle().Width(width - 2).Render(text)
+	hint := v.theme.Hint.Render(fmt.Sprintf("%s close", firstKey(v.keys.Back)))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(v.theme.Border).
+		Padding(0, 1).
+		Width(width).
+		Render(fields.String() + "\n" + message + "\n\n" + hint)
+}
+
+// logObject renders the object of a log entry as kind namespace/name
+func logObject(entry k8s.LogEntry) string {
+	if entry.Name == "" {
+		return ""
+	}
+	return strings.TrimSpace(fmt.Sprintf("%s %s/%s", entry.Kind, entry.Namespace, entry.Name))
+}
+
+// levelStyle returns the style of a log level
+func (v *LogView) levelStyle(level string) lipgloss.Style {
+	switch k8s.LogLevelSeverity(level) {
+	case 0:
+		return v.theme.Muted
+	case 2:
+		return v.theme.Error.Bold(true)
+	default:
+		return lipgloss.NewStyle()
+	}
+}
+
+// SetConfig applies a reloaded configuration
+func (v *LogView) SetConfig(cfg *config.Config) {
+	v.config = cfg
+	v.keys = NewKeyMap(cfg)
+	v.theme = NewTheme(cfg)
+	v.table.SetStyles(v.theme.Table)
+	v.updateTable()
+}
+
+// SetSize sets the view dimensions
+func (v *LogView) SetSize(width, height int) {
+	v.width = width
+	v.height = height
+	v.table.SetHeight(height - logViewChrome)
+	v.table.SetColumns(logColumns(width))
+}
+
+// updateTable filters the lines. The selection stays on the same line
+// unless following, which selects the newest one.
+func (v *LogView) updateTable() {
+	selected := -1
+	if cursor := v.table.Cursor(); cursor >= 0 && cursor < len(v.visible) {
+		selected = v.visible[cursor].seq
+	}
+
+	v.visible = v.visible[:0]
+	for _, line := range v.lines {
+		if v.matches(line) {
+			v.visible = append(v.visible, line)
+		}
+	}
+
+	rows := make([]StyledRow, 0, len(v.visible))
+	cursor := len(v.visible) - 1
+	for i, line := range v.visible {
+		// The open detail popup keeps its line even when following
+		if (!v.follow || v.detail) && line.seq == selected {
+			cursor = i
+		}
+		rows = append(rows, v.createTableRow(line.LogEntry))
+	}
+
+	v.table.SetStyledRows(rows)
+	v.table.SetCursor(max(cursor, 0))
+}
+
+// createTableRow creates a table row for a log line. Errors are
+// highlighted and debug lines muted.
+func (v *LogView) createTableRow(entry k8s.LogEntry) StyledRow {
+	timestamp := ""
+	if !entry.Time.IsZero() {
+		timestamp = entry.Time.Local().Format("15:04:05")
+	}
+	message := entry.Message
+	if entry.Error != "" {
+		message = fmt.Sprintf("%s: %s", message, entry.Error)
+	}
+
+	row := plainRow(
+		timestamp,
+		entry.Level,
+		logObject(entry),
+		strings.ReplaceAll(message, "\n", " "),
+	)
+	row.Cells[1].Style = v.levelStyle(entry.Level)
+	switch k8s.LogLevelSeverity(entry.Level) {
+	case 0:
+		row.Style = v.theme.Muted
+	case 2:
+		row.Style = v.theme.Error
+	}
+	return row
+}
+
+// logColumns returns the columns of the log table, the message taking the
+// width left by the others
+func logColumns(width int) []table.Column {
+	columns := []table.Column{
+		{Title: "Time", Width: 8},
+		{Title: "Level", Width: 5},
+		{Title: "Object", Width: 30},
+		{Title: "Message", Width: 60},
+	}
+	// Each cell is padded by one character on both sides
+	fixedWidth := 8 + 5 + 30 + 4*2
+	if messageWidth := width - fixedWidth; messageWidth > 20 {
+		columns[3].Width = messageWidth
+	}
+	return columns
+}
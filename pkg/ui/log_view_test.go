@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/malagant/fluxcli/internal/config"
+	"github.com/malagant/fluxcli/pkg/k8s"
+)
+
+var testLogEntries = []k8s.LogEntry{
+	{Level: "info", Message: "starting manager"},
+	{Level: "info", Message: "stored artifact", Kind: "GitRepository", Name: "podinfo", Namespace: "apps"},
+	{Level: "debug", Message: "no changes", Kind: "GitRepository", Name: "podinfo", Namespace: "apps"},
+	{Level: "error", Message: "Reconciler error", Error: "authentication required", Kind: "GitRepository", Name: "podinfo", Namespace: "apps"},
+	{Level: "error", Message: "Reconciler error", Error: "timeout", Kind: "GitRepository", Name: "flux-system", Namespace: "flux-system"},
+}
+
+func visibleMessages(v *LogView) []string {
+	messages := make([]string, len(v.visible))
+	for i, line := range v.visible {
+		messages[i] = line.Message
+		if line.Error != "" {
+			messages[i] += ": " + line.Error
+		}
+	}
+	return messages
+}
+
+func TestLogView_Filters(t *testing.T) {
+	cfg, err := config.Load("", "", "", "")
+	require.NoError(t, err)
+
+	v := NewLogView(cfg)
+	v.SetSize(160, 30)
+	v.Open("GitRepository", "podinfo", "apps")
+	v.AddEntries(testLogEntries)
+
+	// Only the lines of the object, following the newest one
+	assert.Equal(t, []string{"stored artifact", "no changes", "Reconciler error: authentication required"}, visibleMessages(v))
+	assert.Equal(t, "authentication required", v.GetSelectedEntry().Error)
+	assert.Contains(t, ansi.Strip(v.View()), "Logs of source-controller for GitRepository apps/podinfo (3 of 5)")
+
+	v.SetLevel(nextLogLevel(v.Level()))
+	assert.Equal(t, []string{"stored artifact", "Reconciler error: authentication required"}, visibleMessages(v))
+	v.SetLevel(nextLogLevel(v.Level()))
+	assert.Equal(t, []string{"Reconciler error: authentication required"}, visibleMessages(v))
+	v.SetLevel(nextLogLevel(v.Level()))
+	assert.Len(t, v.visible, 3)
+
+	// The search matches messages and errors of every object
+	v.allObjects = true
+	v.SetSearch("TIMEOUT")
+	assert.Equal(t, []string{"Reconciler error: timeout"}, visibleMessages(v))
+	assert.Contains(t, ansi.Strip(v.View()), `for all objects (1 of 5) search="TIMEOUT"`)
+	v.SetSearch("")
+	assert.Len(t, v.visible, 5)
+}
+
+func TestLogView_FollowAndSelection(t *testing.T) {
+	cfg, err := config.Load("", "", "", "")
+	require.NoError(t, err)
+
+	v := NewLogView(cfg)
+	v.SetSize(160, 30)
+	v.Open("GitRepository", "podinfo", "apps")
+	v.AddEntries(testLogEntries[:3])
+	assert.Equal(t, "no changes", v.GetSelectedEntry().Message)
+
+	// Moving up stops following and keeps the selected line
+	v.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}})
+	assert.False(t, v.Following())
+	v.AddEntries(testLogEntries[3:])
+	assert.Equal(t, "stored artifact", v.GetSelectedEntry().Message)
+
+	v.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'F'}})
+	assert.True(t, v.Following())
+	assert.Equal(t, "authentication required", v.GetSelectedEntry().Error)
+
+	// Opening another object drops the lines
+	v.Open("Kustomization", "apps", "flux-system")
+	assert.Empty(t, v.visible)
+	assert.Contains(t, ansi.Strip(v.View()), "Waiting for kustomize-controller log lines")
+}
+
+func TestApp_LogViewSearch(t *testing.T) {
+	app := newTestApp(t)
+	app.logView.Open("GitRepository", "podinfo", "apps")
+	app.currentView = ViewLogs
+
+	// Lines of a stopped stream are ignored
+	app.Update(LogLinesMsg{Stream: app.logStream - 1, Entries: testLogEntries})
+	assert.Empty(t, app.logView.visible)
+	app.Update(LogLinesMsg{Stream: app.logStream, Entries: testLogEntries, Done: true})
+	assert.Len(t, app.logView.visible, 3)
+	assert.Contains(t, ansi.Strip(app.View()), "· ended")
+
+	press(app, "/", "a", "u", "t", "h")
+	assert.True(t, app.searchMode)
+	assert.Contains(t, ansi.Strip(app.View()), "/auth")
+	assert.Equal(t, []string{"Reconciler error: authentication required"}, visibleMessages(app.logView))
+	press(app, "enter")
+	assert.False(t, app.searchMode)
+	assert.Equal(t, "auth", app.logView.Search())
+
+	// Cancelling restores the previous search
+	press(app, "/", "x", "esc")
+	assert.Equal(t, "auth", app.logView.Search())
+
+	press(app, "esc")
+	assert.Equal(t, ViewResources, app.currentView)
+}
@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Pane identifies one of the panes of the main view
+type Pane int
+
+const (
+	PaneResources Pane = iota // Resource table on top
+	PaneEvents                // Events of the selected resource below
+)
+
+// reservedHeight is the height of the header and footer
+const reservedHeight = 4
+
+// eventsPaneChrome is the height of the events pane besides its rows: the
+// title and the table header
+const eventsPaneChrome = 3
+
+// minResourceRows keeps some resource rows visible when the events pane grows
+const minResourceRows = 3
+
+// contentHeight returns the height available to the panes
+func (m *AppModel) contentHeight() int {
+	return max(m.height-reservedHeight, 1)
+}
+
+// eventsPaneHeight returns the height of the events pane in the split
+// layout, zero when it is hidden
+func (m *AppModel) eventsPaneHeight() int {
+	rows := min(m.eventRows, m.maxEventRows())
+	if rows <= 0 || !m.config.Defaults.EventsEnabled {
+		return 0
+	}
+	return rows + eventsPaneChrome
+}
+
+// maxEventRows returns the largest number of event rows that leaves room
+// for the resource table
+func (m *AppModel) maxEventRows() int {
+	return max(m.contentHeight()-eventsPaneChrome-minResourceRows-2, 0)
+}
+
+// layout sizes the child views to the window
+func (m *AppModel) layout() {
+	content := m.contentHeight()
+
+	switch {
+	case m.zoomed && m.focus == PaneEvents:
+		m.eventView.SetSize(m.width, content)
+	case m.zoomed:
+		m.resourceView.SetSize(m.width, content)
+	default:
+		events := m.eventsPaneHeight()
+		m.resourceView.SetSize(m.width, content-events)
+		m.eventView.SetSize(m.width, events)
+	}
+	m.clusterView.SetSize(m.width, content)
+	m.logView.SetSize(m.width, content)
+	m.diagnosticsView.SetSize(m.width, content)
+}
+
+// focusPane moves the focus to a pane. An events pane that is hidden in the
+// split layout is shown zoomed.
+func (m *AppModel) focusPane(pane Pane) {
+	if pane != m.focus {
+		// Zooming applies to the focused pane only
+		m.zoomed = false
+	}
+	if pane == PaneEvents && m.eventsPaneHeight() == 0 {
+		m.zoomed = true
+	}
+	m.focus = pane
+	m.stopLogs()
+	m.currentView = ViewResources
+	m.resourceView.SetFocused(pane == PaneResources)
+	m.eventView.SetFocused(pane == PaneEvents)
+	m.layout()
+}
+
+// toggleZoom maximizes the focused pane or restores the split layout
+func (m *AppModel) toggleZoom() {
+	if m.zoomed && m.focus == PaneEvents && m.eventsPaneHeight() == 0 {
+		// The events pane cannot be shown split, go back to the resources
+		m.focusPane(PaneResources)
+		return
+	}
+	m.zoomed = !m.zoomed
+	m.layout()
+}
+
+// resizeEvents grows or shrinks the events pane by delta rows
+func (m *AppModel) resizeEvents(delta int) {
+	m.eventRows = max(min(m.eventRows, m.maxEventRows())+delta, 0)
+	m.eventRows = min(m.eventRows, m.maxEventRows())
+	if m.eventRows == 0 && m.focus == PaneEvents && !m.zoomed {
+		m.focusPane(PaneResources)
+		return
+	}
+	m.layout()
+}
+
+// syncEvents shows the events of the selected resource in the events pane
+// while it is linked to the selection
+func (m *AppModel) syncEvents() {
+	if !m.eventsLinked {
+		return
+	}
+	filter := m.eventView.Filter()
+	filter.Object, filter.Namespace = "", ""
+	if selected := m.resourceView.GetSelectedResource(); selected != nil {
+		filter.Object = fmt.Sprintf("%s/%s", selected.Type, selected.Name)
+		filter.Namespace = selected.Namespace
+	}
+	if filter != m.eventView.Filter() {
+		m.eventView.SetFilter(filter)
+	}
+}
+
+// renderPanes renders the resource and events panes, or the zoomed one
+func (m *AppModel) renderPanes() string {
+	resources := m.resourceView.View()
+	if notice := m.renderClusterUnavailable(); notice != "" {
+		resources = notice
+	}
+
+	switch {
+	case m.zoomed && m.focus == PaneEvents, m.eventView.ShowingDetail():
+		return m.eventView.View()
+	case m.zoomed || m.eventsPaneHeight() == 0:
+		return resources
+	}
+
+	// Both panes keep their height when they have few rows
+	resources = lipgloss.NewStyle().Height(m.contentHeight() - m.eventsPaneHeight()).Render(resources)
+	events := lipgloss.NewStyle().Height(m.eventsPaneHeight()).Render(m.eventView.View())
+	return lipgloss.JoinVertical(lipgloss.Left, resources, events)
+}
@@ -0,0 +1,130 @@
+package ui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/malagant/fluxcli/internal/config"
+	"github.com/malagant/fluxcli/pkg/k8s"
+)
+
+func newTestApp(t *testing.T) *AppModel {
+	cfg, err := config.Load("", "", "", "")
+	require.NoError(t, err)
+	// Operations the tests try must not reach the user's audit log
+	cfg.Audit.Path = filepath.Join(t.TempDir(), "audit.log")
+
+	app := NewApp(cfg)
+	app.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	app.Update(ResourceUpdateMsg{
+		Cluster: app.state.CurrentCluster,
+		Type:    k8s.ResourceTypeGitRepository,
+		Resources: []k8s.Resource{
+			{Type: k8s.ResourceTypeGitRepository, Name: "flux-system", Namespace: "flux-system"},
+			{Type: k8s.ResourceTypeGitRepository, Name: "podinfo", Namespace: "apps"},
+		},
+	})
+	app.Update(EventUpdateMsg{
+		Cluster: app.state.CurrentCluster,
+		Events: []Event{
+			{UID: "1", Type: "Normal", Object: "GitRepository/flux-system", Name: "flux-system", Namespace: "flux-system", Message: "stored artifact", LastSeen: time.Now()},
+			{UID: "2", Type: "Warning", Object: "GitRepository/podinfo", Name: "podinfo", Namespace: "apps", Message: "authentication required", LastSeen: time.Now()},
+			{UID: "3", Type: "Normal", Object: "GitRepository/podinfo", Name: "podinfo", Namespace: "default", Message: "other namespace", LastSeen: time.Now()},
+		},
+	})
+	return app
+}
+
+func press(app *AppModel, keys ...string) {
+	for _, k := range keys {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		switch k {
+		case "tab":
+			msg = tea.KeyMsg{Type: tea.KeyTab}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		}
+		app.Update(msg)
+	}
+}
+
+func TestPanes_EventsFollowSelection(t *testing.T) {
+	app := newTestApp(t)
+
+	view := ansi.Strip(app.View())
+	assert.Equal(t, 38, strings.Count(view, "\n")+1) // Header, panes and footer
+	assert.Contains(t, view, "podinfo")
+	assert.Contains(t, view, "Events (1 of 3)")
+	assert.Contains(t, view, "stored artifact")
+	assert.NotContains(t, view, "authentication required")
+
+	press(app, "j")
+	view = ansi.Strip(app.View())
+	assert.Contains(t, view, "authentication required")
+	assert.NotContains(t, view, "other namespace")
+	assert.NotContains(t, view, "stored artifact")
+
+	// e in the events pane unlinks it and shows the events of every object
+	press(app, "tab")
+	assert.Equal(t, PaneEvents, app.focus)
+	press(app, "e")
+	press(app, "tab", "k")
+	assert.Contains(t, ansi.Strip(app.View()), "Events (3)")
+
+	// e in the resources pane links it again
+	press(app, "e")
+	assert.Equal(t, PaneEvents, app.focus)
+	assert.Contains(t, ansi.Strip(app.View()), "stored artifact")
+	assert.NotContains(t, ansi.Strip(app.View()), "authentication required")
+}
+
+func TestPanes_ZoomAndResize(t *testing.T) {
+	app := newTestApp(t)
+	height := func() int { return app.eventsPaneHeight() }
+
+	assert.Equal(t, app.config.UI.PaneEventsHeight+eventsPaneChrome, height())
+	press(app, "+", "+")
+	assert.Equal(t, app.config.UI.PaneEventsHeight+2+eventsPaneChrome, height())
+	assert.Equal(t, 38, strings.Count(app.View(), "\n")+1)
+
+	// The focused pane fills the window when zoomed
+	press(app, "z")
+	assert.NotContains(t, ansi.Strip(app.View()), "Events (")
+	press(app, "z", "tab", "z")
+	view := ansi.Strip(app.View())
+	assert.Contains(t, view, "Events (")
+	assert.NotContains(t, view, "flux-system/podinfo")
+
+	// Switching panes restores the split
+	press(app, "tab")
+	assert.False(t, app.zoomed)
+
+	// Shrinking the pane away hides it, focusing it shows it zoomed
+	for range app.config.UI.PaneEventsHeight + 2 {
+		press(app, "-")
+	}
+	assert.Equal(t, 0, height())
+	assert.NotContains(t, ansi.Strip(app.View()), "Events (")
+	press(app, "tab")
+	assert.True(t, app.zoomed)
+	assert.Contains(t, ansi.Strip(app.View()), "Events (")
+	press(app, "z")
+	assert.Equal(t, PaneResources, app.focus)
+	assert.False(t, app.zoomed)
+
+	// Growing is limited by the window height
+	for range 100 {
+		press(app, "+")
+	}
+	assert.Equal(t, app.maxEventRows()+eventsPaneChrome, height())
+	assert.Equal(t, 38, strings.Count(app.View(), "\n")+1)
+}
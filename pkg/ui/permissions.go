@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/malagant/fluxcli/pkg/core"
+	"github.com/malagant/fluxcli/pkg/k8s"
+)
+
+// operationDenied explains why the user may not perform an operation on a
+// resource, empty when it is allowed or not known to be denied
+func (m *AppModel) operationDenied(operation string, resource k8s.Resource) string {
+	if m.resourceView.OperationPermission(resource) != k8s.PermissionDenied {
+		return ""
+	}
+	fluxResource, _ := k8s.FluxResourceForKind(string(resource.Type))
+	err := &core.OperationDeniedError{Operation: operation, Resource: fluxResource, Namespace: resource.Namespace}
+	return fmt.Sprintf("Cannot %s %s: %v (see :doctor)", operation, resource.Name, err)
+}
+
+// findResource returns the resource of the current type with a name, nil
+// when it is not shown
+func (m *AppModel) findResource(name string) *k8s.Resource {
+	for _, resource := range m.state.Resources[m.state.CurrentCluster][m.state.CurrentResource] {
+		if resource.Name == name {
+			return &resource
+		}
+	}
+	return nil
+}
+
+// readOnlyHint explains that the operation keys are disabled for the
+// current cluster or the selected resource, empty when they are not
+func (m *AppModel) readOnlyHint() string {
+	if m.currentView != ViewResources || m.focus != PaneResources {
+		return ""
+	}
+	if m.manager.IsReadOnly(m.state.CurrentCluster) {
+		return fmt.Sprintf("Read-only: cluster %s does not allow changes, %s/%s/%s are disabled",
+			m.state.CurrentCluster, firstKey(m.keys.Reconcile), firstKey(m.keys.Suspend), firstKey(m.keys.Resume))
+	}
+	selected := m.resourceView.GetSelectedResource()
+	if selected == nil || m.resourceView.OperationPermission(*selected) != k8s.PermissionDenied {
+		return ""
+	}
+	return fmt.Sprintf("Read-only: your role may not change %s objects in %s, %s/%s/%s are disabled",
+		selected.Type, selected.Namespace,
+		firstKey(m.keys.Reconcile), firstKey(m.keys.Suspend), firstKey(m.keys.Resume))
+}
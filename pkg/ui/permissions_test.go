@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/malagant/fluxcli/pkg/k8s"
+)
+
+// assertMessageStays checks that cmd does not clear the status or error
+// message before it can be read
+func assertMessageStays(t *testing.T, cmd tea.Cmd) {
+	t.Helper()
+	require.NotNil(t, cmd)
+
+	cleared := make(chan tea.Msg, 1)
+	go func() { cleared <- cmd() }()
+	select {
+	case msg := <-cleared:
+		t.Fatalf("the message is cleared at once with %T", msg)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestApp_ForbiddenOperations(t *testing.T) {
+	app := newTestApp(t)
+	// The user may only operate on objects in flux-system
+	app.resourceView.SetPermission(func(resource k8s.Resource) k8s.Permission {
+		if resource.Namespace == "flux-system" {
+			return k8s.PermissionAllowed
+		}
+		return k8s.PermissionDenied
+	})
+
+	assert.NotContains(t, ansi.Strip(app.View()), "Read-only")
+
+	press(app, "j")
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("P")})
+	assertMessageStays(t, cmd)
+	assert.Equal(t, "Cannot suspend podinfo: your role may not get and update gitrepositories.source.toolkit.fluxcd.io in namespace apps, which suspend needs (see :doctor)", app.errorMessage)
+
+	app.errorMessage = ""
+	assert.Contains(t, ansi.Strip(app.View()), "Read-only: your role may not change GitRepository objects in apps, f/P/U are disabled")
+
+	// Command mode explains the same before trying
+	app.executeCommand("reconcile podinfo")
+	assert.Contains(t, app.errorMessage, "Cannot reconcile podinfo")
+}
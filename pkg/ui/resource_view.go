@@ -2,11 +2,13 @@ package ui
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 	"github.com/malagant/fluxcli/internal/config"
 	"github.com/malagant/fluxcli/pkg/k8s"
 )
@@ -14,46 +16,42 @@ import (
 // ResourceView displays FluxCD resources in a table
 type ResourceView struct {
 	config       *config.Config
-	table        table.Model
+	table        styledTable
 	resources    []k8s.Resource
+	visible      []k8s.Resource     // Resources passing the health filter
+	healthFilter []k8s.HealthState
 	resourceType k8s.ResourceType
+	columns      []resourceColumn // Columns that fit the width
+	sortColumn   string
+	sortDesc     bool
+	wide         bool
+	nsPrefix     bool // Show namespace/name in the name column
+	permission   func(k8s.Resource) k8s.Permission // Whether operations on a resource are allowed
+	keys         KeyMap
+	theme        Theme
 	width        int
 	height       int
 }
 
 // NewResourceView creates a new resource view
 func NewResourceView(cfg *config.Config) *ResourceView {
-	columns := []table.Column{
-		{Title: "Name", Width: cfg.UI.ColumnsName},
-		{Title: "Ready", Width: 8},
-		{Title: "Status", Width: cfg.UI.ColumnsStatus},
-		{Title: "Age", Width: 10},
-		{Title: "Message", Width: 40},
-	}
-
-	t := table.New(
-		table.WithColumns(columns),
+	t := newStyledTable(
 		table.WithFocused(true),
 		table.WithHeight(10),
 	)
 
-	s := table.DefaultStyles()
-	s.Header = s.Header.
-		BorderStyle(lipgloss.NormalBorder()).
-		BorderForeground(lipgloss.Color("240")).
-		BorderBottom(true).
-		Bold(false)
-	s.Selected = s.Selected.
-		Foreground(lipgloss.Color("229")).
-		Background(lipgloss.Color("57")).
-		Bold(false)
-	t.SetStyles(s)
-
-	return &ResourceView{
+	theme := NewTheme(cfg)
+	t.SetStyles(theme.Table)
+
+	v := &ResourceView{
 		config:       cfg,
 		table:        t,
 		resourceType: k8s.ResourceTypeGitRepository,
+		keys:         NewKeyMap(cfg),
+		theme:        theme,
 	}
+	v.updateTableColumns()
+	return v
 }
 
 // Init initializes the resource view
@@ -67,76 +65,19 @@ func (v *ResourceView) Update(msg tea.Msg) (*ResourceView, tea.Cmd) {
 	
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		// Handle arrow keys by checking Type directly
-		switch msg.Type {
-		case tea.KeyDown:
-			v.table, cmd = v.table.Update(msg)
-		case tea.KeyUp:
-			v.table, cmd = v.table.Update(msg)
-		case tea.KeyLeft:
-			v.table, cmd = v.table.Update(msg)
-		case tea.KeyRight:
-			v.table, cmd = v.table.Update(msg)
-		case tea.KeyPgDown:
-			v.table, cmd = v.table.Update(msg)
-		case tea.KeyPgUp:
-			v.table, cmd = v.table.Update(msg)
-		case tea.KeyHome:
-			if len(v.resources) > 0 {
-				v.table.GotoTop()
-			}
-		case tea.KeyEnd:
-			if len(v.resources) > 0 {
-				v.table.GotoBottom()
+		if handled, navCmd := v.keys.navigate(&v.table.Model, msg, len(v.visible)); handled {
+			cmd = navCmd
+		} else if column, ok := v.sortKey(msg); ok {
+			desc := false
+			if column == v.sortColumn {
+				desc = !v.sortDesc
 			}
-		case tea.KeyEnter, tea.KeySpace:
+			v.SetSort(column, desc)
+		} else if key.Matches(msg, v.keys.Wide) {
+			v.SetWide(!v.wide)
+		} else if key.Matches(msg, v.keys.Select) {
 			// TODO: Show resource details
 			return v, nil
-		default:
-			// Handle string-based keys
-			switch msg.String() {
-			// Vertical navigation - j/k for vim users
-			case "j":
-				v.table, cmd = v.table.Update(tea.KeyMsg{Type: tea.KeyDown})
-			case "k":
-				v.table, cmd = v.table.Update(tea.KeyMsg{Type: tea.KeyUp})
-			
-			// Page navigation with vim-style shortcuts
-			case "ctrl+d":
-				v.table, cmd = v.table.Update(tea.KeyMsg{Type: tea.KeyPgDown})
-			case "ctrl+u":
-				v.table, cmd = v.table.Update(tea.KeyMsg{Type: tea.KeyPgUp})
-			
-			// Vim-style navigation
-			case "g":
-				// Go to top
-				if len(v.resources) > 0 {
-					v.table.GotoTop()
-				}
-			case "G":
-				// Go to bottom
-				if len(v.resources) > 0 {
-					v.table.GotoBottom()
-				}
-			case "H":
-				// Go to top of visible area
-				if len(v.resources) > 0 {
-					v.table.GotoTop()
-				}
-			case "M":
-				// Go to middle of visible area
-				if len(v.resources) > 0 {
-					middle := len(v.resources) / 2
-					for i := 0; i < middle; i++ {
-						v.table, _ = v.table.Update(tea.KeyMsg{Type: tea.KeyDown})
-					}
-				}
-			case "L":
-				// Go to bottom of visible area
-				if len(v.resources) > 0 {
-					v.table.GotoBottom()
-				}
-			}
 		}
 	}
 	
@@ -146,11 +87,12 @@ func (v *ResourceView) Update(msg tea.Msg) (*ResourceView, tea.Cmd) {
 // View renders the resource view
 func (v *ResourceView) View() string {
 	if len(v.resources) == 0 {
-		emptyMsg := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("244")).
-			Render(fmt.Sprintf("No %s resources found", v.resourceType))
+		emptyMsg := v.theme.Muted.Render(fmt.Sprintf("No %s resources found", v.resourceType))
 		return emptyMsg
 	}
+	if len(v.visible) == 0 {
+		return v.theme.Muted.Render(fmt.Sprintf("No %s resources are %s", v.resourceType, joinHealth(v.healthFilter)))
+	}
 	
 	return v.table.View()
 }
@@ -162,6 +104,73 @@ func (v *ResourceView) SetResources(resources []k8s.Resource) {
 	v.updateTable()
 }
 
+// SetHealthFilter shows only the resources in one of the given health
+// states, or every resource when none is given
+func (v *ResourceView) SetHealthFilter(states ...k8s.HealthState) {
+	v.healthFilter = states
+	v.updateTable()
+}
+
+// HealthFilter returns the health states the resources are filtered by
+func (v *ResourceView) HealthFilter() []k8s.HealthState {
+	return v.healthFilter
+}
+
+// SetSort sorts the rows by column, one of sortColumns, or restores API
+// order for an empty column
+func (v *ResourceView) SetSort(column string, desc bool) {
+	v.sortColumn, v.sortDesc = column, desc
+	v.table.SetColumns(v.tableColumns())
+	v.updateTable()
+}
+
+// Sort returns the sort column and whether the order is descending
+func (v *ResourceView) Sort() (string, bool) {
+	return v.sortColumn, v.sortDesc
+}
+
+// SetWide shows or hides the wide columns
+func (v *ResourceView) SetWide(wide bool) {
+	v.wide = wide
+	v.updateTableColumns()
+	v.updateTable()
+}
+
+// Wide reports whether the wide columns are shown
+func (v *ResourceView) Wide() bool {
+	return v.wide
+}
+
+// SetFocused sets whether the resource view is focused. Only a focused
+// view highlights the selected row.
+func (v *ResourceView) SetFocused(focused bool) {
+	v.table.Focus()
+	if !focused {
+		v.table.Blur()
+	}
+}
+
+// ResourceType returns the resource type shown
+func (v *ResourceView) ResourceType() k8s.ResourceType {
+	return v.resourceType
+}
+
+// sortKey returns the sort column of a sort key
+func (v *ResourceView) sortKey(msg tea.KeyMsg) (string, bool) {
+	for column, binding := range map[string]key.Binding{
+		"name":       v.keys.SortName,
+		"status":     v.keys.SortStatus,
+		"age":        v.keys.SortAge,
+		"transition": v.keys.SortTransition,
+		"cluster":    v.keys.SortCluster,
+	} {
+		if key.Matches(msg, binding) {
+			return column, true
+		}
+	}
+	return "", false
+}
+
 // SetResourceType sets the current resource type
 func (v *ResourceView) SetResourceType(resourceType k8s.ResourceType) {
 	v.resourceType = resourceType
@@ -169,145 +178,111 @@ func (v *ResourceView) SetResourceType(resourceType k8s.ResourceType) {
 	v.updateTable()
 }
 
+// SetPermission sets how to tell whether the user may suspend, resume and
+// reconcile a resource. Rows of resources they may not are greyed out.
+func (v *ResourceView) SetPermission(permission func(k8s.Resource) k8s.Permission) {
+	v.permission = permission
+	v.updateTable()
+}
+
+// OperationPermission tells whether the user may suspend, resume and
+// reconcile a resource
+func (v *ResourceView) OperationPermission(resource k8s.Resource) k8s.Permission {
+	if v.permission == nil {
+		return k8s.PermissionUnknown
+	}
+	return v.permission(resource)
+}
+
+// SetConfig applies a reloaded configuration, such as new column widths
+func (v *ResourceView) SetConfig(cfg *config.Config) {
+	v.config = cfg
+	v.keys = NewKeyMap(cfg)
+	v.theme = NewTheme(cfg)
+	v.table.SetStyles(v.theme.Table)
+	v.updateTableColumns()
+	v.updateTable()
+}
+
 // SetSize sets the view dimensions
 func (v *ResourceView) SetSize(width, height int) {
 	v.width = width
 	v.height = height
 	v.table.SetHeight(height - 2) // Reserve space for borders
 	v.updateTableColumns()
+	v.updateTable()
 }
 
 // updateTable updates the table with current resources
 func (v *ResourceView) updateTable() {
-	rows := make([]table.Row, 0, len(v.resources))
+	v.visible = slices.Clone(k8s.FilterByHealth(v.resources, v.healthFilter...))
+	v.sortResources(v.visible)
+	v.nsPrefix = v.config.UI.ShowNamespace && !slices.Contains(v.columnNames(), "namespace")
+
+	rows := make([]StyledRow, 0, len(v.visible))
 	
-	for _, resource := range v.resources {
+	for _, resource := range v.visible {
 		row := v.createTableRow(resource)
 		rows = append(rows, row)
 	}
 	
-	v.table.SetRows(rows)
+	v.table.SetStyledRows(rows)
 }
 
-// createTableRow creates a table row for a resource
-func (v *ResourceView) createTableRow(resource k8s.Resource) table.Row {
-	// Format name with namespace if shown
-	name := resource.Name
-	if v.config.UI.ShowNamespace && resource.Namespace != "" {
-		name = fmt.Sprintf("%s/%s", resource.Namespace, resource.Name)
-	}
-	
-	// Format ready status (plain text)
-	ready := "False"
-	if resource.Ready {
-		ready = "True"
-	}
-	
-	// Format status (plain text)
-	status := resource.Status
-	if status == "" {
-		status = "Unknown"
-	}
-	if resource.Suspended {
-		status = "Suspended"
-	}
-	
-	// Truncate status if too long
-	if len(status) > 12 {
-		status = status[:9] + "…"
-	}
-	
-	// Format age (plain text)
-	age := formatAge(resource.Age)
-	
-	// Format message (truncate if too long)
-	message := resource.Message
-	if len(message) > 35 {
-		message = message[:32] + "…"
+// createTableRow creates a table row for a resource. The Health and Status
+// cells are colored by the health of the resource, the Revision cell is
+// highlighted when the attempted revision was not applied and the whole row
+// is highlighted when it needs attention, or greyed out when the user may
+// not operate on it; the table truncates the cells.
+func (v *ResourceView) createTableRow(resource k8s.Resource) StyledRow {
+	texts := make([]string, len(v.columns))
+	for i, column := range v.columns {
+		texts[i] = column.value(v, resource)
 	}
 
-	// Resource-specific columns
-	switch v.resourceType {
-	case k8s.ResourceTypeGitRepository, k8s.ResourceTypeHelmRepository:
-		return table.Row{name, ready, status, age, message, resource.URL}
-	case k8s.ResourceTypeKustomization:
-		source := resource.Source
-		if resource.Path != "" {
-			source = fmt.Sprintf("%s/%s", source, resource.Path)
-		}
-		return table.Row{name, ready, status, age, message, source}
-	case k8s.ResourceTypeHelmRelease:
-		chart := resource.Chart
-		if resource.Version != "" {
-			chart = fmt.Sprintf("%s:%s", chart, resource.Version)
+	row := plainRow(texts...)
+	row.Style = v.theme.RowStyle(resource.Health)
+	if v.OperationPermission(resource) == k8s.PermissionDenied {
+		row.Style = v.theme.Muted
+	}
+	for i, column := range v.columns {
+		switch {
+		case column.name == "health" || column.name == "status":
+			row.Cells[i].Style = v.theme.HealthStyle(resource.Health)
+		case column.name == "revision" && resource.RevisionBehind():
+			row.Cells[i].Style = v.theme.Warning
 		}
-		return table.Row{name, ready, status, age, message, chart}
-	default:
-		return table.Row{name, ready, status, age, message}
 	}
+	return row
 }
 
-// updateTableColumns updates table columns based on resource type and width
+// updateTableColumns lays out the columns of the current resource type for
+// the view width
 func (v *ResourceView) updateTableColumns() {
-	baseColumns := []table.Column{
-		{Title: "Name", Width: v.config.UI.ColumnsName},
-		{Title: "Ready", Width: 8},
-		{Title: "Status", Width: v.config.UI.ColumnsStatus},
-		{Title: "Age", Width: 10},
-		{Title: "Message", Width: 35},
-	}
-
-	// Add resource-specific columns
-	switch v.resourceType {
-	case k8s.ResourceTypeGitRepository:
-		baseColumns = append(baseColumns, table.Column{Title: "URL", Width: 40})
-	case k8s.ResourceTypeHelmRepository:
-		baseColumns = append(baseColumns, table.Column{Title: "URL", Width: 40})
-	case k8s.ResourceTypeKustomization:
-		baseColumns = append(baseColumns, table.Column{Title: "Source/Path", Width: 30})
-	case k8s.ResourceTypeHelmRelease:
-		baseColumns = append(baseColumns, table.Column{Title: "Chart", Width: 25})
-	}
-
-	// Adjust column widths based on available space
-	if v.width > 0 {
-		totalFixedWidth := 0
-		flexColumns := 0
-		
-		for _, col := range baseColumns {
-			if col.Title == "Message" || col.Title == "URL" || col.Title == "Source/Path" {
-				flexColumns++
-			} else {
-				totalFixedWidth += col.Width
-			}
-		}
-		
-		if flexColumns > 0 {
-			availableWidth := v.width - totalFixedWidth - 10 // Reserve space for borders/padding
-			flexWidth := availableWidth / flexColumns
-			
-			if flexWidth > 20 { // Minimum width
-				for _, col := range baseColumns {
-					if col.Title == "Message" || col.Title == "URL" || col.Title == "Source/Path" {
-						col.Width = flexWidth
-					}
-				}
-			}
-		}
-	}
-
-	v.table.SetColumns(baseColumns)
+	v.columns = v.layoutColumns(v.columnNames(), v.width)
+	// Rows must match the new columns before the table renders them
+	v.table.SetStyledRows(nil)
+	v.table.SetColumns(v.tableColumns())
 }
 
 // GetSelectedResource returns the currently selected resource
 func (v *ResourceView) GetSelectedResource() *k8s.Resource {
 	cursor := v.table.Cursor()
-	if cursor >= 0 && cursor < len(v.resources) {
-		return &v.resources[cursor]
+	if cursor >= 0 && cursor < len(v.visible) {
+		return &v.visible[cursor]
 	}
 	return nil
 }
 
+// joinHealth lists health states for messages, such as "failed or stalled"
+func joinHealth(states []k8s.HealthState) string {
+	names := make([]string, len(states))
+	for i, state := range states {
+		names[i] = strings.ToLower(string(state))
+	}
+	return strings.Join(names, " or ")
+}
+
 // formatAge formats a duration as a human-readable age string
 func formatAge(d time.Duration) string {
 	if d < time.Minute {
@@ -133,3 +133,17 @@ func createTestResource(name, namespace string, resourceType k8s.ResourceType) k
 		Revision:  "main@sha256:abc123",
 	}
 }
+
+func TestResourceView_SetConfig(t *testing.T) {
+	cfg, err := config.Load("", "", "", "")
+	require.NoError(t, err)
+
+	rv := NewResourceView(cfg)
+	rv.SetSize(200, 30)
+
+	reloaded := *cfg
+	reloaded.UI.ColumnsName = 50
+	rv.SetConfig(&reloaded)
+
+	assert.Equal(t, 50, rv.table.Columns()[0].Width)
+}
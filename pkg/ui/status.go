@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/malagant/fluxcli/pkg/k8s"
+)
+
+// healthIcons are single-width so that they never shift the columns
+var healthIcons = map[k8s.HealthState]string{
+	k8s.HealthReady:       "✓",
+	k8s.HealthProgressing: "⟳",
+	k8s.HealthReconciling: "⟳",
+	k8s.HealthSuspended:   "∥",
+	k8s.HealthStalled:     "⊘",
+	k8s.HealthFailed:      "✗",
+	k8s.HealthUnknown:     "?",
+}
+
+// healthLabel returns the icon and name of a health state
+func healthLabel(health k8s.HealthState) string {
+	if health == "" {
+		health = k8s.HealthUnknown
+	}
+	return healthIcons[health] + " " + string(health)
+}
+
+// HealthStyle returns the style of a health or status cell
+func (t Theme) HealthStyle(health k8s.HealthState) lipgloss.Style {
+	switch health {
+	case k8s.HealthReady:
+		return t.Success
+	case k8s.HealthProgressing, k8s.HealthReconciling:
+		return t.Progress
+	case k8s.HealthStalled:
+		return t.Warning
+	case k8s.HealthFailed:
+		return t.Error
+	default:
+		return t.Muted
+	}
+}
+
+// RowStyle returns the style highlighting a whole row by severity. Failed
+// and stalled resources stand out, suspended ones fade.
+func (t Theme) RowStyle(health k8s.HealthState) lipgloss.Style {
+	switch health {
+	case k8s.HealthFailed:
+		return t.Error
+	case k8s.HealthStalled:
+		return t.Warning
+	case k8s.HealthSuspended:
+		return t.Muted
+	default:
+		return lipgloss.NewStyle()
+	}
+}
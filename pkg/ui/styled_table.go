@@ -0,0 +1,144 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Cell is a table cell rendered with a style. Its width is measured on the
+// plain text, so styling never shifts the columns.
+type Cell struct {
+	Text  string
+	Style lipgloss.Style
+}
+
+// StyledRow is a table row. Style applies to the cells that do not set a
+// style property themselves and is used to highlight rows by severity.
+type StyledRow struct {
+	Cells []Cell
+	Style lipgloss.Style
+}
+
+// plainRow creates a row of unstyled cells
+func plainRow(texts ...string) StyledRow {
+	cells := make([]Cell, len(texts))
+	for i, text := range texts {
+		cells[i] = Cell{Text: text, Style: lipgloss.NewStyle()}
+	}
+	return StyledRow{Cells: cells, Style: lipgloss.NewStyle()}
+}
+
+// styledTable renders a bubbles table with styled cells. The embedded table
+// keeps the cursor and handles navigation on the plain text of the rows,
+// while the visible rows are rendered here: the bubbles table truncates
+// cells by counting the bytes of escape sequences as characters, which cuts
+// them apart. Cells are truncated and padded on their plain text and only
+// then styled. The selected row of a focused table is rendered without cell
+// styles so that its highlight covers the whole row.
+type styledTable struct {
+	table.Model
+	rows   []StyledRow
+	styles table.Styles
+	offset int // First visible row
+}
+
+// newStyledTable creates a styled table from bubbles table options
+func newStyledTable(opts ...table.Option) styledTable {
+	return styledTable{Model: table.New(opts...), styles: table.DefaultStyles()}
+}
+
+// SetStyles sets the header, cell and selection styles
+func (t *styledTable) SetStyles(s table.Styles) {
+	t.styles = s
+	t.Model.SetStyles(s)
+}
+
+// SetStyledRows replaces the rows of the table
+func (t *styledTable) SetStyledRows(rows []StyledRow) {
+	t.rows = rows
+	plain := make([]table.Row, len(rows))
+	for i, row := range rows {
+		plain[i] = make(table.Row, len(row.Cells))
+		for j, cell := range row.Cells {
+			plain[i][j] = cell.Text
+		}
+	}
+	t.Model.SetRows(plain)
+}
+
+// Update handles navigation keys
+func (t styledTable) Update(msg tea.Msg) (styledTable, tea.Cmd) {
+	var cmd tea.Cmd
+	t.Model, cmd = t.Model.Update(msg)
+	return t, cmd
+}
+
+// View renders the header and the rows around the cursor. The scroll
+// offset only moves when the cursor leaves the visible rows.
+func (t *styledTable) View() string {
+	height := max(t.Model.Height(), 1)
+	cursor := t.Cursor()
+	if cursor < t.offset {
+		t.offset = cursor
+	}
+	if cursor >= t.offset+height {
+		t.offset = cursor - height + 1
+	}
+	t.offset = max(0, min(t.offset, len(t.rows)-height))
+
+	lines := make([]string, 0, height+1)
+	lines = append(lines, t.headerView())
+	for i := t.offset; i < len(t.rows) && i < t.offset+height; i++ {
+		lines = append(lines, t.rowView(t.rows[i], t.Focused() && i == cursor))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// headerView renders the column titles
+func (t *styledTable) headerView() string {
+	titles := make([]string, 0, len(t.Columns()))
+	for _, column := range t.Columns() {
+		if column.Width <= 0 {
+			continue
+		}
+		titles = append(titles, t.styles.Header.Render(fitWidth(column.Title, column.Width)))
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, titles...)
+}
+
+// rowView renders a single row
+func (t *styledTable) rowView(row StyledRow, selected bool) string {
+	cells := make([]string, 0, len(t.Columns()))
+	for i, column := range t.Columns() {
+		if column.Width <= 0 {
+			continue
+		}
+		var cell Cell
+		if i < len(row.Cells) {
+			cell = row.Cells[i]
+		}
+
+		text := ansi.Truncate(cell.Text, column.Width, "…")
+		padding := strings.Repeat(" ", column.Width-ansi.StringWidth(text))
+		if !selected {
+			text = cell.Style.Inherit(row.Style).Render(text)
+		}
+		cells = append(cells, t.styles.Cell.Render(text+padding))
+	}
+
+	line := strings.Join(cells, "")
+	if selected {
+		return t.styles.Selected.Render(line)
+	}
+	return line
+}
+
+// fitWidth truncates or pads plain text to exactly width cells
+func fitWidth(text string, width int) string {
+	text = ansi.Truncate(text, width, "…")
+	return text + strings.Repeat(" ", width-ansi.StringWidth(text))
+}
@@ -0,0 +1,120 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/malagant/fluxcli/internal/config"
+	"github.com/malagant/fluxcli/pkg/k8s"
+)
+
+// withColors forces ANSI colors for the duration of a test
+func withColors(t *testing.T) {
+	profile := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.ANSI256)
+	t.Cleanup(func() { lipgloss.SetColorProfile(profile) })
+}
+
+func TestStyledTableAlignment(t *testing.T) {
+	withColors(t)
+
+	tbl := newStyledTable(
+		table.WithColumns([]table.Column{{Title: "Name", Width: 6}, {Title: "Status", Width: 8}, {Title: "Message", Width: 10}}),
+		table.WithHeight(5),
+		table.WithFocused(true),
+	)
+	red := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+
+	row := plainRow("failing", "✗ Failed", "reconciliation failed")
+	row.Style = red
+	row.Cells[1].Style = red.Bold(true)
+	tbl.SetStyledRows([]StyledRow{plainRow("ok", "✓ Ready", "applied"), row, plainRow("日本語の名前", "? Unknown", "")})
+
+	lines := strings.Split(tbl.View(), "\n")
+	require.Len(t, lines, 4) // Header and three rows
+	for _, line := range lines[1:] {
+		assert.Equal(t, ansi.StringWidth(lines[0]), ansi.StringWidth(line), "%q", line)
+	}
+
+	plain := strings.Split(ansi.Strip(tbl.View()), "\n")
+	assert.Contains(t, plain[1], "ok      ✓ Ready   applied")
+	assert.Contains(t, plain[2], "faili…  ✗ Failed  reconcili…")
+	assert.Contains(t, plain[3], "日本…   ? Unkno…")
+
+	// The highlight of the selected row replaces the cell styles
+	assert.Contains(t, lines[2], red.Render("faili…"))
+	tbl.MoveDown(1)
+	lines = strings.Split(tbl.View(), "\n")
+	assert.NotContains(t, lines[2], "38;5;196")
+	assert.Equal(t, plain[2], ansi.Strip(lines[2]))
+
+	// Only a focused table highlights the selected row
+	tbl.Blur()
+	lines = strings.Split(tbl.View(), "\n")
+	assert.Contains(t, lines[2], red.Render("faili…"))
+}
+
+func TestStyledTableScrolling(t *testing.T) {
+	tbl := newStyledTable(
+		table.WithColumns([]table.Column{{Title: "Name", Width: 4}}),
+		table.WithHeight(3), // Two rows below the header
+	)
+	tbl.SetStyledRows([]StyledRow{plainRow("a"), plainRow("b"), plainRow("c"), plainRow("d")})
+
+	rows := func() []string {
+		lines := strings.Split(ansi.Strip(tbl.View()), "\n")[1:]
+		for i := range lines {
+			lines[i] = strings.TrimSpace(lines[i])
+		}
+		return lines
+	}
+
+	assert.Equal(t, []string{"a", "b"}, rows())
+	tbl.MoveDown(2)
+	assert.Equal(t, []string{"b", "c"}, rows())
+	tbl.MoveUp(1)
+	assert.Equal(t, []string{"b", "c"}, rows())
+	tbl.GotoBottom()
+	assert.Equal(t, []string{"c", "d"}, rows())
+	tbl.GotoTop()
+	assert.Equal(t, []string{"a", "b"}, rows())
+}
+
+func TestResourceView_StatusCells(t *testing.T) {
+	withColors(t)
+
+	cfg, err := config.Load("", "", "", "")
+	require.NoError(t, err)
+
+	rv := NewResourceView(cfg)
+	rv.SetSize(160, 10)
+	rv.SetResources([]k8s.Resource{
+		{Name: "healthy", Ready: true, Health: k8s.HealthReady, Status: "Succeeded"},
+		{Name: "broken", Health: k8s.HealthFailed, Status: "GitOperationFailed", Message: "authentication required"},
+		{Name: "paused", Health: k8s.HealthSuspended},
+	})
+
+	lines := strings.Split(rv.View(), "\n")
+	require.Len(t, lines, 5)
+	assert.Equal(t, ansi.StringWidth(lines[2]), ansi.StringWidth(lines[3]))
+	assert.Contains(t, ansi.Strip(lines[2]), "✓ Ready")
+	assert.Contains(t, ansi.Strip(lines[3]), "✗ Failed")
+	assert.Contains(t, lines[3], rv.theme.Error.Render("broken"))
+	assert.Contains(t, lines[4], rv.theme.Muted.Render("paused"))
+
+	rv.SetHealthFilter(k8s.HealthFailed, k8s.HealthStalled)
+	require.NotNil(t, rv.GetSelectedResource())
+	assert.Equal(t, "broken", rv.GetSelectedResource().Name)
+	assert.Len(t, strings.Split(rv.View(), "\n"), 3)
+
+	rv.SetHealthFilter(k8s.HealthStalled)
+	assert.Nil(t, rv.GetSelectedResource())
+	assert.Equal(t, "No GitRepository resources are stalled", ansi.Strip(rv.View()))
+}
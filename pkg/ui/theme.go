@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"os"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/malagant/fluxcli/internal/config"
+	"github.com/malagant/fluxcli/pkg/core"
+)
+
+// Theme holds the styles of the UI, built from the palette of the configured
+// theme. With NO_COLOR set every color is dropped and the selected row is
+// shown in reverse video instead.
+type Theme struct {
+	Title     lipgloss.Style
+	Cluster   lipgloss.Style
+	Resource  lipgloss.Style
+	Namespace lipgloss.Style
+	Prompt    lipgloss.Style
+	Success   lipgloss.Style
+	Warning   lipgloss.Style
+	Progress  lipgloss.Style
+	Error     lipgloss.Style
+	Muted     lipgloss.Style
+	Hint      lipgloss.Style
+	Border    lipgloss.TerminalColor
+	Table     table.Styles
+
+	noColor bool
+}
+
+// NewTheme creates the styles of the theme selected in cfg
+func NewTheme(cfg *config.Config) Theme {
+	palette := cfg.Palette()
+	noColor := os.Getenv("NO_COLOR") != ""
+
+	color := func(c string) lipgloss.TerminalColor {
+		if noColor {
+			return lipgloss.NoColor{}
+		}
+		return lipgloss.Color(c)
+	}
+	foreground := func(c string) lipgloss.Style {
+		return lipgloss.NewStyle().Foreground(color(c))
+	}
+
+	theme := Theme{
+		Title:     foreground(palette.Title).Bold(true),
+		Cluster:   foreground(palette.Cluster).Bold(true),
+		Resource:  foreground(palette.Resource).Bold(true),
+		Namespace: foreground(palette.Namespace).Bold(true),
+		Prompt:    foreground(palette.Error).Bold(true),
+		Success:   foreground(palette.Success),
+		Warning:   foreground(palette.Warning),
+		Progress:  foreground(palette.Progressing),
+		Error:     foreground(palette.Error),
+		Muted:     foreground(palette.Muted),
+		Hint:      foreground(palette.Border),
+		Border:    color(palette.Border),
+		noColor:   noColor,
+	}
+
+	theme.Table = table.DefaultStyles()
+	theme.Table.Header = theme.Table.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(theme.Border).
+		BorderBottom(true).
+		Bold(false)
+	theme.Table.Selected = theme.Table.Selected.
+		Foreground(color(palette.SelectedForeground)).
+		Background(color(palette.SelectedBackground)).
+		Bold(false)
+	if noColor {
+		theme.Table.Selected = theme.Table.Selected.Reverse(true)
+	}
+
+	return theme
+}
+
+// ClusterStyle returns the header style of a cluster, using the color
+// configured for the cluster when it has one. cluster may be nil.
+func (t Theme) ClusterStyle(cluster *config.ClusterConfig) lipgloss.Style {
+	if cluster == nil || cluster.Color == "" || t.noColor {
+		return t.Cluster
+	}
+	return t.Cluster.Foreground(lipgloss.Color(cluster.Color))
+}
+
+// ConnectionStyle returns the style of a connection state indicator
+func (t Theme) ConnectionStyle(state core.ConnectionState) lipgloss.Style {
+	switch state {
+	case core.ConnectionConnected:
+		return t.Success
+	case core.ConnectionConnecting, core.ConnectionDegraded:
+		return t.Warning
+	default:
+		return t.Error
+	}
+}
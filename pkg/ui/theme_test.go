@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/malagant/fluxcli/internal/config"
+)
+
+func TestNewTheme(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	cfg, err := config.Load("", "", "", "")
+	require.NoError(t, err)
+
+	cfg.UI.Theme = "light"
+	theme := NewTheme(cfg)
+	assert.Equal(t, lipgloss.Color(config.BuiltinThemes["light"].Title), theme.Title.GetForeground())
+	assert.Equal(t, lipgloss.Color(config.BuiltinThemes["light"].SelectedBackground), theme.Table.Selected.GetBackground())
+
+	cfg.UI.Themes = map[string]config.ThemeConfig{"mine": {Base: "high-contrast", Error: "#ff0000"}}
+	cfg.UI.Theme = "mine"
+	theme = NewTheme(cfg)
+	assert.Equal(t, lipgloss.Color("#ff0000"), theme.Error.GetForeground())
+	assert.Equal(t, lipgloss.Color(config.BuiltinThemes["high-contrast"].Success), theme.Success.GetForeground())
+
+	// Clusters with a color of their own keep it in the header
+	assert.Equal(t, lipgloss.Color("212"), theme.ClusterStyle(&config.ClusterConfig{Color: "212"}).GetForeground())
+	assert.Equal(t, theme.Cluster.GetForeground(), theme.ClusterStyle(nil).GetForeground())
+}
+
+func TestNewThemeNoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	cfg, err := config.Load("", "", "", "")
+	require.NoError(t, err)
+
+	theme := NewTheme(cfg)
+	assert.Equal(t, lipgloss.NoColor{}, theme.Title.GetForeground())
+	assert.Equal(t, lipgloss.NoColor{}, theme.Table.Selected.GetBackground())
+	assert.True(t, theme.Table.Selected.GetReverse(), "the selected row stays visible without colors")
+	assert.Equal(t, lipgloss.NoColor{}, theme.ClusterStyle(&config.ClusterConfig{Color: "212"}).GetForeground())
+}
+
+func TestResourceView_SetConfigTheme(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	cfg, err := config.Load("", "", "", "")
+	require.NoError(t, err)
+
+	rv := NewResourceView(cfg)
+	reloaded := *cfg
+	reloaded.UI.Theme = "colorblind"
+	rv.SetConfig(&reloaded)
+
+	assert.Equal(t, lipgloss.Color(config.BuiltinThemes["colorblind"].SelectedBackground), rv.theme.Table.Selected.GetBackground())
+}